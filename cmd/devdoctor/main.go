@@ -4,9 +4,11 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"path/filepath"
 	"runtime"
 
 	"github.com/Sw3bbl3/devdoctor/internal/checker"
+	"github.com/Sw3bbl3/devdoctor/internal/config"
 	"github.com/Sw3bbl3/devdoctor/internal/detector"
 	"github.com/Sw3bbl3/devdoctor/internal/reporter"
 	"github.com/Sw3bbl3/devdoctor/internal/updater"
@@ -23,7 +25,11 @@ func main() {
 	       var update bool
 	       var checkUpdate bool
 	       var showHelp bool
+	       var recursive bool
+	       var depth int
 	       flag.StringVar(&path, "path", ".", "Path to the project directory to diagnose")
+	       flag.BoolVar(&recursive, "recursive", false, "Scan subdirectories for nested projects (monorepos)")
+	       flag.IntVar(&depth, "depth", 3, "Maximum directory depth for -recursive")
 	       flag.BoolVar(&showVersion, "version", false, "Print DevDoctor version")
 	       flag.BoolVar(&update, "update", false, "Update DevDoctor to the latest release")
 	       flag.BoolVar(&checkUpdate, "check-update", false, "Check if a newer version is available")
@@ -33,23 +39,26 @@ func main() {
 			       fmt.Println("\033[1;36m╔═══════════════════════════════════════════════════════════════╗\033[0m")
 			       fmt.Println("\033[1;36m║                         DEVDOCTOR                            ║\033[0m")
 			       fmt.Println("\033[1;36m║              Project Diagnostic CLI Tool                     ║\033[0m")
-			       fmt.Println("\033[1;36m╚═══════════════════════════════════════════════════════════════╝\033[0m\n")
+			       fmt.Print("\033[1;36m╚═══════════════════════════════════════════════════════════════╝\033[0m\n\n")
 			       fmt.Println("Usage:")
-			       fmt.Println("  devdoctor [options]\n")
+			       fmt.Print("  devdoctor [options]\n\n")
 			       fmt.Println("Options:")
 			       fmt.Println("  -path           Path to the project directory to diagnose (default: .)")
+			       fmt.Println("  -recursive      Scan subdirectories for nested projects (monorepos)")
+			       fmt.Println("  -depth          Maximum directory depth for -recursive (default: 3)")
 			       fmt.Println("  -version        Print DevDoctor version")
 			       fmt.Println("  -check-update   Check if a newer version is available")
 			       fmt.Println("  -update         Update DevDoctor to the latest release")
-			       fmt.Println("  -help           Show this help message\n")
+			       fmt.Print("  -help           Show this help message\n\n")
 			       fmt.Println("Examples:")
 			       fmt.Println("  devdoctor")
 			       fmt.Println("  devdoctor -path /path/to/project")
+			       fmt.Println("  devdoctor -recursive -depth 4")
 			       fmt.Println("  devdoctor -check-update")
-			       fmt.Println("  devdoctor -update\n")
+			       fmt.Print("  devdoctor -update\n\n")
 			       fmt.Println("Supported Project Types:")
-			       fmt.Println("  Node.js, Python, Go, Java, Ruby, Rust, .NET, Docker\n")
-			       fmt.Println("For more info, see: https://github.com/Sw3bbl3/devdoctor\n")
+			       fmt.Print("  Node.js, Python, Go, Java, Ruby, Rust, .NET, Docker\n\n")
+			       fmt.Print("For more info, see: https://github.com/Sw3bbl3/devdoctor\n\n")
 		       }
 
 	       flag.Parse()
@@ -133,9 +142,25 @@ func main() {
 		absPath = path
 	}
 
+	// Load custom project types from the devdoctor config file
+	cfg, cfgFile, err := config.Load(absPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+		os.Exit(1)
+	}
+
 	// Detect project types
 	detectors := detector.NewDetectorRegistry()
-	detectedProjects := detectors.Detect(absPath)
+	if cfg != nil {
+		fmt.Printf("Using config: %s\n\n", cfgFile)
+		detectors.AddProjectTypes(cfg.ProjectTypes)
+	}
+	var detectedProjects []*detector.ProjectType
+	if recursive {
+		detectedProjects = detectors.DetectRecursive(absPath, depth)
+	} else {
+		detectedProjects = detectors.Detect(absPath)
+	}
 
 	if len(detectedProjects) == 0 {
 		fmt.Println("No supported project types detected in", absPath)
@@ -147,13 +172,25 @@ func main() {
 		fmt.Println("  - Ruby (Gemfile)")
 		fmt.Println("  - Rust (Cargo.toml)")
 		fmt.Println("  - .NET (*.csproj, *.sln)")
+		if !recursive {
+			fmt.Println("\nFor monorepos, use -recursive to scan subdirectories.")
+		}
 		os.Exit(0)
 	}
 
+	// Check the git repository the directory belongs to
+	allIssues := checker.CheckGit(absPath)
+
 	// Run checks for each detected project type
-	allIssues := []checker.Issue{}
+	checkedRoots := map[string]bool{}
 	for _, project := range detectedProjects {
-		issues := checker.CheckProject(absPath, project)
+		projectPath := filepath.Join(absPath, project.Root)
+		// Directory-wide checks run once per sub-project root
+		if !checkedRoots[project.Root] {
+			checkedRoots[project.Root] = true
+			allIssues = append(allIssues, checker.CheckRoot(projectPath, project.Root)...)
+		}
+		issues := checker.CheckProject(projectPath, project)
 		allIssues = append(allIssues, issues...)
 	}
 
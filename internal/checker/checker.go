@@ -9,6 +9,7 @@ import (
 	"strings"
 
 	"github.com/Sw3bbl3/devdoctor/internal/detector"
+	"github.com/Sw3bbl3/devdoctor/internal/envcheck"
 )
 
 // Severity levels for issues
@@ -24,6 +25,7 @@ const (
 type Issue struct {
 	Severity    Severity
 	ProjectType string
+	Root        string // sub-project directory relative to the scan root
 	Message     string
 	Suggestion  string
 }
@@ -62,6 +64,45 @@ func CheckProject(path string, project *detector.ProjectType) []Issue {
 		issues = append(issues, checkDotNet(path)...)
 	case "Docker":
 		issues = append(issues, checkDocker(path)...)
+	case "PHP":
+		issues = append(issues, checkPHP(path)...)
+	case "Swift":
+		issues = append(issues, checkSwift(path)...)
+	case "Kotlin":
+		issues = append(issues, checkKotlin(path)...)
+	case "Elixir":
+		issues = append(issues, checkElixir(path)...)
+	case "Haskell":
+		issues = append(issues, checkHaskell(path)...)
+	case "Scala":
+		issues = append(issues, checkScala(path)...)
+	case "Dart/Flutter":
+		issues = append(issues, checkDart(path)...)
+	case "C++":
+		issues = append(issues, checkNative(path, "C++")...)
+	case "C":
+		// Mixed C and C++ projects are checked once, as C++
+		sources := project.Sources
+		if sources == nil {
+			inspected := detector.InspectNativeSources(path)
+			sources = &inspected
+		}
+		if !sources.Uses("CXX") {
+			issues = append(issues, checkNative(path, "C")...)
+		}
+	}
+
+	// Check the system libraries native dependencies build against
+	issues = append(issues, checkSystemLibraries(path, project)...)
+
+	// Check the environment variables the code reads are defined
+	issues = append(issues, checkEnvReads(path, project)...)
+
+	// Run checks declared in the devdoctor config file
+	issues = append(issues, checkCustom(path, project)...)
+
+	for i := range issues {
+		issues[i].Root = project.Root
 	}
 
 	return issues
@@ -77,22 +118,55 @@ func fileExists(path, filename string) bool {
 	return err == nil
 }
 
+func pathExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
 func getInstallSuggestion(tool string) string {
 	suggestions := map[string]string{
-		"node":   "Install Node.js from https://nodejs.org/ or use a version manager like nvm",
-		"npm":    "npm is included with Node.js. Install from https://nodejs.org/",
-		"python": "Install Python from https://python.org/ or use pyenv for version management",
-		"pip":    "pip is included with Python 3.4+. Reinstall Python or install pip separately",
-		"go":     "Install Go from https://golang.org/dl/",
-		"java":   "Install Java JDK from https://adoptium.net/ or your system package manager",
-		"mvn":    "Install Maven from https://maven.apache.org/ or use your system package manager",
-		"gradle": "Install Gradle from https://gradle.org/ or use the gradle wrapper (./gradlew)",
-		"ruby":   "Install Ruby from https://www.ruby-lang.org/ or use rbenv/rvm",
-		"bundle": "Install bundler with: gem install bundler",
-		"cargo":  "Install Rust from https://rustup.rs/",
-		"rustc":  "Install Rust from https://rustup.rs/",
-		"dotnet": "Install .NET SDK from https://dotnet.microsoft.com/download",
-		"docker": "Install Docker from https://docs.docker.com/get-docker/",
+		"node":     "Install Node.js from https://nodejs.org/ or use a version manager like nvm",
+		"npm":      "npm is included with Node.js. Install from https://nodejs.org/",
+		"yarn":     "Run 'corepack enable' (Node.js 16.9+) or install with: npm install -g yarn",
+		"pnpm":     "Run 'corepack enable' (Node.js 16.9+) or install with: npm install -g pnpm",
+		"bun":      "Install Bun from https://bun.sh/",
+		"python":   "Install Python from https://python.org/ or use pyenv for version management",
+		"pip":      "pip is included with Python 3.4+. Reinstall Python or install pip separately",
+		"poetry":   "Install Poetry with: pipx install poetry (see https://python-poetry.org/docs/)",
+		"pipenv":   "Install Pipenv with: pipx install pipenv",
+		"uv":       "Install uv from https://docs.astral.sh/uv/getting-started/installation/",
+		"hatch":    "Install Hatch with: pipx install hatch",
+		"conda":    "Install Miniconda from https://docs.conda.io/en/latest/miniconda.html or Miniforge",
+		"go":       "Install Go from https://golang.org/dl/",
+		"java":     "Install Java JDK from https://adoptium.net/ or your system package manager",
+		"mvn":      "Install Maven from https://maven.apache.org/ or use your system package manager",
+		"gradle":   "Install Gradle from https://gradle.org/ or use the gradle wrapper (./gradlew)",
+		"ruby":     "Install Ruby from https://www.ruby-lang.org/ or use rbenv/rvm",
+		"bundle":   "Install bundler with: gem install bundler",
+		"cargo":    "Install Rust from https://rustup.rs/",
+		"rustc":    "Install Rust from https://rustup.rs/",
+		"dotnet":   "Install .NET SDK from https://dotnet.microsoft.com/download",
+		"docker":   "Install Docker from https://docs.docker.com/get-docker/",
+		"php":      "Install PHP from https://www.php.net/downloads or your system package manager",
+		"composer": "Install Composer from https://getcomposer.org/download/",
+		"swift":    "Install Swift from https://www.swift.org/install/ (on macOS it comes with Xcode)",
+		"kotlin":   "Install Kotlin with 'sdk install kotlin' or from https://kotlinlang.org/docs/command-line.html",
+		"elixir":   "Install Elixir from https://elixir-lang.org/install.html or with asdf",
+		"mix":      "mix is included with Elixir. Install from https://elixir-lang.org/install.html",
+		"ghc":      "Install GHC with ghcup from https://www.haskell.org/ghcup/",
+		"stack":    "Install Stack with ghcup or from https://docs.haskellstack.org/",
+		"cabal":    "Install cabal with ghcup from https://www.haskell.org/ghcup/",
+		"scala":    "Install Scala with Coursier ('cs setup') from https://www.scala-lang.org/download/",
+		"sbt":      "Install sbt with Coursier ('cs setup') or from https://www.scala-sbt.org/download/",
+		"dart":     "Install the Dart SDK from https://dart.dev/get-dart (it is included with Flutter)",
+		"flutter":  "Install Flutter from https://docs.flutter.dev/get-started/install",
+		"gcc":      "Install a C compiler (e.g. 'apt install build-essential', 'xcode-select --install' or Visual Studio Build Tools)",
+		"g++":      "Install a C++ compiler (e.g. 'apt install build-essential', 'xcode-select --install' or Visual Studio Build Tools)",
+		"make":     "Install make (e.g. 'apt install build-essential' or 'xcode-select --install')",
+		"cmake":    "Install CMake from https://cmake.org/download/ or with 'pip install cmake'",
+		"meson":    "Install Meson with 'pip install meson'",
+		"ninja":    "Install Ninja (e.g. 'apt install ninja-build', 'brew install ninja' or 'pip install ninja')",
+		"conan":    "Install Conan with 'pip install conan'",
 	}
 	if suggestion, ok := suggestions[tool]; ok {
 		return suggestion
@@ -102,64 +176,74 @@ func getInstallSuggestion(tool string) string {
 
 func checkNodeJS(path string) []Issue {
 	issues := []Issue{}
+	pm := detector.DetectNodePackageManager(path)
 
-	// Check if node_modules exists
+	var packageJSON map[string]interface{}
+	if data, err := os.ReadFile(filepath.Join(path, "package.json")); err == nil {
+		json.Unmarshal(data, &packageJSON)
+	}
+
+	// Check if node_modules exists; Yarn Plug'n'Play installs do without it
 	nodeModulesPath := filepath.Join(path, "node_modules")
-	if _, err := os.Stat(nodeModulesPath); os.IsNotExist(err) {
+	if _, err := os.Stat(nodeModulesPath); os.IsNotExist(err) && !isYarnPnP(path) {
 		issues = append(issues, Issue{
 			Severity:    SeverityWarning,
 			ProjectType: "Node.js",
 			Message:     "Dependencies not installed (node_modules directory not found)",
-			Suggestion:  "Run 'npm install' or 'yarn install' to install dependencies",
+			Suggestion:  fmt.Sprintf("Run '%s' to install dependencies", nodeInstallCommand(pm)),
 		})
 	}
 
-	// Check package.json for engines
-	packageJSONPath := filepath.Join(path, "package.json")
-	data, err := os.ReadFile(packageJSONPath)
-	if err == nil {
-		var packageJSON map[string]interface{}
-		if json.Unmarshal(data, &packageJSON) == nil {
-			if engines, ok := packageJSON["engines"].(map[string]interface{}); ok {
-				if nodeVersion, ok := engines["node"].(string); ok {
-					issues = append(issues, Issue{
-						Severity:    SeverityInfo,
-						ProjectType: "Node.js",
-						Message:     fmt.Sprintf("Project requires Node.js version: %s", nodeVersion),
-						Suggestion:  "Verify your Node.js version matches the requirement using 'node --version'",
-					})
-				}
-			}
-		}
+	// Check lockfiles and the package manager version
+	issues = append(issues, checkNodePackageManager(path, pm)...)
+
+	// Check package.json, the lockfile and node_modules agree
+	issues = append(issues, checkNodeLockfile(path, pm, packageJSON)...)
+
+	// Check package.json engines against the installed node and npm
+	if engines, ok := packageJSON["engines"].(map[string]interface{}); ok {
+		issues = append(issues, checkNodeEngines(path, engines)...)
 	}
 
+	// Check native addons were built for the installed node
+	issues = append(issues, checkNodeABI(path, pm)...)
+
 	return issues
 }
 
 func checkPython(path string) []Issue {
 	issues := []Issue{}
-
-	// Check for virtual environment
-	venvDirs := []string{"venv", ".venv", "env", ".env"}
-	venvExists := false
-	for _, dir := range venvDirs {
-		if _, err := os.Stat(filepath.Join(path, dir)); err == nil {
-			venvExists = true
-			break
+	tc := detector.DetectPythonToolchain(path)
+
+	// Check that the environment of the project's toolchain exists. A Conda
+	// environment file without a name cannot be looked up.
+	env := findPythonEnv(path, tc)
+	if env == "" && (tc.Manager != "conda" || tc.CondaEnv != "") {
+		message := "No virtual environment detected"
+		suggestion := "Create a virtual environment with 'python -m venv venv' and activate it"
+		if tc.Manager == "conda" {
+			message = fmt.Sprintf("Conda environment '%s' not found", tc.CondaEnv)
+		} else if tc.Manager != "pip" {
+			message = fmt.Sprintf("No %s virtual environment found for this project", tc.Manager)
+		}
+		if tc.Manager != "pip" {
+			suggestion = fmt.Sprintf("Create it with '%s'", pythonEnvSetup(tc))
 		}
-	}
-
-	if !venvExists {
 		issues = append(issues, Issue{
 			Severity:    SeverityWarning,
 			ProjectType: "Python",
-			Message:     "No virtual environment detected",
-			Suggestion:  "Create a virtual environment with 'python -m venv venv' and activate it",
+			Message:     message,
+			Suggestion:  suggestion,
 		})
 	}
 
-	// Check if requirements are installed
-	if fileExists(path, "requirements.txt") {
+	// Check the environment still works and holds the required packages
+	if env != "" {
+		issues = append(issues, checkPythonEnv(path, tc, env)...)
+		if fileExists(path, "requirements.txt") {
+			issues = append(issues, checkRequirements(path, tc, env)...)
+		}
+	} else if fileExists(path, "requirements.txt") {
 		issues = append(issues, Issue{
 			Severity:    SeverityInfo,
 			ProjectType: "Python",
@@ -174,8 +258,41 @@ func checkPython(path string) []Issue {
 func checkGo(path string) []Issue {
 	issues := []Issue{}
 
+	var mod *goModFile
+	if data, err := os.ReadFile(filepath.Join(path, "go.mod")); err == nil {
+		mod = parseGoMod(data)
+	}
+
+	// Check the workspace this module belongs to; its go and toolchain
+	// lines take precedence over go.mod
+	toolchainFile, toolchainMod := "go.mod", mod
+	workFile := findGoWork(path)
+	if workFile != "" {
+		workIssues, work := checkGoWork(path, workFile, mod)
+		issues = append(issues, workIssues...)
+		if work != nil {
+			toolchainFile, toolchainMod = "go.work", work
+		}
+	}
+
+	// Check the go and toolchain directives against the installed Go
+	env := goEnv("GOROOT", "GOENV", "GOMODCACHE")
+	local := ""
+	if status := envcheck.Lookup("go"); status.Found {
+		local = status.Version
+	}
+	issues = append(issues, checkGoToolchain(toolchainFile, toolchainMod, local, goToolchainSetting(env))...)
+
+	if mod == nil {
+		if workFile != "" {
+			return issues
+		}
+		mod = &goModFile{}
+	}
+
 	// Check if go.sum exists
-	if _, err := os.Stat(filepath.Join(path, "go.sum")); os.IsNotExist(err) {
+	goSum, err := os.ReadFile(filepath.Join(path, "go.sum"))
+	if os.IsNotExist(err) && (len(mod.Require) > 0 || mod.Module == "") {
 		issues = append(issues, Issue{
 			Severity:    SeverityWarning,
 			ProjectType: "Go",
@@ -192,6 +309,28 @@ func checkGo(path string) []Issue {
 			Message:     "Using vendored dependencies",
 			Suggestion:  "Dependencies are vendored. Run 'go mod vendor' to update if needed",
 		})
+		return append(issues, checkGoVendor(path, mod)...)
+	}
+
+	// Check every required module in go.sum has been downloaded
+	if len(goSum) == 0 || env["GOMODCACHE"] == "" {
+		return issues
+	}
+	if !pathExists(env["GOMODCACHE"]) {
+		return append(issues, Issue{
+			Severity:    SeverityWarning,
+			ProjectType: "Go",
+			Message:     fmt.Sprintf("Module cache %s does not exist - dependencies are not downloaded", env["GOMODCACHE"]),
+			Suggestion:  "Run 'go mod download' to download dependencies",
+		})
+	}
+	if missing := missingGoModules(goSum, mod.Require, env["GOMODCACHE"]); len(missing) > 0 {
+		issues = append(issues, Issue{
+			Severity:    SeverityWarning,
+			ProjectType: "Go",
+			Message:     fmt.Sprintf("%d modules required by go.mod are not in the module cache: %s", len(missing), strings.Join(missing, ", ")),
+			Suggestion:  "Run 'go mod download' to download them",
+		})
 	}
 
 	return issues
@@ -199,28 +338,46 @@ func checkGo(path string) []Issue {
 
 func checkJava(path string) []Issue {
 	issues := []Issue{}
+	mavenWrapper := detector.DetectMavenWrapper(path)
+	gradleWrapper := detector.DetectGradleWrapper(path)
 
 	// Check for Maven
 	if _, err := os.Stat(filepath.Join(path, "pom.xml")); err == nil {
+		mvn := "mvn"
+		if mavenWrapper != nil {
+			mvn = "./mvnw"
+			issues = append(issues, checkBuildWrapper(path, "Java", mavenWrapper)...)
+		}
+		// Check the compiler levels against the JDK
+		jdk, jdkSource := installedJDK(path)
+		issues = append(issues, checkJavaLevels(mavenJavaLevels(path), jdk, jdkSource)...)
+
 		// Check if .m2 or target exists
 		if _, err := os.Stat(filepath.Join(path, "target")); os.IsNotExist(err) {
 			issues = append(issues, Issue{
 				Severity:    SeverityWarning,
 				ProjectType: "Java",
 				Message:     "Maven project not built (target directory not found)",
-				Suggestion:  "Run 'mvn install' or 'mvn package' to build the project",
+				Suggestion:  fmt.Sprintf("Run '%s install' or '%s package' to build the project", mvn, mvn),
 			})
 		}
 	}
 
 	// Check for Gradle
+	if fileExists(path, "build.gradle") || fileExists(path, "build.gradle.kts") {
+		issues = append(issues, checkGradle(path, gradleWrapper)...)
+	}
 	if _, err := os.Stat(filepath.Join(path, "build.gradle")); err == nil {
 		if _, err := os.Stat(filepath.Join(path, "build")); os.IsNotExist(err) {
+			suggestion := "Run 'gradle build' or './gradlew build' to build the project"
+			if gradleWrapper != nil {
+				suggestion = "Run './gradlew build' to build the project"
+			}
 			issues = append(issues, Issue{
 				Severity:    SeverityWarning,
 				ProjectType: "Java",
 				Message:     "Gradle project not built (build directory not found)",
-				Suggestion:  "Run 'gradle build' or './gradlew build' to build the project",
+				Suggestion:  suggestion,
 			})
 		}
 	}
@@ -232,13 +389,21 @@ func checkRuby(path string) []Issue {
 	issues := []Issue{}
 
 	// Check if Gemfile.lock exists
-	if _, err := os.Stat(filepath.Join(path, "Gemfile.lock")); os.IsNotExist(err) {
+	var lock *gemLockfile
+	if data, err := os.ReadFile(filepath.Join(path, "Gemfile.lock")); err != nil {
 		issues = append(issues, Issue{
 			Severity:    SeverityWarning,
 			ProjectType: "Ruby",
 			Message:     "Gemfile.lock not found - dependencies may not be installed",
 			Suggestion:  "Run 'bundle install' to install dependencies",
 		})
+	} else {
+		lock = parseGemLockfile(string(data))
+	}
+
+	issues = append(issues, checkRubyVersions(path, lock)...)
+	if lock != nil {
+		issues = append(issues, checkGemsInstalled(path, lock)...)
 	}
 
 	return issues
@@ -257,16 +422,18 @@ func checkRust(path string) []Issue {
 		})
 	}
 
-	// Check if target directory exists
+	// Check if target directory exists; a fresh clone has not been built yet
 	if _, err := os.Stat(filepath.Join(path, "target")); os.IsNotExist(err) {
 		issues = append(issues, Issue{
-			Severity:    SeverityWarning,
+			Severity:    SeverityInfo,
 			ProjectType: "Rust",
 			Message:     "Project not built (target directory not found)",
 			Suggestion:  "Run 'cargo build' to build the project",
 		})
 	}
 
+	issues = append(issues, checkRustSetup(path)...)
+
 	return issues
 }
 
@@ -293,6 +460,10 @@ func checkDotNet(path string) []Issue {
 		})
 	}
 
+	// Check global.json and the target frameworks against the installed
+	// SDKs and runtimes
+	issues = append(issues, checkDotnetSetup(path, scanDotnet(dotnetRoots()))...)
+
 	return issues
 }
 
@@ -312,78 +483,267 @@ func checkDocker(path string) []Issue {
 		}
 	}
 
-	// Check for .env file if docker-compose is present
-	hasCompose := false
-	if _, err := os.Stat(filepath.Join(path, "docker-compose.yml")); err == nil {
-		hasCompose = true
+	// Check the paths and variables the compose files reference
+	issues = append(issues, checkCompose(path)...)
+
+	// Check the Dockerfiles against their build context
+	for _, name := range dockerfileNames(path) {
+		issues = append(issues, checkDockerfile(path, name)...)
+	}
+
+	return issues
+}
+
+func checkPHP(path string) []Issue {
+	issues := []Issue{}
+
+	// Check if composer.lock exists
+	var lock *composerLock
+	if data, err := os.ReadFile(filepath.Join(path, "composer.lock")); err != nil {
+		issues = append(issues, Issue{
+			Severity:    SeverityWarning,
+			ProjectType: "PHP",
+			Message:     "composer.lock not found - dependency versions are not locked",
+			Suggestion:  "Run 'composer install' to resolve dependencies and write composer.lock",
+		})
+	} else if err := json.Unmarshal(data, &lock); err != nil {
+		lock = nil
+	}
+
+	// Check if vendor exists
+	if _, err := os.Stat(filepath.Join(path, "vendor")); os.IsNotExist(err) {
+		issues = append(issues, Issue{
+			Severity:    SeverityWarning,
+			ProjectType: "PHP",
+			Message:     "Dependencies not installed (vendor directory not found)",
+			Suggestion:  "Run 'composer install' to install dependencies",
+		})
 	}
-	if _, err := os.Stat(filepath.Join(path, "docker-compose.yaml")); err == nil {
-		hasCompose = true
+
+	// Check the lock, vendor/ and the PHP version and extensions
+	phpVersion := ""
+	var modules map[string]bool
+	if status := envcheck.LookupIn(path, "php"); status.Found {
+		phpVersion = status.Version
+		modules = loadedPHPModules(path)
 	}
+	issues = append(issues, checkComposer(path, lock, phpVersion, modules)...)
 
-	if hasCompose {
-		// Look for .env.example or .env.sample
-		hasEnvExample := false
-		if _, err := os.Stat(filepath.Join(path, ".env.example")); err == nil {
-			hasEnvExample = true
-		}
-		if _, err := os.Stat(filepath.Join(path, ".env.sample")); err == nil {
-			hasEnvExample = true
+	return issues
+}
+
+func checkSwift(path string) []Issue {
+	issues := []Issue{}
+
+	issues = append(issues, checkSwiftToolsVersion(path, installedSwiftVersion(path))...)
+
+	// Check if Package.resolved exists; packages without dependencies have none
+	data, err := os.ReadFile(filepath.Join(path, "Package.resolved"))
+	if err != nil {
+		if manifest, _ := os.ReadFile(filepath.Join(path, "Package.swift")); strings.Contains(string(manifest), ".package(") {
+			issues = append(issues, Issue{
+				Severity:    SeverityInfo,
+				ProjectType: "Swift",
+				Message:     "Package.resolved not found - dependency versions are not pinned",
+				Suggestion:  "Run 'swift package resolve' and commit Package.resolved",
+			})
 		}
+		return issues
+	}
+	pins, err := parsePackageResolved(data)
+	if err != nil {
+		return append(issues, Issue{
+			Severity:    SeverityError,
+			ProjectType: "Swift",
+			Message:     fmt.Sprintf("Cannot parse Package.resolved: %v", err),
+			Suggestion:  "Resolve the merge conflict or run 'swift package resolve' to regenerate it",
+		})
+	}
 
-		if hasEnvExample {
-			if _, err := os.Stat(filepath.Join(path, ".env")); os.IsNotExist(err) {
-				issues = append(issues, Issue{
-					Severity:    SeverityWarning,
-					ProjectType: "Docker",
-					Message:     "Environment file (.env) not found but example exists",
-					Suggestion:  "Copy .env.example to .env and configure your environment variables",
-				})
-			}
+	// Check if the pinned dependencies have been checked out
+	if _, err := os.Stat(filepath.Join(path, ".build")); os.IsNotExist(err) && len(pins) > 0 {
+		issues = append(issues, Issue{
+			Severity:    SeverityInfo,
+			ProjectType: "Swift",
+			Message:     "Dependencies not resolved (.build directory not found)",
+			Suggestion:  "Run 'swift package resolve' or 'swift build'",
+		})
+	}
+	issues = append(issues, checkSwiftPins(path, pins)...)
+
+	return issues
+}
+
+func checkKotlin(path string) []Issue {
+	issues := []Issue{}
+	wrapper := detector.DetectGradleWrapper(path)
+
+	// A root build script also makes this a Java project, whose checks cover
+	// the wrapper, Gradle and the JDK
+	if !fileExists(path, "build.gradle.kts") && !fileExists(path, "build.gradle") {
+		for _, issue := range checkGradle(path, wrapper) {
+			issue.ProjectType = "Kotlin"
+			issues = append(issues, issue)
 		}
 	}
 
+	gradleVersion, source := "", ""
+	if wrapper != nil {
+		gradleVersion, source = wrapper.Version(), "wrapper"
+	} else if status := envcheck.LookupIn(path, "gradle"); status.Found {
+		gradleVersion, source = status.Version, "PATH"
+	}
+	issues = append(issues, checkKotlinPlugin(path, gradleVersion, source)...)
+
 	return issues
 }
 
-// Check for common environment files
-func checkEnvironmentFiles(path string) []Issue {
+func checkElixir(path string) []Issue {
 	issues := []Issue{}
 
-	// Look for .env.example or .env.sample
-	hasEnvExample := false
-	exampleFile := ""
-	for _, filename := range []string{".env.example", ".env.sample", "env.example"} {
-		if _, err := os.Stat(filepath.Join(path, filename)); err == nil {
-			hasEnvExample = true
-			exampleFile = filename
-			break
+	// Check if mix.lock exists
+	var deps []mixDep
+	if data, err := os.ReadFile(filepath.Join(path, "mix.lock")); err != nil {
+		issues = append(issues, Issue{
+			Severity:    SeverityWarning,
+			ProjectType: "Elixir",
+			Message:     "mix.lock not found - dependencies may not be fetched",
+			Suggestion:  "Run 'mix deps.get' to fetch dependencies",
+		})
+	} else {
+		deps = parseMixLock(string(data))
+	}
+
+	// Check if deps and _build exist
+	if _, err := os.Stat(filepath.Join(path, "deps")); os.IsNotExist(err) && len(deps) > 0 {
+		issues = append(issues, Issue{
+			Severity:    SeverityWarning,
+			ProjectType: "Elixir",
+			Message:     "Dependencies not fetched (deps directory not found)",
+			Suggestion:  "Run 'mix deps.get' to fetch dependencies",
+		})
+	} else {
+		issues = append(issues, checkMixDeps(path, deps)...)
+	}
+	if _, err := os.Stat(filepath.Join(path, "_build")); os.IsNotExist(err) {
+		issues = append(issues, Issue{
+			Severity:    SeverityInfo,
+			ProjectType: "Elixir",
+			Message:     "Project not compiled (_build directory not found)",
+			Suggestion:  "Run 'mix compile' to build the project",
+		})
+	}
+
+	// Check the Elixir requirement and the OTP release
+	issues = append(issues, checkElixirVersions(path, installedElixir(path))...)
+
+	return issues
+}
+
+func checkHaskell(path string) []Issue {
+	issues := []Issue{}
+	ghc := ""
+	if status := envcheck.LookupIn(path, "ghc"); status.Found {
+		ghc = status.Version
+	}
+
+	if cfg, err := readStackConfig(path); err == nil {
+		// Check if .stack-work exists
+		if _, err := os.Stat(filepath.Join(path, ".stack-work")); os.IsNotExist(err) {
+			issues = append(issues, Issue{
+				Severity:    SeverityInfo,
+				ProjectType: "Haskell",
+				Message:     "Project not built (.stack-work directory not found)",
+				Suggestion:  "Run 'stack build' to build the project",
+			})
 		}
+		issues = append(issues, checkStackCompiler(cfg, ghc, installedGHCs())...)
+	} else if fileExists(path, "stack.yaml") {
+		issues = append(issues, Issue{
+			Severity:    SeverityError,
+			ProjectType: "Haskell",
+			Message:     fmt.Sprintf("Cannot parse stack.yaml: %v", err),
+			Suggestion:  "Fix the YAML syntax in stack.yaml",
+		})
 	}
 
-	if hasEnvExample {
-		if _, err := os.Stat(filepath.Join(path, ".env")); os.IsNotExist(err) {
+	if fileExists(path, "cabal.project") {
+		// Check if dist-newstyle exists
+		if _, err := os.Stat(filepath.Join(path, "dist-newstyle")); os.IsNotExist(err) && !fileExists(path, "stack.yaml") {
 			issues = append(issues, Issue{
-				Severity:    SeverityWarning,
-				ProjectType: "General",
-				Message:     "Environment file (.env) not found",
-				Suggestion:  fmt.Sprintf("Copy %s to .env and configure your environment variables", exampleFile),
+				Severity:    SeverityInfo,
+				ProjectType: "Haskell",
+				Message:     "Project not built (dist-newstyle directory not found)",
+				Suggestion:  "Run 'cabal build' to build the project",
 			})
 		}
+		issues = append(issues, checkCabalCompiler(path, ghc)...)
+	}
+
+	return issues
+}
+
+func checkScala(path string) []Issue {
+	issues := []Issue{}
+
+	// Check if the sbt version is pinned
+	if sbtVersion(path) == "" {
+		issues = append(issues, Issue{
+			Severity:    SeverityInfo,
+			ProjectType: "Scala",
+			Message:     "sbt version not pinned (sbt.version not found in project/build.properties)",
+			Suggestion:  "Add sbt.version=<version> to project/build.properties so every machine builds with the same sbt",
+		})
+	}
+
+	// Check sbt and the Scala compiler against the JDK
+	jdk, jdkSource := installedJDK(path)
+	issues = append(issues, checkScalaJDK(path, jdk, jdkSource)...)
+
+	return issues
+}
+
+func checkDart(path string) []Issue {
+	issues := []Issue{}
+	spec, err := readPubspec(path)
+	if err != nil {
+		return append(issues, Issue{
+			Severity:    SeverityError,
+			ProjectType: "Dart/Flutter",
+			Message:     fmt.Sprintf("Cannot parse pubspec.yaml: %v", err),
+			Suggestion:  "Fix the YAML syntax in pubspec.yaml",
+		})
+	}
+	lock := readPubLock(path)
+
+	// Check the SDK constraints against the installed Dart and Flutter
+	dart, flutter := "", ""
+	if status := envcheck.LookupIn(path, "dart"); status.Found {
+		dart = status.Version
+	}
+	if spec.IsFlutter {
+		if status := envcheck.LookupIn(path, "flutter"); status.Found {
+			flutter = status.Version
+		}
 	}
+	issues = append(issues, checkDartSDK(spec, lock, dart, flutter)...)
+
+	// Check .dart_tool/package_config.json and the pub cache
+	issues = append(issues, checkPubPackages(path, spec, lock)...)
 
 	return issues
 }
 
-// Helper function to parse version requirements
-func parseVersionRequirement(requirement string) string {
-	// Remove common prefixes and return cleaned version
-	cleaned := strings.TrimSpace(requirement)
-	cleaned = strings.TrimPrefix(cleaned, "^")
-	cleaned = strings.TrimPrefix(cleaned, "~")
-	cleaned = strings.TrimPrefix(cleaned, ">=")
-	cleaned = strings.TrimPrefix(cleaned, "<=")
-	cleaned = strings.TrimPrefix(cleaned, ">")
-	cleaned = strings.TrimPrefix(cleaned, "<")
-	return cleaned
+func checkNative(path, projectType string) []Issue {
+	// Compare the build files with the installed CMake and Meson
+	cmake, meson := "", ""
+	if status := envcheck.Lookup("cmake"); status.Found {
+		cmake = status.Version
+	}
+	if status := envcheck.Lookup("meson"); status.Found {
+		meson = status.Version
+	}
+
+	// Check find_package, pkg_check_modules and dependency() lookups
+	return checkNativeBuild(path, projectType, defaultNativeEnv(), cmake, meson)
 }
@@ -5,6 +5,7 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/Sw3bbl3/devdoctor/internal/config"
 	"github.com/Sw3bbl3/devdoctor/internal/detector"
 )
 
@@ -49,11 +50,22 @@ func TestCheckPython(t *testing.T) {
 		t.Error("Expected virtual environment warning")
 	}
 
+	// A dotenv file is not a virtual environment
+	if err := os.Mkdir(filepath.Join(tmpDir, ".env"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if len(checkPython(tmpDir)) == 0 {
+		t.Error("Expected virtual environment warning with only .env present")
+	}
+
 	// Create venv
 	venvPath := filepath.Join(tmpDir, "venv")
 	if err := os.Mkdir(venvPath, 0755); err != nil {
 		t.Fatal(err)
 	}
+	if err := os.WriteFile(filepath.Join(venvPath, "pyvenv.cfg"), []byte("version = 3.11.4\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
 
 	issues = checkPython(tmpDir)
 	hasVenvWarning = false
@@ -160,6 +172,11 @@ func TestCheckRust(t *testing.T) {
 	if len(issues) == 0 {
 		t.Error("Expected issues for unbuilt Rust project")
 	}
+	for _, issue := range issues {
+		if issue.Message == "Project not built (target directory not found)" && issue.Severity != SeverityInfo {
+			t.Errorf("target directory issue severity = %s, want INFO", issue.Severity)
+		}
+	}
 
 	// Create target directory
 	targetPath := filepath.Join(tmpDir, "target")
@@ -246,3 +263,50 @@ func TestGetInstallSuggestion(t *testing.T) {
 		})
 	}
 }
+
+func TestCheckProjectSetsRoot(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	project := &detector.ProjectType{
+		Name:        "Go",
+		Root:        "services/api",
+		ConfigFiles: []string{"go.mod"},
+	}
+
+	issues := CheckProject(tmpDir, project)
+	if len(issues) == 0 {
+		t.Fatal("Expected issues for a Go project without go.sum")
+	}
+	for _, issue := range issues {
+		if issue.Root != "services/api" {
+			t.Errorf("Expected issue root 'services/api', got %q", issue.Root)
+		}
+	}
+}
+
+func TestCheckCustom(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	project := &detector.ProjectType{
+		Name: "Acme",
+		Checks: []config.Check{
+			{File: "acme.lock", Severity: "ERROR", Message: "acme.lock missing", Suggestion: "Run 'acme lock'"},
+			{File: "*.acme.yml", Severity: "WARNING"},
+			{Command: []string{"devdoctor-no-such-command"}, Severity: "INFO"},
+		},
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "app.acme.yml"), []byte(""), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	issues := checkCustom(tmpDir, project)
+	if len(issues) != 2 {
+		t.Fatalf("Expected 2 issues, got %v", issues)
+	}
+	if issues[0].Severity != SeverityError || issues[0].Message != "acme.lock missing" {
+		t.Errorf("Unexpected file check issue: %+v", issues[0])
+	}
+	if issues[1].Severity != SeverityInfo || issues[1].Message != "Command 'devdoctor-no-such-command' failed" {
+		t.Errorf("Unexpected command check issue: %+v", issues[1])
+	}
+}
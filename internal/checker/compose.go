@@ -0,0 +1,375 @@
+package checker
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/Sw3bbl3/devdoctor/internal/dotenv"
+	"github.com/Sw3bbl3/devdoctor/internal/yaml"
+)
+
+// composeFileNames are the default compose files in the order Compose looks
+// for them, and composeOverrideNames the override files merged on top
+var (
+	composeFileNames     = []string{"compose.yaml", "compose.yml", "docker-compose.yaml", "docker-compose.yml"}
+	composeOverrideNames = []string{"compose.override.yaml", "compose.override.yml", "docker-compose.override.yaml", "docker-compose.override.yml"}
+)
+
+// composeEnv returns the variables Compose interpolates: .env in the
+// project directory, overridden by the environment
+func composeEnv(path string) map[string]string {
+	env, err := dotenv.Read(filepath.Join(path, ".env"))
+	if err != nil {
+		env = map[string]string{}
+	}
+	for _, kv := range os.Environ() {
+		if key, value, ok := strings.Cut(kv, "="); ok {
+			env[key] = value
+		}
+	}
+	return env
+}
+
+// composeFiles returns the compose files 'docker compose' uses in path:
+// the files listed in COMPOSE_FILE (the equivalent of repeated -f flags),
+// otherwise the default file and its override file
+func composeFiles(path string, env map[string]string) []string {
+	if list := env["COMPOSE_FILE"]; list != "" {
+		sep := env["COMPOSE_PATH_SEPARATOR"]
+		if sep == "" {
+			sep = string(os.PathListSeparator)
+		}
+		files := []string{}
+		for _, file := range strings.Split(list, sep) {
+			if file = strings.TrimSpace(file); file != "" {
+				if !filepath.IsAbs(file) {
+					file = filepath.Join(path, file)
+				}
+				files = append(files, file)
+			}
+		}
+		return files
+	}
+
+	files := []string{}
+	for _, names := range [][]string{composeFileNames, composeOverrideNames} {
+		for _, name := range names {
+			if fileExists(path, name) {
+				files = append(files, filepath.Join(path, name))
+				break
+			}
+		}
+	}
+	if len(files) == 1 && strings.Contains(filepath.Base(files[0]), ".override.") {
+		return nil // an override file alone is not a compose project
+	}
+	return files
+}
+
+// interpolate expands ${VAR}, $VAR and the ${VAR:-default}, ${VAR-default},
+// ${VAR:?error}, ${VAR?error} and ${VAR:+alt} forms the way Compose does.
+// It returns the variables that had no value, split into those the file
+// only warns about and those marked as required with '?'.
+func interpolate(s string, env map[string]string) (result string, unset, required []string) {
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '$' || i+1 == len(s) {
+			sb.WriteByte(s[i])
+			continue
+		}
+		if s[i+1] == '$' {
+			sb.WriteByte('$')
+			i++
+			continue
+		}
+
+		var expr string
+		if s[i+1] == '{' {
+			end := strings.IndexByte(s[i:], '}')
+			if end < 0 {
+				sb.WriteString(s[i:])
+				break
+			}
+			expr = s[i+2 : i+end]
+			i += end
+		} else {
+			j := i + 1
+			for j < len(s) && (s[j] == '_' || isAlnum(s[j])) {
+				j++
+			}
+			if j == i+1 {
+				sb.WriteByte('$')
+				continue
+			}
+			expr = s[i+1 : j]
+			i = j - 1
+		}
+
+		name, op, arg := expr, "", ""
+		if k := strings.IndexAny(expr, ":-?+"); k > 0 {
+			name, op = expr[:k], expr[k:k+1]
+			if op == ":" && k+1 < len(expr) {
+				op = expr[k : k+2]
+			}
+			arg = expr[k+len(op):]
+		}
+		value, set := env[name]
+		switch op {
+		case "":
+			if !set {
+				unset = append(unset, name)
+			}
+			sb.WriteString(value)
+		case ":-", "-":
+			if !set || (op == ":-" && value == "") {
+				value = arg
+			}
+			sb.WriteString(value)
+		case ":?", "?":
+			if !set || (op == ":?" && value == "") {
+				required = append(required, name)
+			}
+			sb.WriteString(value)
+		case ":+", "+":
+			if set && (op == "+" || value != "") {
+				sb.WriteString(arg)
+			}
+		}
+	}
+	return sb.String(), unset, required
+}
+
+func isAlnum(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
+
+// walkScalars calls fn for every scalar value in a decoded YAML document
+func walkScalars(v interface{}, fn func(string)) {
+	switch v := v.(type) {
+	case string:
+		fn(v)
+	case map[string]interface{}:
+		for _, key := range yaml.Keys(v) {
+			walkScalars(v[key], fn)
+		}
+	case []interface{}:
+		for _, item := range v {
+			walkScalars(item, fn)
+		}
+	}
+}
+
+// composeChecker checks one compose file. Relative paths are resolved
+// against the project directory, the directory of the first compose file.
+type composeChecker struct {
+	path       string
+	projectDir string
+	file       string
+	env        map[string]string
+	issues     []Issue
+}
+
+func (c *composeChecker) add(severity Severity, message, suggestion string) {
+	c.issues = append(c.issues, Issue{
+		Severity:    severity,
+		ProjectType: "Docker",
+		Message:     fmt.Sprintf("%s: %s", displayPath(c.path, c.file), message),
+		Suggestion:  suggestion,
+	})
+}
+
+// resolve interpolates a path and makes it absolute. It returns "" when a
+// variable in it has no value, since the path cannot be known then.
+func (c *composeChecker) resolve(value, base string) string {
+	value, unset, required := interpolate(value, c.env)
+	if len(unset) > 0 || len(required) > 0 || value == "" {
+		return ""
+	}
+	if strings.HasPrefix(value, "~/") {
+		value = userHomePath(value[2:])
+	}
+	if !filepath.IsAbs(value) {
+		value = filepath.Join(base, value)
+	}
+	return value
+}
+
+func (c *composeChecker) checkVariables(doc interface{}) {
+	unset, required := map[string]bool{}, map[string]bool{}
+	walkScalars(doc, func(s string) {
+		_, u, r := interpolate(s, c.env)
+		for _, name := range u {
+			unset[name] = true
+		}
+		for _, name := range r {
+			required[name] = true
+		}
+	})
+	if len(required) > 0 {
+		c.add(SeverityError,
+			fmt.Sprintf("required variables are not set: %s", strings.Join(sortedKeys(required), ", ")),
+			"Set them in .env or the environment; 'docker compose' refuses to start without them")
+	}
+	for name := range required {
+		delete(unset, name)
+	}
+	if len(unset) > 0 {
+		c.add(SeverityWarning,
+			fmt.Sprintf("variables have no value in .env or the environment and default to an empty string: %s", strings.Join(sortedKeys(unset), ", ")),
+			"Add them to .env, or give them a default with ${VAR:-default}")
+	}
+}
+
+func (c *composeChecker) checkService(name string, service map[string]interface{}) {
+	// build: may be the context itself or a mapping
+	if build := service["build"]; build != nil {
+		context, dockerfile := yaml.String(build), ""
+		if m := yaml.Map(build); m != nil {
+			context, dockerfile = yaml.String(m["context"]), yaml.String(m["dockerfile"])
+			if m["dockerfile_inline"] != nil {
+				dockerfile = "-"
+			}
+		}
+		if context == "" {
+			context = "."
+		}
+		// Remote contexts (git repositories and URLs) are fetched by BuildKit
+		if !strings.Contains(context, "://") && !strings.HasPrefix(context, "git@") {
+			if dir := c.resolve(context, c.projectDir); dir != "" && !pathExists(dir) {
+				c.add(SeverityError,
+					fmt.Sprintf("service '%s' build context '%s' does not exist", name, context),
+					"Fix build.context or restore the directory")
+			} else if dir != "" && dockerfile != "-" {
+				if dockerfile == "" {
+					dockerfile = "Dockerfile"
+				}
+				if file := c.resolve(dockerfile, dir); file != "" && !pathExists(file) {
+					c.add(SeverityError,
+						fmt.Sprintf("service '%s' Dockerfile '%s' does not exist in build context '%s'", name, dockerfile, context),
+						"Fix build.dockerfile; it is relative to the build context")
+				}
+			}
+		}
+	}
+
+	// env_file: a path, a list of paths or a list of {path, required}
+	envFiles := yaml.List(service["env_file"])
+	if s := yaml.String(service["env_file"]); s != "" {
+		envFiles = []interface{}{s}
+	}
+	for _, entry := range envFiles {
+		file, required := yaml.String(entry), true
+		if m := yaml.Map(entry); m != nil {
+			file, required = yaml.String(m["path"]), yaml.String(m["required"]) != "false"
+		}
+		if resolved := c.resolve(file, c.projectDir); required && resolved != "" && !pathExists(resolved) {
+			c.add(SeverityError,
+				fmt.Sprintf("service '%s' env_file '%s' does not exist", name, file),
+				fmt.Sprintf("Create %s, or mark it 'required: false'", file))
+		}
+	}
+
+	for _, volume := range yaml.List(service["volumes"]) {
+		c.checkVolume(name, volume)
+	}
+}
+
+// checkVolume checks the host path of a bind mount. With the short syntax
+// Docker creates a missing host path as an empty directory; with the long
+// syntax it fails unless bind.create_host_path is set.
+func (c *composeChecker) checkVolume(service string, volume interface{}) {
+	source, long := "", false
+	if m := yaml.Map(volume); m != nil {
+		if yaml.String(m["type"]) != "bind" || yaml.String(yaml.Lookup(m, "bind", "create_host_path")) == "true" {
+			return
+		}
+		source, long = yaml.String(m["source"]), true
+	} else {
+		spec := yaml.String(volume)
+		drive := ""
+		if len(spec) > 2 && spec[1] == ':' && (spec[2] == '\\' || spec[2] == '/') {
+			drive, spec = spec[:2], spec[2:] // C:\data:/data
+		}
+		host, _, ok := strings.Cut(spec, ":")
+		if !ok {
+			return // anonymous volume
+		}
+		source = drive + host
+		if drive == "" && !strings.HasPrefix(source, ".") && !strings.HasPrefix(source, "/") && !strings.HasPrefix(source, "~") && !strings.HasPrefix(source, "$") {
+			return // named volume
+		}
+	}
+
+	resolved := c.resolve(source, c.projectDir)
+	if resolved == "" || pathExists(resolved) {
+		return
+	}
+	if long {
+		c.add(SeverityError,
+			fmt.Sprintf("service '%s' bind mount source '%s' does not exist", service, source),
+			"Create the path, or set bind.create_host_path: true")
+	} else {
+		c.add(SeverityWarning,
+			fmt.Sprintf("service '%s' bind mount '%s' does not exist; Docker will create it as an empty directory", service, source),
+			"Create the path or fix the volume, e.g. with 'mkdir -p "+source+"'")
+	}
+}
+
+// checkCompose parses the project's compose files and checks the paths and
+// variables they reference
+func checkCompose(path string) []Issue {
+	issues := []Issue{}
+	env := composeEnv(path)
+	files := composeFiles(path, env)
+	if len(files) == 0 {
+		return issues
+	}
+
+	projectDir := filepath.Dir(files[0])
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			issues = append(issues, Issue{
+				Severity:    SeverityError,
+				ProjectType: "Docker",
+				Message:     fmt.Sprintf("Compose file %s listed in COMPOSE_FILE does not exist", displayPath(path, file)),
+				Suggestion:  "Fix COMPOSE_FILE in .env or the environment",
+			})
+			continue
+		}
+		doc, err := yaml.Decode(data)
+		if err != nil {
+			issues = append(issues, Issue{
+				Severity:    SeverityWarning,
+				ProjectType: "Docker",
+				Message:     fmt.Sprintf("Cannot parse %s: %v", displayPath(path, file), err),
+				Suggestion:  "Run 'docker compose config' to validate the file",
+			})
+			continue
+		}
+
+		c := &composeChecker{path: path, projectDir: projectDir, file: file, env: env}
+		c.checkVariables(doc)
+		services := yaml.Map(yaml.Lookup(doc, "services"))
+		for _, name := range yaml.Keys(services) {
+			if service := yaml.Map(services[name]); service != nil {
+				c.checkService(name, service)
+			}
+		}
+		issues = append(issues, c.issues...)
+	}
+	return issues
+}
+
+// sortedKeys returns the keys of a set in order
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
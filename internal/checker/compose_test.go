@@ -0,0 +1,109 @@
+package checker
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestInterpolate(t *testing.T) {
+	env := map[string]string{"TAG": "1.2", "EMPTY": ""}
+	tests := []struct {
+		in, want        string
+		unset, required string
+	}{
+		{"app:${TAG}", "app:1.2", "", ""},
+		{"app:$TAG-$NAME", "app:1.2-", "NAME", ""},
+		{"${PORT:-8080}", "8080", "", ""},
+		{"${EMPTY:-x}/${EMPTY-y}", "x/", "", ""},
+		{"${DB_URL:?set DB_URL}", "", "", "DB_URL"},
+		{"${EMPTY?}", "", "", ""},
+		{"${TAG:+tagged}${MISSING:+no}", "tagged", "", ""},
+		{"cost $$5", "cost $5", "", ""},
+	}
+	for _, tt := range tests {
+		got, unset, required := interpolate(tt.in, env)
+		if got != tt.want || strings.Join(unset, ",") != tt.unset || strings.Join(required, ",") != tt.required {
+			t.Errorf("interpolate(%q) = %q, %v, %v; want %q, %q, %q", tt.in, got, unset, required, tt.want, tt.unset, tt.required)
+		}
+	}
+}
+
+func TestComposeFiles(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"compose.yaml", "docker-compose.yml", "compose.override.yml"} {
+		os.WriteFile(filepath.Join(dir, name), []byte("services: {}\n"), 0644)
+	}
+	got := composeFiles(dir, map[string]string{})
+	want := []string{filepath.Join(dir, "compose.yaml"), filepath.Join(dir, "compose.override.yml")}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("composeFiles() = %v, want %v", got, want)
+	}
+
+	got = composeFiles(dir, map[string]string{"COMPOSE_FILE": "docker-compose.yml:docker-compose.prod.yml", "COMPOSE_PATH_SEPARATOR": ":"})
+	want = []string{filepath.Join(dir, "docker-compose.yml"), filepath.Join(dir, "docker-compose.prod.yml")}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("composeFiles() with COMPOSE_FILE = %v, want %v", got, want)
+	}
+}
+
+func TestCheckCompose(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, ".env"), []byte("WEB_PORT=8080\n"), 0644)
+	os.MkdirAll(filepath.Join(dir, "web"), 0755)
+	os.WriteFile(filepath.Join(dir, "web", "Dockerfile"), []byte("FROM scratch\n"), 0644)
+	os.MkdirAll(filepath.Join(dir, "api"), 0755)
+	os.WriteFile(filepath.Join(dir, "compose.yaml"), []byte(`services:
+  web:
+    build: ./web
+    ports:
+      - "${WEB_PORT}:80"
+    env_file: .env.web
+    volumes:
+      - ./static:/usr/share/nginx/html:ro
+      - data:/data
+  api:
+    build:
+      context: ./api
+      dockerfile: Dockerfile.dev
+    environment:
+      SECRET_KEY: ${SECRET_KEY:?SECRET_KEY is required}
+      LOG_LEVEL: ${LOG_LEVEL}
+    env_file:
+      - path: .env.local
+        required: false
+    volumes:
+      - type: bind
+        source: ./config
+        target: /etc/api
+  worker:
+    build:
+      context: ./worker
+volumes:
+  data:
+`), 0644)
+	os.WriteFile(filepath.Join(dir, "compose.override.yaml"), []byte(`services:
+  web:
+    env_file:
+      - .env.override
+`), 0644)
+
+	want := []string{
+		"compose.yaml: required variables are not set: SECRET_KEY",
+		"compose.yaml: variables have no value in .env or the environment and default to an empty string: LOG_LEVEL",
+		"compose.yaml: service 'api' Dockerfile 'Dockerfile.dev' does not exist in build context './api'",
+		"compose.yaml: service 'api' bind mount source './config' does not exist",
+		"compose.yaml: service 'web' env_file '.env.web' does not exist",
+		"compose.yaml: service 'web' bind mount './static' does not exist; Docker will create it as an empty directory",
+		"compose.yaml: service 'worker' build context './worker' does not exist",
+		"compose.override.yaml: service 'web' env_file '.env.override' does not exist",
+	}
+	messages := []string{}
+	for _, issue := range checkCompose(dir) {
+		messages = append(messages, issue.Message)
+	}
+	if strings.Join(messages, "\n") != strings.Join(want, "\n") {
+		t.Errorf("checkCompose():\n%s\nwant:\n%s", strings.Join(messages, "\n"), strings.Join(want, "\n"))
+	}
+}
@@ -0,0 +1,51 @@
+package checker
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/Sw3bbl3/devdoctor/internal/detector"
+)
+
+// checkCustom runs the declarative checks of a project type loaded from a
+// devdoctor config file
+func checkCustom(path string, project *detector.ProjectType) []Issue {
+	issues := []Issue{}
+
+	for _, check := range project.Checks {
+		message := check.Message
+		if check.File != "" {
+			matches, _ := filepath.Glob(filepath.Join(path, check.File))
+			if len(matches) > 0 {
+				continue
+			}
+			if message == "" {
+				message = fmt.Sprintf("Required file '%s' not found", check.File)
+			}
+		} else {
+			cmd := exec.Command(check.Command[0], check.Command[1:]...)
+			cmd.Dir = path
+			if err := cmd.Run(); err == nil {
+				continue
+			}
+			if message == "" {
+				message = fmt.Sprintf("Command '%s' failed", strings.Join(check.Command, " "))
+			}
+		}
+
+		suggestion := check.Suggestion
+		if suggestion == "" {
+			suggestion = "See the project's devdoctor config for details"
+		}
+		issues = append(issues, Issue{
+			Severity:    Severity(check.Severity),
+			ProjectType: project.Name,
+			Message:     message,
+			Suggestion:  suggestion,
+		})
+	}
+
+	return issues
+}
@@ -0,0 +1,209 @@
+package checker
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/Sw3bbl3/devdoctor/internal/semver"
+	"github.com/Sw3bbl3/devdoctor/internal/yaml"
+)
+
+// pubspec holds the parts of pubspec.yaml the checks need
+type pubspec struct {
+	SDK     string // environment.sdk
+	Flutter string // environment.flutter
+	// IsFlutter is set when the package depends on the Flutter SDK
+	IsFlutter bool
+}
+
+// readPubspec reads pubspec.yaml
+func readPubspec(path string) (*pubspec, error) {
+	data, err := os.ReadFile(filepath.Join(path, "pubspec.yaml"))
+	if err != nil {
+		return nil, err
+	}
+	doc, err := yaml.Decode(data)
+	if err != nil {
+		return nil, err
+	}
+	return &pubspec{
+		SDK:       yaml.String(yaml.Lookup(doc, "environment", "sdk")),
+		Flutter:   yaml.String(yaml.Lookup(doc, "environment", "flutter")),
+		IsFlutter: yaml.String(yaml.Lookup(doc, "dependencies", "flutter", "sdk")) == "flutter",
+	}, nil
+}
+
+// pubCommand returns the command that runs pub for the package
+func (p *pubspec) pubCommand() string {
+	if p.IsFlutter {
+		return "flutter pub get"
+	}
+	return "dart pub get"
+}
+
+// pubLock holds the parts of pubspec.lock the checks need
+type pubLock struct {
+	Packages []string
+	// SDKs holds the SDK constraints every locked package agrees on
+	SDKs map[string]string
+}
+
+// readPubLock reads pubspec.lock, or returns nil if there is none
+func readPubLock(path string) *pubLock {
+	data, err := os.ReadFile(filepath.Join(path, "pubspec.lock"))
+	if err != nil {
+		return nil
+	}
+	doc, err := yaml.Decode(data)
+	if err != nil {
+		return nil
+	}
+	lock := &pubLock{
+		Packages: yaml.Keys(yaml.Map(yaml.Lookup(doc, "packages"))),
+		SDKs:     map[string]string{},
+	}
+	for name, constraint := range yaml.Map(yaml.Lookup(doc, "sdks")) {
+		lock.SDKs[name] = yaml.String(constraint)
+	}
+	return lock
+}
+
+// dartPackageConfig is .dart_tool/package_config.json, which pub writes
+// with the location of every resolved package
+type dartPackageConfig struct {
+	Packages []struct {
+		Name    string `json:"name"`
+		RootURI string `json:"rootUri"`
+	} `json:"packages"`
+}
+
+// packageRoot resolves the rootUri of a package, which is a file: URI or
+// is relative to the .dart_tool directory
+func packageRoot(dartTool, rootURI string) string {
+	if u, err := url.Parse(rootURI); err == nil && u.Scheme == "file" {
+		p := filepath.FromSlash(u.Path)
+		// file:///C:/... on Windows
+		if len(p) > 2 && p[0] == filepath.Separator && p[2] == ':' {
+			p = p[1:]
+		}
+		return p
+	}
+	return filepath.Join(dartTool, filepath.FromSlash(rootURI))
+}
+
+// modTime returns the modification time of a file in nanoseconds, or 0
+func modTime(file string) int64 {
+	info, err := os.Stat(file)
+	if err != nil {
+		return 0
+	}
+	return info.ModTime().UnixNano()
+}
+
+// checkDartSDK compares the SDK constraints of pubspec.yaml and
+// pubspec.lock with the installed Dart and Flutter SDKs
+func checkDartSDK(spec *pubspec, lock *pubLock, dart, flutter string) []Issue {
+	issues := []Issue{}
+	type constraint struct {
+		SDK, Installed, Range, Source string
+	}
+	constraints := []constraint{
+		{"Dart", dart, spec.SDK, "environment.sdk in pubspec.yaml"},
+		{"Flutter", flutter, spec.Flutter, "environment.flutter in pubspec.yaml"},
+	}
+	if lock != nil {
+		// The lock narrows the constraints to what every dependency accepts
+		constraints = append(constraints,
+			constraint{"Dart", dart, lock.SDKs["dart"], "sdks in pubspec.lock"},
+			constraint{"Flutter", flutter, lock.SDKs["flutter"], "sdks in pubspec.lock"})
+	}
+
+	reported := map[string]bool{}
+	for _, c := range constraints {
+		if c.Range == "" || c.Installed == "" || reported[c.SDK] {
+			continue
+		}
+		if ok, err := semver.Satisfies(c.Installed, c.Range); err == nil && !ok {
+			reported[c.SDK] = true
+			issues = append(issues, Issue{
+				Severity:    SeverityError,
+				ProjectType: "Dart/Flutter",
+				Message:     fmt.Sprintf("%s requires %s SDK %s but %s is installed", c.Source, c.SDK, c.Range, c.Installed),
+				Suggestion:  fmt.Sprintf("Install a matching %s SDK (e.g. with fvm or 'flutter upgrade'/'flutter downgrade')", c.SDK),
+			})
+		}
+	}
+	return issues
+}
+
+// checkPubPackages checks that pub get has run since pubspec.yaml and
+// pubspec.lock last changed, and that the packages it resolved are still
+// in the pub cache
+func checkPubPackages(path string, spec *pubspec, lock *pubLock) []Issue {
+	issues := []Issue{}
+	dartTool := filepath.Join(path, ".dart_tool")
+	configFile := filepath.Join(dartTool, "package_config.json")
+	data, err := os.ReadFile(configFile)
+	if err != nil {
+		return append(issues, Issue{
+			Severity:    SeverityWarning,
+			ProjectType: "Dart/Flutter",
+			Message:     "Dependencies not fetched (.dart_tool/package_config.json not found)",
+			Suggestion:  fmt.Sprintf("Run '%s' to fetch dependencies", spec.pubCommand()),
+		})
+	}
+	var config dartPackageConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return append(issues, Issue{
+			Severity:    SeverityError,
+			ProjectType: "Dart/Flutter",
+			Message:     fmt.Sprintf("Cannot parse .dart_tool/package_config.json: %v", err),
+			Suggestion:  fmt.Sprintf("Run '%s' to regenerate it", spec.pubCommand()),
+		})
+	}
+
+	// dart run and flutter run refuse to start in these cases too
+	configTime := modTime(configFile)
+	for _, file := range []string{"pubspec.yaml", "pubspec.lock"} {
+		if t := modTime(filepath.Join(path, file)); t > configTime {
+			issues = append(issues, Issue{
+				Severity:    SeverityWarning,
+				ProjectType: "Dart/Flutter",
+				Message:     fmt.Sprintf("%s has changed since dependencies were last fetched", file),
+				Suggestion:  fmt.Sprintf("Run '%s' to update .dart_tool/package_config.json", spec.pubCommand()),
+			})
+			break
+		}
+	}
+
+	resolved := map[string]bool{}
+	missing := []string{}
+	for _, pkg := range config.Packages {
+		resolved[pkg.Name] = true
+		if !pathExists(packageRoot(dartTool, pkg.RootURI)) {
+			missing = append(missing, pkg.Name)
+		}
+	}
+	if lock != nil {
+		for _, name := range lock.Packages {
+			if !resolved[name] && !containsString(missing, name) {
+				missing = append(missing, name)
+			}
+		}
+	}
+	sort.Strings(missing)
+	if len(missing) > 0 {
+		issues = append(issues, Issue{
+			Severity:    SeverityError,
+			ProjectType: "Dart/Flutter",
+			Message:     fmt.Sprintf("%d packages are missing from the pub cache or package_config.json: %s", len(missing), strings.Join(missing, ", ")),
+			Suggestion:  fmt.Sprintf("Run '%s' to fetch them", spec.pubCommand()),
+		})
+	}
+	return issues
+}
@@ -0,0 +1,117 @@
+package checker
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReadPubspec(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "pubspec.yaml"), []byte(`name: app
+environment:
+  sdk: ">=3.2.0 <4.0.0"
+  flutter: ">=3.16.0"
+dependencies:
+  flutter:
+    sdk: flutter
+  http: ^1.1.0
+`), 0644)
+	os.WriteFile(filepath.Join(dir, "pubspec.lock"), []byte(`packages:
+  http:
+    dependency: "direct main"
+    source: hosted
+    version: "1.1.2"
+  flutter:
+    dependency: "direct main"
+    source: sdk
+    version: "0.0.0"
+sdks:
+  dart: ">=3.2.3 <4.0.0"
+  flutter: ">=3.16.0"
+`), 0644)
+
+	spec, err := readPubspec(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if spec.SDK != ">=3.2.0 <4.0.0" || spec.Flutter != ">=3.16.0" || !spec.IsFlutter {
+		t.Errorf("readPubspec() = %+v", spec)
+	}
+	lock := readPubLock(dir)
+	if lock == nil || strings.Join(lock.Packages, ",") != "flutter,http" || lock.SDKs["dart"] != ">=3.2.3 <4.0.0" {
+		t.Errorf("readPubLock() = %+v", lock)
+	}
+}
+
+func TestCheckDartSDK(t *testing.T) {
+	spec := &pubspec{SDK: "^3.2.0", Flutter: ">=3.16.0", IsFlutter: true}
+	lock := &pubLock{SDKs: map[string]string{"dart": ">=3.2.3 <4.0.0", "flutter": ">=3.16.0"}}
+
+	tests := []struct {
+		dart, flutter string
+		want          []string
+	}{
+		{"3.2.3", "3.16.5", nil},
+		{"3.1.5", "3.13.9", []string{
+			"environment.sdk in pubspec.yaml requires Dart SDK ^3.2.0 but 3.1.5 is installed",
+			"environment.flutter in pubspec.yaml requires Flutter SDK >=3.16.0 but 3.13.9 is installed",
+		}},
+		{"3.2.0", "", []string{
+			"sdks in pubspec.lock requires Dart SDK >=3.2.3 <4.0.0 but 3.2.0 is installed",
+		}},
+	}
+	for _, tt := range tests {
+		got := []string{}
+		for _, issue := range checkDartSDK(spec, lock, tt.dart, tt.flutter) {
+			got = append(got, issue.Message)
+		}
+		if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+			t.Errorf("checkDartSDK(%q, %q) = %q, want %q", tt.dart, tt.flutter, got, tt.want)
+		}
+	}
+}
+
+func TestCheckPubPackages(t *testing.T) {
+	dir := t.TempDir()
+	spec := &pubspec{}
+	lock := &pubLock{Packages: []string{"http", "path", "meta"}}
+	os.WriteFile(filepath.Join(dir, "pubspec.yaml"), []byte("name: app\n"), 0644)
+	os.WriteFile(filepath.Join(dir, "pubspec.lock"), []byte("packages: {}\n"), 0644)
+
+	issues := checkPubPackages(dir, spec, lock)
+	if len(issues) != 1 || issues[0].Message != "Dependencies not fetched (.dart_tool/package_config.json not found)" {
+		t.Fatalf("checkPubPackages() without .dart_tool = %v", issues)
+	}
+
+	cache := t.TempDir()
+	os.MkdirAll(filepath.Join(cache, "http-1.1.2"), 0755)
+	os.MkdirAll(filepath.Join(dir, ".dart_tool"), 0755)
+	config := `{"configVersion": 2, "packages": [
+  {"name": "http", "rootUri": "file://` + filepath.ToSlash(filepath.Join(cache, "http-1.1.2")) + `", "packageUri": "lib/"},
+  {"name": "path", "rootUri": "file://` + filepath.ToSlash(filepath.Join(cache, "path-1.9.0")) + `", "packageUri": "lib/"},
+  {"name": "app", "rootUri": "../", "packageUri": "lib/"}
+]}`
+	configFile := filepath.Join(dir, ".dart_tool", "package_config.json")
+	os.WriteFile(configFile, []byte(config), 0644)
+	old := time.Now().Add(-time.Hour)
+	os.Chtimes(filepath.Join(dir, "pubspec.yaml"), old, old)
+	os.Chtimes(filepath.Join(dir, "pubspec.lock"), old, old)
+
+	got := []string{}
+	for _, issue := range checkPubPackages(dir, spec, lock) {
+		got = append(got, issue.Message)
+	}
+	want := []string{"2 packages are missing from the pub cache or package_config.json: meta, path"}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("checkPubPackages() = %q, want %q", got, want)
+	}
+
+	os.Chtimes(filepath.Join(dir, "pubspec.yaml"), time.Now().Add(time.Hour), time.Now().Add(time.Hour))
+	issues = checkPubPackages(dir, spec, lock)
+	if len(issues) == 0 || issues[0].Message != "pubspec.yaml has changed since dependencies were last fetched" {
+		t.Errorf("checkPubPackages() after editing pubspec.yaml = %v", issues)
+	}
+}
@@ -0,0 +1,321 @@
+package checker
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Sw3bbl3/devdoctor/internal/dockerfile"
+)
+
+// dockerfileNames returns the Dockerfiles in path: Dockerfile,
+// Dockerfile.<name> and <name>.Dockerfile
+func dockerfileNames(path string) []string {
+	names := []string{}
+	entries, _ := os.ReadDir(path)
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || strings.HasSuffix(name, ".dockerignore") {
+			continue
+		}
+		if name == "Dockerfile" || strings.HasPrefix(name, "Dockerfile.") || strings.HasSuffix(name, ".Dockerfile") {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// readDockerignore reads the ignore file BuildKit uses for a Dockerfile:
+// <Dockerfile>.dockerignore next to it, otherwise .dockerignore in the
+// build context. It returns nil if there is neither.
+func readDockerignore(context, dockerfilePath string) (*dockerfile.Ignore, string) {
+	for _, file := range []string{dockerfilePath + ".dockerignore", filepath.Join(context, ".dockerignore")} {
+		if data, err := os.ReadFile(file); err == nil {
+			return dockerfile.ParseIgnore(data), filepath.Base(file)
+		}
+	}
+	return nil, ""
+}
+
+// dockerfileChecker checks one Dockerfile whose build context is the
+// directory it is in
+type dockerfileChecker struct {
+	name    string
+	context string
+	df      *dockerfile.Dockerfile
+	ignore  *dockerfile.Ignore
+	global  map[string]string // ARGs declared before the first FROM
+	shipped map[string]bool   // context paths COPY and ADD send to the builder
+	issues  []Issue
+	lines   []int // line of each issue, to report them in file order
+}
+
+func (c *dockerfileChecker) add(severity Severity, line int, message, suggestion string) {
+	c.issues = append(c.issues, Issue{
+		Severity:    severity,
+		ProjectType: "Docker",
+		Message:     fmt.Sprintf("%s:%d: %s", c.name, line, message),
+		Suggestion:  suggestion,
+	})
+	c.lines = append(c.lines, line)
+}
+
+func (c *dockerfileChecker) Len() int           { return len(c.issues) }
+func (c *dockerfileChecker) Less(i, j int) bool { return c.lines[i] < c.lines[j] }
+func (c *dockerfileChecker) Swap(i, j int) {
+	c.issues[i], c.issues[j] = c.issues[j], c.issues[i]
+	c.lines[i], c.lines[j] = c.lines[j], c.lines[i]
+}
+
+// checkFrom reports base images without a tag or with :latest. Stage
+// references, scratch and images built from ARGs without defaults are
+// skipped.
+func (c *dockerfileChecker) checkFrom(stage *dockerfile.Stage) {
+	image, ok := dockerfile.Expand(stage.Image, c.global)
+	if !ok || image == "" || strings.EqualFold(image, "scratch") {
+		return
+	}
+	if parent := c.df.StageByName(image); parent != nil && parent.Index < stage.Index {
+		return
+	}
+	if strings.Contains(image, "@") {
+		return // pinned by digest
+	}
+	tag := ""
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		tag = image[i+1:]
+	}
+	switch tag {
+	case "":
+		c.add(SeverityWarning, stage.From.Line,
+			fmt.Sprintf("FROM %s has no tag, so it uses :latest", image),
+			fmt.Sprintf("Pin the base image to a version, e.g. %s:<version>, so builds are reproducible", image))
+	case "latest":
+		c.add(SeverityWarning, stage.From.Line,
+			fmt.Sprintf("FROM %s uses the :latest tag", image),
+			"Pin the base image to a version so builds are reproducible")
+	}
+}
+
+// checkArgs reports variables used before the ARG that declares them, and
+// global ARGs used in a stage without being redeclared there. ENV values
+// of the stage and of the stages it builds on count as declared.
+func (c *dockerfileChecker) checkArgs(stage *dockerfile.Stage, inherited map[string]bool) map[string]bool {
+	declaredAt := map[string]int{}
+	for _, inst := range stage.Instructions {
+		if inst.Cmd == "ARG" {
+			for name := range inst.Declared() {
+				if _, ok := declaredAt[name]; !ok {
+					declaredAt[name] = inst.Line
+				}
+			}
+		}
+	}
+
+	declared := map[string]bool{}
+	for name := range inherited {
+		declared[name] = true
+	}
+	reported := map[string]bool{}
+	for _, inst := range stage.Instructions {
+		if inst.Cmd != "CMD" && inst.Cmd != "ENTRYPOINT" && inst.Cmd != "HEALTHCHECK" {
+			for _, name := range dockerfile.Variables(inst.Raw) {
+				if declared[name] || reported[name] {
+					continue
+				}
+				if line, ok := declaredAt[name]; ok && line > inst.Line {
+					reported[name] = true
+					c.add(SeverityWarning, inst.Line,
+						fmt.Sprintf("%s uses $%s before 'ARG %s' on line %d, so it is empty here", inst.Cmd, name, name, line),
+						fmt.Sprintf("Move 'ARG %s' above line %d", name, inst.Line))
+				} else if _, global := c.global[name]; global && !ok {
+					reported[name] = true
+					c.add(SeverityWarning, inst.Line,
+						fmt.Sprintf("%s uses $%s, which is declared before the first FROM and not redeclared in this stage", inst.Cmd, name),
+						fmt.Sprintf("Add 'ARG %s' inside the stage to use the global value", name))
+				}
+			}
+		}
+		if inst.Cmd == "ARG" || inst.Cmd == "ENV" {
+			for name := range inst.Declared() {
+				declared[name] = true
+			}
+		}
+	}
+
+	env := map[string]bool{}
+	for name := range inherited {
+		env[name] = true
+	}
+	for _, inst := range stage.Instructions {
+		if inst.Cmd == "ENV" {
+			for name := range inst.Declared() {
+				env[name] = true
+			}
+		}
+	}
+	return env
+}
+
+// checkCopy checks the --from reference and the context sources of a COPY
+// or ADD instruction
+func (c *dockerfileChecker) checkCopy(stage *dockerfile.Stage, inst *dockerfile.Instruction, vars map[string]string) {
+	if from, ok := inst.Flags["from"]; ok {
+		if n, err := strconv.Atoi(from); err == nil {
+			if n >= stage.Index {
+				c.add(SeverityError, inst.Line,
+					fmt.Sprintf("%s --from=%s refers to a stage that is not defined before this one", inst.Cmd, from),
+					"Stages can only copy from earlier stages")
+			}
+		} else if ref := c.df.StageByName(from); ref != nil && ref.Index >= stage.Index {
+			c.add(SeverityError, inst.Line,
+				fmt.Sprintf("%s --from=%s refers to a stage defined later in the file", inst.Cmd, from),
+				fmt.Sprintf("Move stage '%s' above line %d", from, inst.Line))
+		} else if ref == nil && !strings.ContainsAny(from, ":/.$") {
+			c.add(SeverityWarning, inst.Line,
+				fmt.Sprintf("%s --from=%s does not match any stage, so Docker will pull an image named '%s'", inst.Cmd, from, from),
+				"Fix the stage name, or use a full image reference such as name:tag")
+		}
+		return
+	}
+
+	// The last argument is the destination; heredocs have no context source
+	if len(inst.Args) < 2 || strings.Contains(inst.Raw, "<<") {
+		return
+	}
+	for _, src := range inst.Args[:len(inst.Args)-1] {
+		if inst.Cmd == "ADD" && (strings.Contains(src, "://") || strings.HasPrefix(src, "git@")) {
+			continue
+		}
+		src, ok := dockerfile.Expand(src, vars)
+		if !ok {
+			continue
+		}
+		rel := filepath.Clean(strings.TrimPrefix(filepath.FromSlash(src), string(filepath.Separator)))
+		if rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			c.add(SeverityError, inst.Line,
+				fmt.Sprintf("%s source '%s' is outside the build context", inst.Cmd, src),
+				"Only files inside the build context can be copied; move the file or widen the context")
+			continue
+		}
+
+		matches, _ := filepath.Glob(filepath.Join(c.context, rel))
+		if len(matches) == 0 {
+			c.add(SeverityError, inst.Line,
+				fmt.Sprintf("%s source '%s' does not exist in the build context", inst.Cmd, src),
+				"Fix the path; COPY and ADD sources are relative to the build context")
+			continue
+		}
+		included := false
+		for _, match := range matches {
+			matchRel, _ := filepath.Rel(c.context, match)
+			if matchRel == "." || !c.ignore.Excluded(matchRel) {
+				included = true
+				c.shipped[matchRel] = true
+			}
+		}
+		if !included {
+			c.add(SeverityError, inst.Line,
+				fmt.Sprintf("%s source '%s' is excluded by .dockerignore", inst.Cmd, src),
+				"Remove the matching pattern from .dockerignore or add a '!' exception")
+		}
+	}
+}
+
+// checkShippedDirs reports node_modules and .git directories that COPY or
+// ADD send to the builder because no .dockerignore excludes them
+func (c *dockerfileChecker) checkShippedDirs(ignoreFile string) {
+	heavy := []string{}
+	for _, dir := range []string{"node_modules", ".git"} {
+		if !pathExists(filepath.Join(c.context, dir)) || c.ignore.Excluded(dir) {
+			continue
+		}
+		for shipped := range c.shipped {
+			if shipped == "." || shipped == dir {
+				heavy = append(heavy, dir)
+				break
+			}
+		}
+	}
+	if len(heavy) == 0 {
+		return
+	}
+	verb := "is"
+	if len(heavy) > 1 {
+		verb = "are"
+	}
+	message := fmt.Sprintf("%s: no .dockerignore, so %s %s copied into the image", c.name, strings.Join(heavy, " and "), verb)
+	if ignoreFile != "" {
+		message = fmt.Sprintf("%s: %s does not exclude %s, so %s copied into the image", c.name, ignoreFile, strings.Join(heavy, " and "), map[string]string{"is": "it is", "are": "they are"}[verb])
+	}
+	c.issues = append(c.issues, Issue{
+		Severity:    SeverityWarning,
+		ProjectType: "Docker",
+		Message:     message,
+		Suggestion:  fmt.Sprintf("Add %s to .dockerignore", strings.Join(heavy, " and ")),
+	})
+}
+
+// checkDockerfile parses a Dockerfile and checks it against its build
+// context, the directory it is in
+func checkDockerfile(path, name string) []Issue {
+	file := filepath.Join(path, name)
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil
+	}
+	ignore, ignoreFile := readDockerignore(path, file)
+	c := &dockerfileChecker{
+		name:    name,
+		context: path,
+		df:      dockerfile.Parse(data),
+		ignore:  ignore,
+		global:  map[string]string{},
+		shipped: map[string]bool{},
+	}
+	for _, inst := range c.df.Global {
+		for key, value := range inst.Declared() {
+			c.global[key] = value
+		}
+	}
+
+	// ENV is inherited by stages built on an earlier stage
+	stageEnv := map[int]map[string]bool{}
+	for _, stage := range c.df.Stages {
+		c.checkFrom(stage)
+
+		inherited := map[string]bool{}
+		if image, ok := dockerfile.Expand(stage.Image, c.global); ok {
+			if parent := c.df.StageByName(image); parent != nil && parent.Index < stage.Index {
+				inherited = stageEnv[parent.Index]
+			}
+		}
+		stageEnv[stage.Index] = c.checkArgs(stage, inherited)
+
+		vars := map[string]string{}
+		for _, inst := range stage.Instructions {
+			switch inst.Cmd {
+			case "ARG":
+				// A redeclared global ARG keeps the global value
+				for key, value := range inst.Declared() {
+					if global, ok := c.global[key]; ok && value == "" {
+						value = global
+					}
+					vars[key] = value
+				}
+			case "ENV":
+				for key, value := range inst.Declared() {
+					vars[key] = value
+				}
+			case "COPY", "ADD":
+				c.checkCopy(stage, inst, vars)
+			}
+		}
+	}
+	sort.Stable(c)
+	c.checkShippedDirs(ignoreFile)
+	return c.issues
+}
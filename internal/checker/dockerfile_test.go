@@ -0,0 +1,116 @@
+package checker
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func dockerfileMessages(t *testing.T, dir, content string) []string {
+	t.Helper()
+	os.WriteFile(filepath.Join(dir, "Dockerfile"), []byte(content), 0644)
+	messages := []string{}
+	for _, issue := range checkDockerfile(dir, "Dockerfile") {
+		messages = append(messages, issue.Message)
+	}
+	return messages
+}
+
+func TestCheckDockerfile(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "package.json"), []byte("{}"), 0644)
+	os.WriteFile(filepath.Join(dir, "secrets.env"), []byte("TOKEN=x"), 0644)
+	os.WriteFile(filepath.Join(dir, ".dockerignore"), []byte("*.env\n"), 0644)
+	os.MkdirAll(filepath.Join(dir, "node_modules", "left-pad"), 0755)
+
+	got := dockerfileMessages(t, dir, `ARG NODE_VERSION=20
+ARG APP_DIR=/app
+
+FROM node:${NODE_VERSION} AS build
+WORKDIR $APP_DIR
+COPY package.json package-lock.json ./
+COPY secrets.env ./
+ENV PORT=$PORT
+ARG PORT=3000
+COPY . .
+RUN npm run build
+
+FROM nginx
+COPY --from=build /app/dist /usr/share/nginx/html
+COPY --from=assets /static /usr/share/nginx/html/static
+COPY --from=2 /x /x
+
+FROM alpine:latest AS assets
+`)
+	want := []string{
+		"Dockerfile:5: WORKDIR uses $APP_DIR, which is declared before the first FROM and not redeclared in this stage",
+		"Dockerfile:6: COPY source 'package-lock.json' does not exist in the build context",
+		"Dockerfile:7: COPY source 'secrets.env' is excluded by .dockerignore",
+		"Dockerfile:8: ENV uses $PORT before 'ARG PORT' on line 9, so it is empty here",
+		"Dockerfile:13: FROM nginx has no tag, so it uses :latest",
+		"Dockerfile:15: COPY --from=assets refers to a stage defined later in the file",
+		"Dockerfile:16: COPY --from=2 refers to a stage that is not defined before this one",
+		"Dockerfile:18: FROM alpine:latest uses the :latest tag",
+		"Dockerfile: .dockerignore does not exclude node_modules, so it is copied into the image",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("checkDockerfile():\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestCheckDockerfileHereString(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, ".dockerignore"), []byte(".git\n"), 0644)
+
+	got := dockerfileMessages(t, dir, `FROM alpine:3.19
+RUN cat <<< "x" > /tmp/x
+COPY missing.conf /etc/app/
+`)
+	want := "Dockerfile:3: COPY source 'missing.conf' does not exist in the build context"
+	if len(got) != 1 || got[0] != want {
+		t.Errorf("checkDockerfile() = %q, want %q", got, want)
+	}
+}
+
+func TestCheckDockerfileClean(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, "src"), 0755)
+	os.MkdirAll(filepath.Join(dir, ".git"), 0755)
+	os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module x\n"), 0644)
+	os.WriteFile(filepath.Join(dir, ".dockerignore"), []byte(".git\n"), 0644)
+
+	got := dockerfileMessages(t, dir, `# syntax=docker/dockerfile:1
+ARG GO_VERSION=1.22
+FROM golang:${GO_VERSION}-alpine AS builder
+ARG TARGETOS
+ARG VERSION=dev
+WORKDIR /src
+COPY go.mod ./
+COPY src/ ./src/
+RUN GOOS=$TARGETOS go build -ldflags "-X main.version=${VERSION}" -o /out/app ./src
+
+FROM gcr.io/distroless/static@sha256:0123456789abcdef AS runtime
+COPY --from=builder /out/app /app
+COPY --from=busybox:1.36 /bin/sh /bin/sh
+FROM runtime
+COPY <<MOTD /etc/motd
+hello
+MOTD
+`)
+	if len(got) != 0 {
+		t.Errorf("checkDockerfile() reported issues for a clean Dockerfile:\n%s", strings.Join(got, "\n"))
+	}
+}
+
+func TestCheckDockerfileNoDockerignore(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, "node_modules"), 0755)
+	os.MkdirAll(filepath.Join(dir, ".git"), 0755)
+
+	got := dockerfileMessages(t, dir, "FROM node:20\nCOPY . .\n")
+	want := "Dockerfile: no .dockerignore, so node_modules and .git are copied into the image"
+	if len(got) != 1 || got[0] != want {
+		t.Errorf("checkDockerfile() = %q, want %q", got, want)
+	}
+}
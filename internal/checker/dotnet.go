@@ -0,0 +1,441 @@
+package checker
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Sw3bbl3/devdoctor/internal/semver"
+)
+
+// dotnetInstall lists the SDKs and the runtimes of each shared framework
+// (Microsoft.NETCore.App, Microsoft.AspNetCore.App, ...) that are installed
+type dotnetInstall struct {
+	SDKs     []string
+	Runtimes map[string][]string
+}
+
+// dotnetRoots returns the directories .NET may be installed in: DOTNET_ROOT,
+// the directory of the dotnet on PATH and the default install locations
+func dotnetRoots() []string {
+	roots := []string{}
+	if root := os.Getenv("DOTNET_ROOT"); root != "" {
+		roots = append(roots, root)
+	}
+	if exe, err := exec.LookPath("dotnet"); err == nil {
+		if resolved, err := filepath.EvalSymlinks(exe); err == nil {
+			exe = resolved
+		}
+		roots = append(roots, filepath.Dir(exe))
+	}
+	if runtime.GOOS == "windows" {
+		roots = append(roots, filepath.Join(os.Getenv("ProgramFiles"), "dotnet"))
+	} else {
+		roots = append(roots, "/usr/share/dotnet", "/usr/lib/dotnet", "/usr/local/share/dotnet")
+	}
+	return append(roots, userHomePath(".dotnet"))
+}
+
+// scanDotnet collects the SDKs and runtimes under the given roots
+func scanDotnet(roots []string) dotnetInstall {
+	install := dotnetInstall{Runtimes: map[string][]string{}}
+	seen := map[string]bool{}
+	add := func(list []string, dir string) []string {
+		entries, _ := os.ReadDir(dir)
+		for _, entry := range entries {
+			if _, err := semver.Parse(entry.Name()); entry.IsDir() && err == nil && !seen[filepath.Join(dir, entry.Name())] {
+				seen[filepath.Join(dir, entry.Name())] = true
+				list = append(list, entry.Name())
+			}
+		}
+		return list
+	}
+	for _, root := range roots {
+		install.SDKs = add(install.SDKs, filepath.Join(root, "sdk"))
+		frameworks, _ := os.ReadDir(filepath.Join(root, "shared"))
+		for _, fw := range frameworks {
+			install.Runtimes[fw.Name()] = add(install.Runtimes[fw.Name()], filepath.Join(root, "shared", fw.Name()))
+		}
+	}
+	sortDotnetVersions(install.SDKs)
+	for _, versions := range install.Runtimes {
+		sortDotnetVersions(versions)
+	}
+	return install
+}
+
+func sortDotnetVersions(versions []string) {
+	sort.Slice(versions, func(i, j int) bool {
+		a, _ := semver.Parse(versions[i])
+		b, _ := semver.Parse(versions[j])
+		return semver.Compare(a, b) < 0
+	})
+}
+
+// dotnetGlobalJSON is the sdk section of global.json
+type dotnetGlobalJSON struct {
+	File            string
+	Version         string
+	RollForward     string
+	AllowPrerelease bool
+}
+
+// findGlobalJSON reads the global.json the dotnet host uses for path: the
+// first one found in path or its parents
+func findGlobalJSON(path string) *dotnetGlobalJSON {
+	dir, err := filepath.Abs(path)
+	if err != nil {
+		return nil
+	}
+	for {
+		file := filepath.Join(dir, "global.json")
+		if data, err := os.ReadFile(file); err == nil {
+			var global struct {
+				SDK struct {
+					Version         string `json:"version"`
+					RollForward     string `json:"rollForward"`
+					AllowPrerelease *bool  `json:"allowPrerelease"`
+				} `json:"sdk"`
+			}
+			if json.Unmarshal(data, &global) != nil || global.SDK.Version == "" {
+				return nil
+			}
+			g := &dotnetGlobalJSON{
+				File:            file,
+				Version:         global.SDK.Version,
+				RollForward:     global.SDK.RollForward,
+				AllowPrerelease: global.SDK.AllowPrerelease == nil || *global.SDK.AllowPrerelease,
+			}
+			if g.RollForward == "" {
+				g.RollForward = "latestPatch"
+			}
+			return g
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return nil
+		}
+		dir = parent
+	}
+}
+
+// sdkAccepts reports whether an installed SDK satisfies the global.json
+// version under its rollForward policy. SDK patch numbers encode the
+// feature band: 8.0.403 is patch 3 of the 8.0.400 band.
+func (g *dotnetGlobalJSON) sdkAccepts(sdk string) bool {
+	want, err := semver.Parse(g.Version)
+	if err != nil {
+		return false
+	}
+	got, err := semver.Parse(sdk)
+	if err != nil || (got.Prerelease != "" && !g.AllowPrerelease && got.Prerelease != want.Prerelease) {
+		return false
+	}
+	if semver.Compare(got, want) < 0 {
+		return false
+	}
+	switch strings.ToLower(g.RollForward) {
+	case "disable":
+		return semver.Compare(got, want) == 0
+	case "patch", "latestpatch":
+		return got.Major == want.Major && got.Minor == want.Minor && got.Patch/100 == want.Patch/100
+	case "feature", "latestfeature":
+		return got.Major == want.Major && got.Minor == want.Minor
+	case "minor", "latestminor":
+		return got.Major == want.Major
+	}
+	return true // major, latestMajor
+}
+
+// describe explains which SDKs global.json accepts
+func (g *dotnetGlobalJSON) describe() string {
+	want, _ := semver.Parse(g.Version)
+	switch strings.ToLower(g.RollForward) {
+	case "disable":
+		return "exactly " + g.Version
+	case "patch", "latestpatch":
+		return fmt.Sprintf("%s or a later %d.%d.%dxx patch", g.Version, want.Major, want.Minor, want.Patch/100)
+	case "feature", "latestfeature":
+		return fmt.Sprintf("%s or a later %d.%d feature band", g.Version, want.Major, want.Minor)
+	case "minor", "latestminor":
+		return fmt.Sprintf("%s or a later %d.x release", g.Version, want.Major)
+	}
+	return g.Version + " or later"
+}
+
+// dotnetProject is the part of a project file the checks need
+type dotnetProject struct {
+	File       string
+	Frameworks []string // target framework monikers
+	Shared     []string // shared frameworks the project runs on
+}
+
+var slnProjectRe = regexp.MustCompile(`(?m)^Project\("[^"]*"\)\s*=\s*"[^"]*",\s*"([^"]+\.(?:cs|fs|vb)proj)"`)
+var slnxProjectRe = regexp.MustCompile(`<Project\s+Path="([^"]+\.(?:cs|fs|vb)proj)"`)
+
+// dotnetProjectFiles returns the project files in path and the ones the
+// solution files in path reference
+func dotnetProjectFiles(path string) []string {
+	files := []string{}
+	seen := map[string]bool{}
+	add := func(file string) {
+		file = filepath.Clean(file)
+		if !seen[file] {
+			seen[file] = true
+			files = append(files, file)
+		}
+	}
+	entries, _ := os.ReadDir(path)
+	for _, entry := range entries {
+		name := entry.Name()
+		switch filepath.Ext(name) {
+		case ".csproj", ".fsproj", ".vbproj":
+			add(filepath.Join(path, name))
+		case ".sln", ".slnx":
+			data, err := os.ReadFile(filepath.Join(path, name))
+			if err != nil {
+				continue
+			}
+			re := slnProjectRe
+			if filepath.Ext(name) == ".slnx" {
+				re = slnxProjectRe
+			}
+			for _, m := range re.FindAllStringSubmatch(string(data), -1) {
+				add(filepath.Join(path, filepath.FromSlash(strings.ReplaceAll(m[1], `\`, "/"))))
+			}
+		}
+	}
+	return files
+}
+
+type msbuildProject struct {
+	Sdk            string `xml:"Sdk,attr"`
+	PropertyGroups []struct {
+		TargetFramework  string
+		TargetFrameworks string
+		OutputType       string
+		UseWPF           string
+		UseWindowsForms  string
+		IsTestProject    string
+	} `xml:"PropertyGroup"`
+	ItemGroups []struct {
+		PackageReferences []struct {
+			Include string `xml:"Include,attr"`
+		} `xml:"PackageReference"`
+		FrameworkReferences []struct {
+			Include string `xml:"Include,attr"`
+		} `xml:"FrameworkReference"`
+	} `xml:"ItemGroup"`
+}
+
+// targetFrameworks returns the first TargetFramework or TargetFrameworks
+// value of the project
+func (p msbuildProject) targetFrameworks() string {
+	for _, pg := range p.PropertyGroups {
+		if tfms := strings.Trim(strings.TrimSpace(pg.TargetFramework)+";"+strings.TrimSpace(pg.TargetFrameworks), ";"); tfms != "" {
+			return tfms
+		}
+	}
+	return ""
+}
+
+// readDotnetProject reads the target frameworks of a project file, falling
+// back to Directory.Build.props in the project's directory or the scan root.
+// Only projects that run (apps and test projects) need shared frameworks.
+func readDotnetProject(path, file string) (*dotnetProject, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var proj msbuildProject
+	if err := xml.Unmarshal(data, &proj); err != nil {
+		return nil, fmt.Errorf("%s: %v", displayPath(path, file), err)
+	}
+
+	tfms, outputType, desktop, test := proj.targetFrameworks(), "", false, false
+	for _, pg := range proj.PropertyGroups {
+		if pg.OutputType != "" {
+			outputType = pg.OutputType
+		}
+		desktop = desktop || strings.EqualFold(pg.UseWPF, "true") || strings.EqualFold(pg.UseWindowsForms, "true")
+		test = test || strings.EqualFold(pg.IsTestProject, "true")
+	}
+	if tfms == "" {
+		for _, dir := range []string{filepath.Dir(file), path} {
+			if props, err := os.ReadFile(filepath.Join(dir, "Directory.Build.props")); err == nil {
+				var p msbuildProject
+				if xml.Unmarshal(props, &p) == nil {
+					tfms = p.targetFrameworks()
+				}
+				break
+			}
+		}
+	}
+
+	project := &dotnetProject{File: displayPath(path, file)}
+	for _, tfm := range strings.Split(tfms, ";") {
+		// Property references such as $(DefaultTargetFramework) are skipped
+		if tfm = strings.TrimSpace(tfm); tfm != "" && !strings.Contains(tfm, "$(") {
+			project.Frameworks = append(project.Frameworks, tfm)
+		}
+	}
+
+	for _, ig := range proj.ItemGroups {
+		for _, ref := range ig.PackageReferences {
+			test = test || ref.Include == "Microsoft.NET.Test.Sdk"
+		}
+	}
+	switch proj.Sdk {
+	case "Microsoft.NET.Sdk.Web", "Microsoft.NET.Sdk.Worker":
+		outputType = "Exe"
+	}
+
+	// Libraries are loaded by the app that references them
+	if !strings.EqualFold(outputType, "Exe") && !strings.EqualFold(outputType, "WinExe") && !test {
+		return project, nil
+	}
+	project.Shared = []string{"Microsoft.NETCore.App"}
+	if proj.Sdk == "Microsoft.NET.Sdk.Web" {
+		project.Shared = append(project.Shared, "Microsoft.AspNetCore.App")
+	}
+	if desktop {
+		project.Shared = append(project.Shared, "Microsoft.WindowsDesktop.App")
+	}
+	for _, ig := range proj.ItemGroups {
+		for _, ref := range ig.FrameworkReferences {
+			if !containsString(project.Shared, ref.Include) {
+				project.Shared = append(project.Shared, ref.Include)
+			}
+		}
+	}
+	sort.Strings(project.Shared)
+	return project, nil
+}
+
+var tfmRe = regexp.MustCompile(`^(?:net|netcoreapp)(\d+)\.(\d+)(?:-.*)?$`)
+
+// tfmVersion returns the .NET version a target framework moniker such as
+// net8.0, net8.0-windows or netcoreapp3.1 needs. .NET Framework (net48) and
+// .NET Standard monikers return false.
+func tfmVersion(tfm string) (major, minor int, ok bool) {
+	m := tfmRe.FindStringSubmatch(strings.ToLower(tfm))
+	if m == nil {
+		return 0, 0, false
+	}
+	major, _ = strconv.Atoi(m[1])
+	minor, _ = strconv.Atoi(m[2])
+	return major, minor, true
+}
+
+var runtimeInstallNames = map[string]string{
+	"Microsoft.NETCore.App":        "dotnet",
+	"Microsoft.AspNetCore.App":     "aspnetcore",
+	"Microsoft.WindowsDesktop.App": "windowsdesktop",
+}
+
+// checkDotnetSetup compares global.json and the target frameworks of the
+// projects with the SDKs and runtimes that are installed
+func checkDotnetSetup(path string, install dotnetInstall) []Issue {
+	issues := []Issue{}
+	if len(install.SDKs) == 0 && len(install.Runtimes) == 0 {
+		return issues // a missing dotnet is reported as a required tool
+	}
+
+	// The SDK the dotnet host selects: the newest one global.json accepts,
+	// or the newest one installed
+	sdk := ""
+	global := findGlobalJSON(path)
+	for _, v := range install.SDKs {
+		if global == nil || global.sdkAccepts(v) {
+			sdk = v
+		}
+	}
+	if global != nil && sdk == "" {
+		installed := "none"
+		if len(install.SDKs) > 0 {
+			installed = strings.Join(install.SDKs, ", ")
+		}
+		want, _ := semver.Parse(global.Version)
+		issues = append(issues, Issue{
+			Severity:    SeverityError,
+			ProjectType: ".NET",
+			Message: fmt.Sprintf("%s requires .NET SDK %s (rollForward: %s) but the installed SDKs are: %s",
+				displayPath(path, global.File), global.describe(), global.RollForward, installed),
+			Suggestion: fmt.Sprintf("Install .NET SDK %s from https://dotnet.microsoft.com/download/dotnet/%d.%d, or relax rollForward in global.json",
+				global.Version, want.Major, want.Minor),
+		})
+	}
+
+	for _, file := range dotnetProjectFiles(path) {
+		project, err := readDotnetProject(path, file)
+		if err != nil {
+			if !os.IsNotExist(err) {
+				issues = append(issues, Issue{
+					Severity:    SeverityWarning,
+					ProjectType: ".NET",
+					Message:     fmt.Sprintf("Cannot read project file: %v", err),
+					Suggestion:  "Fix the project file so MSBuild can load it",
+				})
+			} else {
+				issues = append(issues, Issue{
+					Severity:    SeverityError,
+					ProjectType: ".NET",
+					Message:     fmt.Sprintf("Project %s referenced by the solution does not exist", displayPath(path, file)),
+					Suggestion:  "Restore the project or remove it with 'dotnet sln remove'",
+				})
+			}
+			continue
+		}
+		issues = append(issues, checkDotnetProject(project, sdk, install)...)
+	}
+	return issues
+}
+
+// checkDotnetProject checks that the SDK can build each target framework of
+// a project and that the shared frameworks it runs on are installed. A
+// runtime rolls forward to a later minor version of the same major.
+func checkDotnetProject(project *dotnetProject, sdk string, install dotnetInstall) []Issue {
+	issues := []Issue{}
+	sdkVersion, sdkErr := semver.Parse(sdk)
+
+	for _, tfm := range project.Frameworks {
+		major, minor, ok := tfmVersion(tfm)
+		if !ok {
+			continue
+		}
+		if sdkErr == nil && (sdkVersion.Major < major || (sdkVersion.Major == major && sdkVersion.Minor < minor)) {
+			issues = append(issues, Issue{
+				Severity:    SeverityError,
+				ProjectType: ".NET",
+				Message:     fmt.Sprintf("%s targets %s but .NET SDK %s cannot build it; it needs SDK %d.%d or later", project.File, tfm, sdk, major, minor),
+				Suggestion:  fmt.Sprintf("Install the .NET %d.%d SDK from https://dotnet.microsoft.com/download/dotnet/%d.%d", major, minor, major, minor),
+			})
+		}
+
+		for _, fw := range project.Shared {
+			found := false
+			for _, v := range install.Runtimes[fw] {
+				if rv, err := semver.Parse(v); err == nil && rv.Major == major && rv.Minor >= minor {
+					found = true
+				}
+			}
+			if !found {
+				issues = append(issues, Issue{
+					Severity:    SeverityError,
+					ProjectType: ".NET",
+					Message:     fmt.Sprintf("%s (%s) needs the %s %d.%d runtime, which is not installed", project.File, tfm, fw, major, minor),
+					Suggestion:  fmt.Sprintf("Run 'dotnet-install.sh --channel %d.%d --runtime %s' or install it from https://dotnet.microsoft.com/download/dotnet/%d.%d", major, minor, runtimeInstallNames[fw], major, minor),
+				})
+			}
+		}
+	}
+	return issues
+}
@@ -0,0 +1,121 @@
+package checker
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// makeDotnetRoot creates a dotnet install directory with the given SDKs and
+// shared framework versions ("Microsoft.NETCore.App/8.0.11")
+func makeDotnetRoot(t *testing.T, sdks []string, runtimes []string) string {
+	t.Helper()
+	root := t.TempDir()
+	for _, sdk := range sdks {
+		os.MkdirAll(filepath.Join(root, "sdk", sdk), 0755)
+	}
+	os.MkdirAll(filepath.Join(root, "sdk", "NuGetFallbackFolder"), 0755)
+	for _, rt := range runtimes {
+		os.MkdirAll(filepath.Join(root, "shared", filepath.FromSlash(rt)), 0755)
+	}
+	return root
+}
+
+func TestScanDotnet(t *testing.T) {
+	root := makeDotnetRoot(t, []string{"8.0.404", "6.0.428", "9.0.100-rc.2.24474.11"}, []string{"Microsoft.NETCore.App/8.0.11"})
+	install := scanDotnet([]string{root, root})
+	if got := strings.Join(install.SDKs, " "); got != "6.0.428 8.0.404 9.0.100-rc.2.24474.11" {
+		t.Errorf("SDKs = %q", got)
+	}
+	if got := install.Runtimes["Microsoft.NETCore.App"]; len(got) != 1 || got[0] != "8.0.11" {
+		t.Errorf("Runtimes = %v", install.Runtimes)
+	}
+}
+
+func TestGlobalJSONRollForward(t *testing.T) {
+	tests := []struct {
+		rollForward string
+		sdk         string
+		want        bool
+	}{
+		{"", "8.0.105", true},
+		{"", "8.0.204", false},
+		{"disable", "8.0.101", false},
+		{"disable", "8.0.100", true},
+		{"latestFeature", "8.0.404", true},
+		{"latestFeature", "9.0.100", false},
+		{"latestMinor", "8.1.100", true},
+		{"latestMajor", "9.0.100", true},
+		{"latestMajor", "7.0.400", false},
+	}
+	for _, tt := range tests {
+		dir := t.TempDir()
+		content := `{"sdk": {"version": "8.0.100"}}`
+		if tt.rollForward != "" {
+			content = `{"sdk": {"version": "8.0.100", "rollForward": "` + tt.rollForward + `"}}`
+		}
+		os.WriteFile(filepath.Join(dir, "global.json"), []byte(content), 0644)
+		global := findGlobalJSON(dir)
+		if global == nil {
+			t.Fatal("findGlobalJSON() = nil")
+		}
+		if got := global.sdkAccepts(tt.sdk); got != tt.want {
+			t.Errorf("rollForward %q: sdkAccepts(%q) = %v, want %v", tt.rollForward, tt.sdk, got, tt.want)
+		}
+	}
+}
+
+func TestCheckDotnetSetup(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "global.json"), []byte(`{"sdk": {"version": "8.0.300", "rollForward": "latestPatch"}}`), 0644)
+	os.WriteFile(filepath.Join(dir, "App.sln"), []byte(`Microsoft Visual Studio Solution File, Format Version 12.00
+Project("{FAE04EC0-301F-11D3-BF4B-00C04F79EFBC}") = "Api", "src\Api\Api.csproj", "{11111111-1111-1111-1111-111111111111}"
+EndProject
+Project("{FAE04EC0-301F-11D3-BF4B-00C04F79EFBC}") = "Core", "src\Core\Core.csproj", "{22222222-2222-2222-2222-222222222222}"
+EndProject
+Project("{FAE04EC0-301F-11D3-BF4B-00C04F79EFBC}") = "Gone", "src\Gone\Gone.csproj", "{33333333-3333-3333-3333-333333333333}"
+EndProject
+`), 0644)
+	os.MkdirAll(filepath.Join(dir, "src", "Api"), 0755)
+	os.WriteFile(filepath.Join(dir, "src", "Api", "Api.csproj"), []byte(`<Project Sdk="Microsoft.NET.Sdk.Web">
+  <PropertyGroup>
+    <TargetFramework>net8.0</TargetFramework>
+  </PropertyGroup>
+</Project>`), 0644)
+	os.MkdirAll(filepath.Join(dir, "src", "Core"), 0755)
+	os.WriteFile(filepath.Join(dir, "src", "Core", "Core.csproj"), []byte(`<Project Sdk="Microsoft.NET.Sdk">
+  <PropertyGroup>
+    <TargetFrameworks>netstandard2.0;net8.0;net9.0</TargetFrameworks>
+  </PropertyGroup>
+</Project>`), 0644)
+
+	root := makeDotnetRoot(t, []string{"8.0.404"}, []string{"Microsoft.NETCore.App/8.0.11"})
+	issues := checkDotnetSetup(dir, scanDotnet([]string{root}))
+
+	want := []string{
+		"global.json requires .NET SDK 8.0.300 or a later 8.0.3xx patch (rollForward: latestPatch) but the installed SDKs are: 8.0.404",
+		filepath.Join("src", "Api", "Api.csproj") + " (net8.0) needs the Microsoft.AspNetCore.App 8.0 runtime, which is not installed",
+		"Project " + filepath.Join("src", "Gone", "Gone.csproj") + " referenced by the solution does not exist",
+	}
+	messages := []string{}
+	for _, issue := range issues {
+		messages = append(messages, issue.Message)
+	}
+	if strings.Join(messages, "\n") != strings.Join(want, "\n") {
+		t.Errorf("issues:\n%s\nwant:\n%s", strings.Join(messages, "\n"), strings.Join(want, "\n"))
+	}
+
+	// Once global.json accepts the installed SDK the target frameworks are checked
+	os.WriteFile(filepath.Join(dir, "global.json"), []byte(`{"sdk": {"version": "8.0.300", "rollForward": "latestFeature"}}`), 0644)
+	issues = checkDotnetSetup(dir, scanDotnet([]string{root}))
+	found := false
+	for _, issue := range issues {
+		if issue.Message == filepath.Join("src", "Core", "Core.csproj")+" targets net9.0 but .NET SDK 8.0.404 cannot build it; it needs SDK 9.0 or later" {
+			found = true
+		}
+	}
+	if !found || len(issues) != 3 {
+		t.Errorf("expected the net9.0 target to be reported, got %v", issues)
+	}
+}
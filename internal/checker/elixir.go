@@ -0,0 +1,216 @@
+package checker
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// elixirOTPSupport lists the Erlang/OTP releases each Elixir minor version
+// supports (https://hexdocs.pm/elixir/compatibility-and-deprecations.html)
+var elixirOTPSupport = []struct {
+	Elixir   string
+	Min, Max int
+}{
+	{"1.11", 21, 24},
+	{"1.12", 22, 24},
+	{"1.13", 22, 25},
+	{"1.14", 23, 26},
+	{"1.15", 24, 26},
+	{"1.16", 24, 26},
+	{"1.17", 25, 27},
+	{"1.18", 25, 28},
+	{"1.19", 26, 28},
+}
+
+// mixDep is a dependency locked in mix.lock
+type mixDep struct {
+	Name    string
+	Source  string // hex, git or path
+	Version string // the version of hex packages, the revision of git ones
+}
+
+var mixLockRe = regexp.MustCompile(`(?m)^\s*"([^"]+)":\s*\{:(hex|git|path),\s*(?::[A-Za-z0-9_]+|"[^"]*"),\s*"([^"]*)"`)
+
+// parseMixLock parses the entries of mix.lock, an Elixir map literal such
+// as %{"jason": {:hex, :jason, "1.4.1", "<hash>", [:mix], [], "hexpm", "<hash>"}}
+func parseMixLock(data string) []mixDep {
+	deps := []mixDep{}
+	for _, m := range mixLockRe.FindAllStringSubmatch(data, -1) {
+		deps = append(deps, mixDep{Name: m[1], Source: m[2], Version: m[3]})
+	}
+	return deps
+}
+
+var hexMetadataVersionRe = regexp.MustCompile(`\{<<"version">>,\s*<<"([^"]+)">>\}`)
+
+// fetchedMixDeps reports, for each locked dependency, what mix deps.get put
+// in deps/: "" if it is up to date, "missing" or the fetched version
+func fetchedMixDeps(path string, deps []mixDep) map[string]string {
+	state := map[string]string{}
+	for _, dep := range deps {
+		if dep.Source == "path" {
+			continue
+		}
+		dir := filepath.Join(path, "deps", dep.Name)
+		if !pathExists(dir) {
+			state[dep.Name] = "missing"
+			continue
+		}
+		if dep.Source != "hex" {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, "hex_metadata.config"))
+		if err != nil {
+			continue
+		}
+		if m := hexMetadataVersionRe.FindStringSubmatch(string(data)); m != nil && m[1] != dep.Version {
+			state[dep.Name] = m[1]
+		}
+	}
+	return state
+}
+
+// elixirSatisfies evaluates an Elixir version requirement such as
+// "~> 1.14", ">= 1.12.0 and < 2.0.0" or "~> 1.14 or ~> 2.0". Its ~> works
+// like RubyGems'.
+func elixirSatisfies(version, requirement string) (bool, error) {
+	for _, alt := range strings.Split(requirement, " or ") {
+		ok, err := gemSatisfies(version, strings.Split(alt, " and "))
+		if err != nil {
+			return false, err
+		}
+		if ok {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+var (
+	mixElixirRe     = regexp.MustCompile(`\belixir:\s*"([^"]+)"`)
+	elixirVersionRe = regexp.MustCompile(`(?m)^Elixir (\d+\.\d+\.\d+\S*)(?: \(compiled with Erlang/OTP (\d+)\))?`)
+	erlangVersionRe = regexp.MustCompile(`(?m)^Erlang/OTP (\d+)`)
+	elixirMinorRe   = regexp.MustCompile(`^\d+\.\d+`)
+)
+
+// elixirInstall is the Elixir and Erlang/OTP the project directory selects
+type elixirInstall struct {
+	Version      string
+	OTP          int // the OTP release Elixir runs on
+	CompiledWith int // the OTP release Elixir was compiled with
+}
+
+// parseElixirVersion parses the output of 'elixir --version'
+func parseElixirVersion(out string) elixirInstall {
+	var install elixirInstall
+	if m := elixirVersionRe.FindStringSubmatch(out); m != nil {
+		install.Version = m[1]
+		install.CompiledWith, _ = strconv.Atoi(m[2])
+	}
+	if m := erlangVersionRe.FindStringSubmatch(out); m != nil {
+		install.OTP, _ = strconv.Atoi(m[1])
+	}
+	return install
+}
+
+// installedElixir runs 'elixir --version' from the project directory, which
+// also starts the Erlang VM
+func installedElixir(path string) elixirInstall {
+	cmd := exec.Command("elixir", "--version")
+	cmd.Dir = path
+	out, err := cmd.Output()
+	if err != nil {
+		return elixirInstall{}
+	}
+	return parseElixirVersion(string(out))
+}
+
+// checkMixDeps reports locked dependencies that are missing from deps/ or
+// were fetched at another version
+func checkMixDeps(path string, deps []mixDep) []Issue {
+	issues := []Issue{}
+	state := fetchedMixDeps(path, deps)
+	stale := []string{}
+	for _, dep := range deps {
+		switch fetched := state[dep.Name]; fetched {
+		case "":
+		case "missing":
+			stale = append(stale, dep.Name)
+		default:
+			stale = append(stale, fmt.Sprintf("%s (%s fetched, %s locked)", dep.Name, fetched, dep.Version))
+		}
+	}
+	if len(stale) > 0 {
+		issues = append(issues, Issue{
+			Severity:    SeverityError,
+			ProjectType: "Elixir",
+			Message:     fmt.Sprintf("%d dependencies from mix.lock are not fetched or out of date: %s", len(stale), strings.Join(stale, ", ")),
+			Suggestion:  "Run 'mix deps.get' to fetch the locked versions",
+		})
+	}
+	return issues
+}
+
+// checkElixirVersions compares the elixir requirement of mix.exs with the
+// installed Elixir, and that Elixir with the OTP release it runs on
+func checkElixirVersions(path string, install elixirInstall) []Issue {
+	issues := []Issue{}
+	if install.Version == "" {
+		return issues
+	}
+
+	if data, err := os.ReadFile(filepath.Join(path, "mix.exs")); err == nil {
+		if m := mixElixirRe.FindStringSubmatch(string(data)); m != nil {
+			if ok, err := elixirSatisfies(install.Version, m[1]); err == nil && !ok {
+				issues = append(issues, Issue{
+					Severity:    SeverityError,
+					ProjectType: "Elixir",
+					Message:     fmt.Sprintf("mix.exs requires Elixir %s but %s is installed", m[1], install.Version),
+					Suggestion:  "Install a matching Elixir, e.g. with 'asdf install elixir <version>'",
+				})
+			}
+		}
+	}
+
+	if install.OTP == 0 {
+		return issues
+	}
+	// Code compiled for a newer OTP does not load on an older one
+	if install.CompiledWith > install.OTP {
+		issues = append(issues, Issue{
+			Severity:    SeverityError,
+			ProjectType: "Elixir",
+			Message:     fmt.Sprintf("Elixir %s was compiled with Erlang/OTP %d but runs on Erlang/OTP %d", install.Version, install.CompiledWith, install.OTP),
+			Suggestion:  fmt.Sprintf("Install an Elixir build for OTP %d (e.g. 'asdf install elixir %s-otp-%d') or upgrade Erlang", install.OTP, install.Version, install.OTP),
+		})
+		return issues
+	}
+	minor := elixirMinorRe.FindString(install.Version)
+	for _, entry := range elixirOTPSupport {
+		if entry.Elixir != minor {
+			continue
+		}
+		switch {
+		case install.OTP < entry.Min:
+			issues = append(issues, Issue{
+				Severity:    SeverityError,
+				ProjectType: "Elixir",
+				Message:     fmt.Sprintf("Elixir %s needs Erlang/OTP %d or later, but OTP %d is installed", install.Version, entry.Min, install.OTP),
+				Suggestion:  fmt.Sprintf("Install Erlang/OTP %d to %d", entry.Min, entry.Max),
+			})
+		case install.OTP > entry.Max:
+			issues = append(issues, Issue{
+				Severity:    SeverityWarning,
+				ProjectType: "Elixir",
+				Message:     fmt.Sprintf("Elixir %s supports Erlang/OTP %d to %d, but OTP %d is installed", install.Version, entry.Min, entry.Max, install.OTP),
+				Suggestion:  fmt.Sprintf("Install Erlang/OTP %d or a newer Elixir", entry.Max),
+			})
+		}
+	}
+	return issues
+}
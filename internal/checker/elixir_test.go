@@ -0,0 +1,112 @@
+package checker
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testMixLock = `%{
+  "jason": {:hex, :jason, "1.4.1", "af1504e35f629ddcdd6addb3513c3853991f694921b1b9368b0bd32beb9f1b63", [:mix], [{:decimal, "~> 1.0 or ~> 2.0", [hex: :decimal, repo: "hexpm", optional: true]}], "hexpm", "fbb01ecdfd565b56261302f7e1fcc27c4fb8f32d56eab74db621fc154604a7a1"},
+  "phoenix": {:hex, :phoenix, "1.7.10", "02189140a61b2ce85bb633a9b6fd02dff705a5f1596869547aeb2b2b95edd729", [:mix], [], "hexpm", "cf784932e010fd736d656d7fead6a584a4498efefe5b8227e9f383bf15bb79d0"},
+  "my_fork": {:git, "https://github.com/acme/my_fork.git", "9c2f1e8d0b7a6c5d4e3f2a1b0c9d8e7f6a5b4c3d", [branch: "main"]},
+}
+`
+
+func TestParseMixLock(t *testing.T) {
+	deps := parseMixLock(testMixLock)
+	want := []mixDep{
+		{Name: "jason", Source: "hex", Version: "1.4.1"},
+		{Name: "phoenix", Source: "hex", Version: "1.7.10"},
+		{Name: "my_fork", Source: "git", Version: "9c2f1e8d0b7a6c5d4e3f2a1b0c9d8e7f6a5b4c3d"},
+	}
+	if len(deps) != len(want) {
+		t.Fatalf("parseMixLock() = %v, want %v", deps, want)
+	}
+	for i := range want {
+		if deps[i] != want[i] {
+			t.Errorf("parseMixLock()[%d] = %v, want %v", i, deps[i], want[i])
+		}
+	}
+}
+
+func TestCheckMixDeps(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, "deps", "jason"), 0755)
+	os.MkdirAll(filepath.Join(dir, "deps", "phoenix"), 0755)
+	os.WriteFile(filepath.Join(dir, "deps", "jason", "hex_metadata.config"), []byte(`{<<"app">>,<<"jason">>}.
+{<<"version">>,<<"1.4.1">>}.
+`), 0644)
+	os.WriteFile(filepath.Join(dir, "deps", "phoenix", "hex_metadata.config"), []byte(`{<<"version">>,<<"1.7.9">>}.`), 0644)
+
+	issues := checkMixDeps(dir, parseMixLock(testMixLock))
+	want := "2 dependencies from mix.lock are not fetched or out of date: phoenix (1.7.9 fetched, 1.7.10 locked), my_fork"
+	if len(issues) != 1 || issues[0].Message != want {
+		t.Errorf("checkMixDeps() = %v, want %q", issues, want)
+	}
+}
+
+func TestParseElixirVersion(t *testing.T) {
+	out := "Erlang/OTP 25 [erts-13.2.2.5] [source] [64-bit] [smp:8:8] [ds:8:8:10] [async-threads:1] [jit]\n\nElixir 1.16.1 (compiled with Erlang/OTP 26)\n"
+	got := parseElixirVersion(out)
+	want := elixirInstall{Version: "1.16.1", OTP: 25, CompiledWith: 26}
+	if got != want {
+		t.Errorf("parseElixirVersion() = %+v, want %+v", got, want)
+	}
+}
+
+func TestCheckElixirVersions(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "mix.exs"), []byte(`defmodule App.MixProject do
+  use Mix.Project
+
+  def project do
+    [app: :app, version: "0.1.0", elixir: "~> 1.15", deps: deps()]
+  end
+end
+`), 0644)
+
+	tests := []struct {
+		name    string
+		install elixirInstall
+		want    []string
+	}{
+		{"supported", elixirInstall{Version: "1.16.1", OTP: 26, CompiledWith: 24}, nil},
+		{"not installed", elixirInstall{}, nil},
+		{"requirement", elixirInstall{Version: "1.14.5", OTP: 25, CompiledWith: 25}, []string{
+			"mix.exs requires Elixir ~> 1.15 but 1.14.5 is installed",
+		}},
+		{"compiled with newer OTP", elixirInstall{Version: "1.16.1", OTP: 25, CompiledWith: 26}, []string{
+			"Elixir 1.16.1 was compiled with Erlang/OTP 26 but runs on Erlang/OTP 25",
+		}},
+		{"OTP too old", elixirInstall{Version: "1.17.3", OTP: 24, CompiledWith: 24}, []string{
+			"Elixir 1.17.3 needs Erlang/OTP 25 or later, but OTP 24 is installed",
+		}},
+		{"OTP too new", elixirInstall{Version: "1.15.7", OTP: 27, CompiledWith: 24}, []string{
+			"Elixir 1.15.7 supports Erlang/OTP 24 to 26, but OTP 27 is installed",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := []string{}
+			for _, issue := range checkElixirVersions(dir, tt.install) {
+				got = append(got, issue.Message)
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("checkElixirVersions() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInstalledElixirRunsFromProject(t *testing.T) {
+	installDirShims(t, "1.16.2", map[string]string{"elixir": "Elixir $v (compiled with Erlang/OTP 26)"})
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, ".shim-version"), []byte("1.15.7\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got := installedElixir(dir); got.Version != "1.15.7" {
+		t.Errorf("installedElixir() = %+v, want the elixir the project selects", got)
+	}
+}
@@ -0,0 +1,116 @@
+package checker
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/Sw3bbl3/devdoctor/internal/dotenv"
+)
+
+// envExampleFiles are the committed templates of .env, in lookup order
+var envExampleFiles = []string{".env.example", ".env.sample", ".env.template", "env.example"}
+
+var placeholderRe = regexp.MustCompile(`(?i)^(?:change[-_ ]?me|change[-_ ]?this|replace[-_ ]?me|todo|tbd|fixme|placeholder|x{3,}|\*{3,}|<[^>]*>|\[[^\]]*\]|your[-_ ].*|.*[-_]here)$`)
+
+// isPlaceholder reports whether an .env value looks like a template value
+// that was never filled in, such as "changeme" or "<your-api-key>"
+func isPlaceholder(value string) bool {
+	return placeholderRe.MatchString(strings.TrimSpace(value))
+}
+
+// checkEnvFile compares .env with its committed example: keys missing from
+// .env, keys left empty or set to placeholders, and keys the example no
+// longer has
+func checkEnvFile(path string) []Issue {
+	issues := []Issue{}
+
+	exampleFile := ""
+	var example []dotenv.Entry
+	for _, name := range envExampleFiles {
+		if data, err := os.ReadFile(filepath.Join(path, name)); err == nil {
+			exampleFile, example = name, dotenv.Parse(data)
+			break
+		}
+	}
+	if exampleFile == "" {
+		return issues
+	}
+
+	data, err := os.ReadFile(filepath.Join(path, ".env"))
+	if err != nil {
+		issues = append(issues, Issue{
+			Severity:    SeverityWarning,
+			ProjectType: "General",
+			Message:     "Environment file (.env) not found",
+			Suggestion:  fmt.Sprintf("Copy %s to .env and configure your environment variables", exampleFile),
+		})
+		return issues
+	}
+	env := map[string]string{}
+	for _, entry := range dotenv.Parse(data) {
+		env[entry.Key] = entry.Value
+	}
+
+	exampleKeys := map[string]bool{}
+	missing, empty, placeholders := []string{}, []string{}, []string{}
+	for _, entry := range example {
+		if exampleKeys[entry.Key] {
+			continue
+		}
+		exampleKeys[entry.Key] = true
+		value, ok := env[entry.Key]
+		switch {
+		case !ok:
+			missing = append(missing, entry.Key)
+		case strings.TrimSpace(value) == "":
+			empty = append(empty, entry.Key)
+		case isPlaceholder(value):
+			placeholders = append(placeholders, entry.Key)
+		}
+	}
+	stale := []string{}
+	for key := range env {
+		if !exampleKeys[key] {
+			stale = append(stale, key)
+		}
+	}
+	sort.Strings(stale)
+
+	if len(missing) > 0 {
+		issues = append(issues, Issue{
+			Severity:    SeverityWarning,
+			ProjectType: "General",
+			Message:     fmt.Sprintf("%d keys from %s are missing in .env: %s", len(missing), exampleFile, strings.Join(missing, ", ")),
+			Suggestion:  fmt.Sprintf("Copy them from %s into .env and set their values", exampleFile),
+		})
+	}
+	if len(empty) > 0 {
+		issues = append(issues, Issue{
+			Severity:    SeverityWarning,
+			ProjectType: "General",
+			Message:     fmt.Sprintf("%d keys in .env are empty: %s", len(empty), strings.Join(empty, ", ")),
+			Suggestion:  "Set values for them in .env",
+		})
+	}
+	if len(placeholders) > 0 {
+		issues = append(issues, Issue{
+			Severity:    SeverityWarning,
+			ProjectType: "General",
+			Message:     fmt.Sprintf("%d keys in .env still have placeholder values: %s", len(placeholders), strings.Join(placeholders, ", ")),
+			Suggestion:  "Replace the placeholders with real values",
+		})
+	}
+	if len(stale) > 0 {
+		issues = append(issues, Issue{
+			Severity:    SeverityInfo,
+			ProjectType: "General",
+			Message:     fmt.Sprintf("%d keys in .env are no longer in %s: %s", len(stale), exampleFile, strings.Join(stale, ", ")),
+			Suggestion:  fmt.Sprintf("Remove them from .env, or add them to %s if they are still used", exampleFile),
+		})
+	}
+	return issues
+}
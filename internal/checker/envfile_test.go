@@ -0,0 +1,74 @@
+package checker
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCheckEnvFile(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, ".env.example"), []byte(`# App
+export APP_URL=http://localhost:3000
+API_KEY=your-api-key
+SECRET_KEY=changeme
+DATABASE_URL=
+SENTRY_DSN=
+PRIVATE_KEY="-----BEGIN KEY-----
+...
+-----END KEY-----"
+`), 0644)
+	os.WriteFile(filepath.Join(dir, ".env"), []byte(`APP_URL=http://localhost:3000
+API_KEY=<your-api-key>
+SECRET_KEY="changeme" # TODO
+DATABASE_URL=postgres://localhost/app
+SENTRY_DSN=
+OLD_FLAG=1
+`), 0644)
+
+	want := []string{
+		"WARNING 1 keys from .env.example are missing in .env: PRIVATE_KEY",
+		"WARNING 1 keys in .env are empty: SENTRY_DSN",
+		"WARNING 2 keys in .env still have placeholder values: API_KEY, SECRET_KEY",
+		"INFO 1 keys in .env are no longer in .env.example: OLD_FLAG",
+	}
+	got := []string{}
+	for _, issue := range checkEnvFile(dir) {
+		got = append(got, string(issue.Severity)+" "+issue.Message)
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("checkEnvFile():\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestCheckEnvFileMissing(t *testing.T) {
+	dir := t.TempDir()
+	if issues := checkEnvFile(dir); len(issues) != 0 {
+		t.Errorf("checkEnvFile() without an example = %v, want no issues", issues)
+	}
+
+	os.WriteFile(filepath.Join(dir, ".env.template"), []byte("A=1\n"), 0644)
+	issues := checkEnvFile(dir)
+	if len(issues) != 1 || issues[0].Message != "Environment file (.env) not found" || !strings.Contains(issues[0].Suggestion, ".env.template") {
+		t.Errorf("checkEnvFile() = %v", issues)
+	}
+}
+
+func TestIsPlaceholder(t *testing.T) {
+	for value, want := range map[string]bool{
+		"changeme":           true,
+		"CHANGE_ME":          true,
+		"<token>":            true,
+		"your_secret_here":   true,
+		"xxxxxx":             true,
+		"TODO":               true,
+		"s3cr3t":             false,
+		"http://example.com": false,
+		"production":         false,
+	} {
+		if got := isPlaceholder(value); got != want {
+			t.Errorf("isPlaceholder(%q) = %v, want %v", value, got, want)
+		}
+	}
+}
@@ -0,0 +1,289 @@
+package checker
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/Sw3bbl3/devdoctor/internal/detector"
+	"github.com/Sw3bbl3/devdoctor/internal/dotenv"
+	"github.com/Sw3bbl3/devdoctor/internal/yaml"
+)
+
+// maxEnvScanFiles and maxEnvScanSize bound the source scan for environment
+// variable reads
+const (
+	maxEnvScanFiles = 5000
+	maxEnvScanSize  = 1 << 20
+)
+
+// envReadPatterns are the source extensions and environment variable reads
+// of a language. Each pattern captures the variable name. Required reads
+// fail on an unset variable; optional ones return "", nil or None, which
+// the code usually branches on.
+type envReadPatterns struct {
+	Extensions []string
+	Required   []*regexp.Regexp
+	Optional   []*regexp.Regexp
+}
+
+// envReadLanguages maps project types to the way their code reads the
+// environment. Reads with a default, like os.getenv("X", "d"), are not
+// matched.
+var envReadLanguages = map[string]envReadPatterns{
+	"Node.js": {
+		[]string{".js", ".jsx", ".mjs", ".cjs", ".ts", ".tsx", ".mts", ".cts", ".vue", ".svelte"},
+		nil,
+		[]*regexp.Regexp{
+			regexp.MustCompile(`\bprocess\.env\.([A-Za-z_][A-Za-z0-9_]*)`),
+			regexp.MustCompile(`\bprocess\.env\[\s*["'` + "`" + `]([A-Za-z_][A-Za-z0-9_]*)["'` + "`" + `]\s*\]`),
+		},
+	},
+	"Python": {
+		[]string{".py"},
+		[]*regexp.Regexp{regexp.MustCompile(`\bos\.environ\[\s*["']([A-Za-z_][A-Za-z0-9_]*)["']\s*\]`)},
+		[]*regexp.Regexp{regexp.MustCompile(`\bos\.(?:getenv|environ\.get)\(\s*["']([A-Za-z_][A-Za-z0-9_]*)["']\s*\)`)},
+	},
+	"Go": {
+		[]string{".go"},
+		nil,
+		[]*regexp.Regexp{regexp.MustCompile(`\bos\.Getenv\(\s*"([A-Za-z_][A-Za-z0-9_]*)"\s*\)`)},
+	},
+	"Ruby": {
+		[]string{".rb", ".rake", ".erb"},
+		[]*regexp.Regexp{regexp.MustCompile(`\bENV\.fetch\(\s*["']([A-Za-z_][A-Za-z0-9_]*)["']\s*\)`)},
+		[]*regexp.Regexp{regexp.MustCompile(`\bENV\[\s*["']([A-Za-z_][A-Za-z0-9_]*)["']\s*\]`)},
+	},
+	"Rust": {
+		[]string{".rs"},
+		[]*regexp.Regexp{
+			regexp.MustCompile(`\benv::var\(\s*"([A-Za-z_][A-Za-z0-9_]*)"\s*\)`),
+			regexp.MustCompile(`\benv!\(\s*"([A-Za-z_][A-Za-z0-9_]*)"\s*\)`),
+		},
+		[]*regexp.Regexp{regexp.MustCompile(`\benv::var_os\(\s*"([A-Za-z_][A-Za-z0-9_]*)"\s*\)`)},
+	},
+	// Kotlin and Scala projects are scanned for their own sources, so a
+	// mixed project reports each file once
+	"Java":   {[]string{".java", ".groovy"}, nil, []*regexp.Regexp{systemGetenvRe}},
+	"Kotlin": {[]string{".kt", ".kts"}, nil, []*regexp.Regexp{systemGetenvRe}},
+	"Scala":  {[]string{".scala", ".sc"}, nil, []*regexp.Regexp{systemGetenvRe}},
+}
+
+// systemGetenvRe matches the JVM's System.getenv("X")
+var systemGetenvRe = regexp.MustCompile(`\bSystem\.getenv\(\s*"([A-Za-z_][A-Za-z0-9_]*)"\s*\)`)
+
+// envFallbackRe matches a fallback right after a read, as in
+// process.env.X || "d", os.getenv("X") or "d", env::var("X").unwrap_or(..)
+// and if v := os.Getenv("X"); v != "" {
+var envFallbackRe = regexp.MustCompile(`^\s*(?:\|\||\?\?|\?:|or\b|\.unwrap_or|\.or_else|\.or\(|\.is_ok\(|\.is_err\(|\.ok\(|\.is_some\(|\.is_none\(|;\s*\w+\s*[!=]=\s*"")`)
+
+// envScanSkipDirs are build output directories skipped in addition to the
+// detector's
+var envScanSkipDirs = map[string]bool{"dist": true, "build": true, "bin": true, "obj": true, "out": true, ".next": true, ".nuxt": true, "coverage": true}
+
+// envScanProjectMarkers are the manifests of a project. A directory below
+// the scanned one that has one is a sub-project with its own env files,
+// which is checked on its own, so the scan stops there.
+var envScanProjectMarkers = []string{
+	"package.json", "go.mod", "pyproject.toml", "setup.py", "requirements.txt", "Cargo.toml", "Gemfile",
+	"pom.xml", "build.gradle", "build.gradle.kts", "build.sbt", "composer.json", "mix.exs", "pubspec.yaml", "Package.swift",
+}
+
+// envScanTestDirs are test and fixture directories, whose reads configure
+// the tests rather than the application
+var envScanTestDirs = map[string]bool{"test": true, "tests": true, "__tests__": true, "__mocks__": true, "testdata": true, "spec": true, "e2e": true}
+
+// envTestFileRe matches test files: foo_test.go, test_foo.py, foo_test.py,
+// foo.test.ts, foo.spec.js, foo_spec.rb, FooTest.java and FooSpec.scala
+var envTestFileRe = regexp.MustCompile(`(?:_test\.(?:go|py|rs)|^test_[^/]*\.py|\.(?:test|spec)\.[a-z]+|_spec\.rb|(?:Test|Tests|Spec)\.(?:java|kt|groovy|scala))$`)
+
+// wellKnownEnv are variables set by the shell, the OS or the tools that run
+// the code rather than by the project
+var wellKnownEnv = map[string]bool{
+	"HOME": true, "PATH": true, "USER": true, "USERNAME": true, "USERPROFILE": true, "PWD": true, "SHELL": true,
+	"TERM": true, "TMPDIR": true, "TEMP": true, "TMP": true, "LANG": true, "HOSTNAME": true, "CI": true,
+	"NODE_ENV": true, "APPDATA": true, "LOCALAPPDATA": true, "XDG_CONFIG_HOME": true, "XDG_CACHE_HOME": true,
+	"XDG_DATA_HOME": true, "CARGO_MANIFEST_DIR": true, "CARGO_PKG_VERSION": true, "CARGO_PKG_NAME": true,
+	"OUT_DIR": true, "GOPATH": true, "GOROOT": true, "JAVA_HOME": true, "VIRTUAL_ENV": true,
+}
+
+// envRead is the first place the code reads a variable, or the first
+// required read if there is one
+type envRead struct {
+	Name     string
+	File     string
+	Line     int
+	Required bool
+}
+
+// scanEnvReads returns the environment variables the sources below path
+// read without a fallback, with the first place each is read. Tests and
+// nested sub-projects are skipped.
+func scanEnvReads(path string, lang envReadPatterns) []envRead {
+	extensions := map[string]bool{}
+	for _, ext := range lang.Extensions {
+		extensions[ext] = true
+	}
+
+	reads := map[string]envRead{}
+	scanned := 0
+	filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() {
+			if p != path && (detector.SkipDir(d.Name()) || envScanSkipDirs[d.Name()] || envScanTestDirs[d.Name()]) {
+				return filepath.SkipDir
+			}
+			if p != path {
+				for _, marker := range envScanProjectMarkers {
+					if fileExists(p, marker) {
+						return filepath.SkipDir
+					}
+				}
+			}
+			return nil
+		}
+		if !extensions[strings.ToLower(filepath.Ext(p))] || strings.HasSuffix(p, ".min.js") || envTestFileRe.MatchString(d.Name()) {
+			return nil
+		}
+		if scanned++; scanned > maxEnvScanFiles {
+			return filepath.SkipAll
+		}
+		if info, err := d.Info(); err != nil || info.Size() > maxEnvScanSize {
+			return nil
+		}
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return nil
+		}
+		content := string(data)
+		match := func(patterns []*regexp.Regexp, required bool) {
+			for _, re := range patterns {
+				for _, m := range re.FindAllStringSubmatchIndex(content, -1) {
+					name := content[m[2]:m[3]]
+					if read, seen := reads[name]; seen && (read.Required || !required) || envFallbackRe.MatchString(content[m[1]:]) {
+						continue
+					}
+					reads[name] = envRead{Name: name, File: displayPath(path, p), Line: strings.Count(content[:m[0]], "\n") + 1, Required: required}
+				}
+			}
+		}
+		match(lang.Required, true)
+		match(lang.Optional, false)
+		return nil
+	})
+
+	result := make([]envRead, 0, len(reads))
+	for _, read := range reads {
+		result = append(result, read)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
+
+// knownEnvVars returns the variables the project defines: the keys of .env
+// and .env.* files (examples, .env.local, ...) and of the environment
+// sections of its compose files
+func knownEnvVars(path string) map[string]bool {
+	known := map[string]bool{}
+	entries, _ := os.ReadDir(path)
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || (name != ".env" && !strings.HasPrefix(name, ".env.") && !containsString(envExampleFiles, name)) {
+			continue
+		}
+		if data, err := os.ReadFile(filepath.Join(path, name)); err == nil {
+			for _, e := range dotenv.Parse(data) {
+				known[e.Key] = true
+			}
+		}
+	}
+
+	for _, file := range composeFiles(path, composeEnv(path)) {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
+		doc, err := yaml.Decode(data)
+		if err != nil {
+			continue
+		}
+		services := yaml.Map(yaml.Lookup(doc, "services"))
+		for _, name := range yaml.Keys(services) {
+			// environment: is a mapping or a list of KEY=value
+			environment := yaml.Lookup(services[name], "environment")
+			for key := range yaml.Map(environment) {
+				known[key] = true
+			}
+			for _, item := range yaml.List(environment) {
+				key, _, _ := strings.Cut(yaml.String(item), "=")
+				known[key] = true
+			}
+		}
+	}
+	return known
+}
+
+// checkEnvReads reports environment variables the project's code reads
+// that are neither defined by the project's env files nor set in the
+// current environment
+func checkEnvReads(path string, project *detector.ProjectType) []Issue {
+	issues := []Issue{}
+	lang, ok := envReadLanguages[project.Name]
+	if !ok {
+		return issues
+	}
+
+	reads := scanEnvReads(path, lang)
+	if len(reads) == 0 {
+		return issues
+	}
+	known := knownEnvVars(path)
+
+	required, optional := []string{}, []string{}
+	for _, read := range reads {
+		if known[read.Name] || wellKnownEnv[read.Name] {
+			continue
+		}
+		if _, set := os.LookupEnv(read.Name); set {
+			continue
+		}
+		entry := fmt.Sprintf("%s (%s:%d)", read.Name, read.File, read.Line)
+		if read.Required {
+			required = append(required, entry)
+		} else {
+			optional = append(optional, entry)
+		}
+	}
+	if len(required) > 0 {
+		issues = append(issues, Issue{
+			Severity:    SeverityWarning,
+			ProjectType: project.Name,
+			Message:     missingEnvMessage(required),
+			Suggestion:  "Add them to .env.example and .env, or set them in your shell",
+		})
+	}
+	// Optional reads return "", nil or None, which the code may handle
+	if len(optional) > 0 {
+		issues = append(issues, Issue{
+			Severity:    SeverityInfo,
+			ProjectType: project.Name,
+			Message:     missingEnvMessage(optional),
+			Suggestion:  "If the code needs them, add them to .env.example and .env, or set them in your shell",
+		})
+	}
+	return issues
+}
+
+// missingEnvMessage describes environment variables that are not defined
+func missingEnvMessage(missing []string) string {
+	if len(missing) == 1 {
+		return fmt.Sprintf("1 environment variable read by the code is not in .env, .env.example or the environment: %s", missing[0])
+	}
+	return fmt.Sprintf("%d environment variables read by the code are not in .env, .env.example or the environment: %s", len(missing), strings.Join(missing, ", "))
+}
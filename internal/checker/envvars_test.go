@@ -0,0 +1,208 @@
+package checker
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Sw3bbl3/devdoctor/internal/detector"
+)
+
+func TestScanEnvReads(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, "src"), 0755)
+	os.MkdirAll(filepath.Join(dir, "node_modules", "dep"), 0755)
+	os.WriteFile(filepath.Join(dir, "src", "app.ts"), []byte(`const url = process.env.DATABASE_URL;
+const key = process.env["API_KEY"];
+const port = process.env.PORT || 3000;
+const host = process.env.HOST ?? "localhost";
+`), 0644)
+	os.WriteFile(filepath.Join(dir, "node_modules", "dep", "index.js"), []byte("process.env.DEP_SECRET"), 0644)
+	os.WriteFile(filepath.Join(dir, "src", "notes.md"), []byte("process.env.NOT_CODE"), 0644)
+
+	reads := scanEnvReads(dir, envReadLanguages["Node.js"])
+	got := []string{}
+	for _, read := range reads {
+		got = append(got, fmt.Sprintf("%s@%s:%d", read.Name, filepath.ToSlash(read.File), read.Line))
+	}
+	want := []string{"API_KEY@src/app.ts:2", "DATABASE_URL@src/app.ts:1"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("scanEnvReads() = %v, want %v", got, want)
+	}
+}
+
+func TestScanEnvReadsSkipsTests(t *testing.T) {
+	dir := t.TempDir()
+	write := func(rel, content string) {
+		file := filepath.Join(dir, filepath.FromSlash(rel))
+		os.MkdirAll(filepath.Dir(file), 0755)
+		os.WriteFile(file, []byte(content), 0644)
+	}
+	write("main.go", `package main
+
+var addr = os.Getenv("LISTEN_ADDR")
+`)
+	write("main_test.go", `package main
+
+var token = os.Getenv("DEVDOCTOR_TEST_TOKEN")
+`)
+	write("testdata/fixture.go", `var home = os.Getenv("RUSTUP_HOME")`)
+	write("web/src/app.test.ts", `process.env.TEST_ONLY`)
+
+	got := []string{}
+	for _, read := range scanEnvReads(dir, envReadLanguages["Go"]) {
+		got = append(got, read.Name)
+	}
+	if strings.Join(got, ",") != "LISTEN_ADDR" {
+		t.Errorf("scanEnvReads() = %v, want only LISTEN_ADDR", got)
+	}
+	if reads := scanEnvReads(dir, envReadLanguages["Node.js"]); len(reads) != 0 {
+		t.Errorf("scanEnvReads() read a .test.ts file: %v", reads)
+	}
+}
+
+func TestScanEnvReadsSkipsSubProjects(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"package.json":          `{"workspaces": ["apps/*"]}`,
+		"scripts/deploy.js":     "process.env.DEPLOY_TOKEN",
+		"apps/api/package.json": `{"name": "api"}`,
+		"apps/api/.env":         "API_SECRET=x\n",
+		"apps/api/index.js":     "process.env.API_SECRET",
+	}
+	for rel, content := range files {
+		file := filepath.Join(dir, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	reads := scanEnvReads(dir, envReadLanguages["Node.js"])
+	if len(reads) != 1 || reads[0].Name != "DEPLOY_TOKEN" {
+		t.Errorf("scanEnvReads() at the workspace root = %v, want only DEPLOY_TOKEN", reads)
+	}
+	if issues := checkEnvReads(filepath.Join(dir, "apps", "api"), &detector.ProjectType{Name: "Node.js"}); len(issues) != 0 {
+		t.Errorf("checkEnvReads() in the sub-project = %v, want none", issues)
+	}
+}
+
+func TestEnvReadPatterns(t *testing.T) {
+	tests := []struct {
+		lang   string
+		source string
+		want   []string
+	}{
+		{"Python", `a = os.environ["SECRET_KEY"]
+b = os.getenv("REDIS_URL")
+c = os.getenv("DEBUG", "0")
+d = os.environ.get('SENTRY_DSN')
+e = os.environ.get("LOG_LEVEL", "info")
+f = os.getenv("MODE") or "dev"`, []string{"REDIS_URL", "SECRET_KEY", "SENTRY_DSN"}},
+		{"Go", `token := os.Getenv("GITHUB_TOKEN")
+if dir := os.Getenv("CACHE_DIR"); dir != "" {
+}
+home, ok := os.LookupEnv("APP_HOME")`, []string{"GITHUB_TOKEN"}},
+		{"Ruby", `a = ENV["STRIPE_KEY"]
+b = ENV.fetch("QUEUE")
+c = ENV.fetch("THREADS", 5)
+d = ENV["RACK_ENV"] || "development"`, []string{"QUEUE", "STRIPE_KEY"}},
+		{"Rust", `let a = std::env::var("DATABASE_URL").expect("set");
+let b = env::var("PORT").unwrap_or("8080".into());
+let c = env!("BUILD_ID");`, []string{"BUILD_ID", "DATABASE_URL"}},
+		{"Java", `String a = System.getenv("JDBC_URL");`, []string{"JDBC_URL"}},
+		{"Kotlin", `val a = System.getenv("KAFKA_BROKERS")`, []string{"KAFKA_BROKERS"}},
+		{"Scala", `val a = System.getenv("SPARK_MASTER")`, []string{"SPARK_MASTER"}},
+	}
+	extension := map[string]string{"Python": "app.py", "Go": "main.go", "Ruby": "app.rb", "Rust": "main.rs", "Java": "App.java", "Kotlin": "App.kt", "Scala": "App.scala"}
+
+	for _, tt := range tests {
+		t.Run(tt.lang, func(t *testing.T) {
+			dir := t.TempDir()
+			os.WriteFile(filepath.Join(dir, extension[tt.lang]), []byte(tt.source), 0644)
+			got := []string{}
+			for _, read := range scanEnvReads(dir, envReadLanguages[tt.lang]) {
+				got = append(got, read.Name)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("scanEnvReads() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScanEnvReadsMixedJVMProject(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "Legacy.java"), []byte(`String a = System.getenv("JAVA_ONLY");`), 0644)
+	os.WriteFile(filepath.Join(dir, "App.kt"), []byte(`val a = System.getenv("KOTLIN_ONLY")`), 0644)
+
+	for lang, want := range map[string]string{"Java": "JAVA_ONLY", "Kotlin": "KOTLIN_ONLY"} {
+		reads := scanEnvReads(dir, envReadLanguages[lang])
+		if len(reads) != 1 || reads[0].Name != want {
+			t.Errorf("scanEnvReads() for %s = %v, want only %s", lang, reads, want)
+		}
+	}
+}
+
+func TestCheckEnvReads(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "main.go"), []byte(`package main
+
+import "os"
+
+func main() {
+	_ = os.Getenv("DEVDOCTOR_TEST_FROM_EXAMPLE")
+	_ = os.Getenv("DEVDOCTOR_TEST_FROM_COMPOSE")
+	_ = os.Getenv("DEVDOCTOR_TEST_FROM_SHELL")
+	_ = os.Getenv("HOME")
+	_ = os.Getenv("DEVDOCTOR_TEST_UNDEFINED")
+}
+`), 0644)
+	os.WriteFile(filepath.Join(dir, ".env.example"), []byte("DEVDOCTOR_TEST_FROM_EXAMPLE=\n"), 0644)
+	os.WriteFile(filepath.Join(dir, "compose.yaml"), []byte(`services:
+  app:
+    environment:
+      - DEVDOCTOR_TEST_FROM_COMPOSE=1
+`), 0644)
+	t.Setenv("DEVDOCTOR_TEST_FROM_SHELL", "1")
+
+	issues := checkEnvReads(dir, &detector.ProjectType{Name: "Go"})
+	if len(issues) != 1 {
+		t.Fatalf("checkEnvReads() returned %d issues, want 1: %v", len(issues), issues)
+	}
+	want := "1 environment variable read by the code is not in .env, .env.example or the environment: DEVDOCTOR_TEST_UNDEFINED (main.go:10)"
+	// os.Getenv returns "" for an unset variable, which the code may handle
+	if issues[0].Message != want || issues[0].Severity != SeverityInfo {
+		t.Errorf("checkEnvReads() = %q, want %q", issues[0].Message, want)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "app.py"), []byte("a = os.environ[\"DEVDOCTOR_TEST_REQUIRED\"]\nb = os.getenv(\"DEVDOCTOR_TEST_OPTIONAL\")\nc = os.getenv(\"DEVDOCTOR_TEST_BOTH\")\nd = os.environ[\"DEVDOCTOR_TEST_BOTH\"]\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	// os.environ[...] raises KeyError, os.getenv returns None
+	issues = checkEnvReads(dir, &detector.ProjectType{Name: "Python"})
+	if len(issues) != 2 || issues[0].Severity != SeverityWarning || issues[1].Severity != SeverityInfo {
+		t.Fatalf("checkEnvReads() for Python = %v, want a warning and an info", issues)
+	}
+	if want := "2 environment variables read by the code are not in .env, .env.example or the environment: DEVDOCTOR_TEST_BOTH (app.py:4), DEVDOCTOR_TEST_REQUIRED (app.py:1)"; issues[0].Message != want {
+		t.Errorf("checkEnvReads() warning = %q, want %q", issues[0].Message, want)
+	}
+	if !strings.HasSuffix(issues[1].Message, ": DEVDOCTOR_TEST_OPTIONAL (app.py:2)") {
+		t.Errorf("checkEnvReads() info = %q", issues[1].Message)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "index.js"), []byte("const url = process.env.DEVDOCTOR_TEST_UNDEFINED;\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if issues := checkEnvReads(dir, &detector.ProjectType{Name: "Node.js"}); len(issues) != 1 || issues[0].Severity != SeverityInfo {
+		t.Errorf("checkEnvReads() for Node.js = %v, want one info", issues)
+	}
+
+	if issues := checkEnvReads(dir, &detector.ProjectType{Name: "Docker"}); len(issues) != 0 {
+		t.Errorf("checkEnvReads() for a project type without patterns = %v, want none", issues)
+	}
+}
@@ -0,0 +1,557 @@
+package checker
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/Sw3bbl3/devdoctor/internal/detector"
+	"github.com/Sw3bbl3/devdoctor/internal/yaml"
+)
+
+const (
+	// lfsPointerHeader starts every Git LFS pointer file
+	lfsPointerHeader = "version https://git-lfs.github.com/spec/v1"
+	// maxLFSScanFiles bounds the walk for LFS-tracked files
+	maxLFSScanFiles = 50000
+	// maxListed bounds the file names an issue lists
+	maxListed = 5
+)
+
+// gitRepo locates the parts of a repository the checks read
+type gitRepo struct {
+	WorkTree string
+	GitDir   string // .git, or .git/worktrees/<name> for a linked worktree
+	Common   string // the directory holding config, hooks and shallow
+	Config   map[string]map[string]string
+}
+
+// gitHooks are the client-side hooks hook managers install
+var gitHooks = map[string]bool{
+	"applypatch-msg": true, "pre-applypatch": true, "post-applypatch": true, "pre-commit": true,
+	"pre-merge-commit": true, "prepare-commit-msg": true, "commit-msg": true, "post-commit": true,
+	"pre-rebase": true, "post-checkout": true, "post-merge": true, "pre-push": true,
+	"post-rewrite": true, "pre-auto-gc": true,
+}
+
+// historyTools are the dependencies and files of tools that read tags or
+// the commit history to derive versions, changelogs or affected projects
+var historyTools = struct {
+	Node   []string
+	Python []string
+	Files  [][2]string // file, tool
+}{
+	Node:   []string{"semantic-release", "standard-version", "release-it", "lerna", "@changesets/cli", "conventional-changelog-cli"},
+	Python: []string{"setuptools-scm", "setuptools_scm", "hatch-vcs", "versioneer", "poetry-dynamic-versioning"},
+	Files: [][2]string{
+		{"GitVersion.yml", "GitVersion"},
+		{"cliff.toml", "git-cliff"},
+		{".goreleaser.yml", "GoReleaser"},
+		{".goreleaser.yaml", "GoReleaser"},
+	},
+}
+
+var submoduleStatusRe = regexp.MustCompile(`^([ +\-U])([0-9a-f]{40}) (\S+)`)
+
+// findGitRepo returns the repository path belongs to, or nil. A .git file
+// points to the git directory of a linked worktree or a submodule.
+func findGitRepo(path string) *gitRepo {
+	dir := path
+	for {
+		dotGit := filepath.Join(dir, ".git")
+		if info, err := os.Stat(dotGit); err == nil {
+			repo := &gitRepo{WorkTree: dir, GitDir: dotGit}
+			if !info.IsDir() {
+				data, err := os.ReadFile(dotGit)
+				if err != nil {
+					return nil
+				}
+				gitDir, ok := strings.CutPrefix(strings.TrimSpace(string(data)), "gitdir:")
+				if !ok {
+					return nil
+				}
+				repo.GitDir = strings.TrimSpace(gitDir)
+				if !filepath.IsAbs(repo.GitDir) {
+					repo.GitDir = filepath.Join(dir, repo.GitDir)
+				}
+			}
+			repo.Common = repo.GitDir
+			if data, err := os.ReadFile(filepath.Join(repo.GitDir, "commondir")); err == nil {
+				repo.Common = strings.TrimSpace(string(data))
+				if !filepath.IsAbs(repo.Common) {
+					repo.Common = filepath.Join(repo.GitDir, repo.Common)
+				}
+			}
+			data, _ := os.ReadFile(filepath.Join(repo.Common, "config"))
+			repo.Config = parseGitConfig(string(data))
+			return repo
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return nil
+		}
+		dir = parent
+	}
+}
+
+// parseGitConfig reads a git config file such as .git/config or .gitmodules
+// into section -> key -> value. Subsections are joined with a dot, as in
+// submodule.lib/foo; section and key names are lower-cased.
+func parseGitConfig(data string) map[string]map[string]string {
+	config := map[string]map[string]string{}
+	section := ""
+	scanner := bufio.NewScanner(strings.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' || line[0] == ';' {
+			continue
+		}
+		if strings.HasPrefix(line, "[") {
+			header := strings.Trim(line[:strings.Index(line+"]", "]")], "[")
+			name, sub, ok := strings.Cut(header, " ")
+			section = strings.ToLower(name)
+			if ok {
+				section += "." + strings.Trim(strings.TrimSpace(sub), `"`)
+			}
+			if config[section] == nil {
+				config[section] = map[string]string{}
+			}
+			continue
+		}
+		if section == "" {
+			continue
+		}
+		key, value, _ := strings.Cut(line, "=")
+		config[section][strings.ToLower(strings.TrimSpace(key))] = strings.Trim(strings.TrimSpace(value), `"`)
+	}
+	return config
+}
+
+// submoduleState is a line of 'git submodule status'
+type submoduleState struct {
+	Path   string
+	Commit string // the checked out commit, or the recorded one if not initialised
+	// Flag is ' ' when up to date, '-' when not initialised, '+' when
+	// another commit is checked out and 'U' when it has merge conflicts
+	Flag byte
+}
+
+// parseSubmoduleStatus parses the output of 'git submodule status'
+func parseSubmoduleStatus(out string) []submoduleState {
+	states := []submoduleState{}
+	for _, line := range strings.Split(out, "\n") {
+		if m := submoduleStatusRe.FindStringSubmatch(line); m != nil {
+			states = append(states, submoduleState{Path: m[3], Commit: m[2], Flag: m[1][0]})
+		}
+	}
+	return states
+}
+
+// submoduleStates returns the state of the submodules in .gitmodules. Without
+// git, a submodule counts as initialised when its checkout has a .git.
+func submoduleStates(repo *gitRepo) []submoduleState {
+	data, err := os.ReadFile(filepath.Join(repo.WorkTree, ".gitmodules"))
+	if err != nil {
+		return nil
+	}
+	if isCommandAvailable("git") {
+		if out, err := exec.Command("git", "-C", repo.WorkTree, "submodule", "status").Output(); err == nil {
+			return parseSubmoduleStatus(string(out))
+		}
+	}
+
+	states := []submoduleState{}
+	for section, values := range parseGitConfig(string(data)) {
+		if !strings.HasPrefix(section, "submodule.") || values["path"] == "" {
+			continue
+		}
+		state := submoduleState{Path: values["path"], Flag: ' '}
+		if !pathExists(filepath.Join(repo.WorkTree, filepath.FromSlash(values["path"]), ".git")) {
+			state.Flag = '-'
+		}
+		states = append(states, state)
+	}
+	sort.Slice(states, func(i, j int) bool { return states[i].Path < states[j].Path })
+	return states
+}
+
+// recordedSubmoduleCommits returns the commits the index records for the
+// given submodule paths
+func recordedSubmoduleCommits(worktree string, paths []string) map[string]string {
+	commits := map[string]string{}
+	args := append([]string{"-C", worktree, "ls-files", "--stage", "--"}, paths...)
+	out, err := exec.Command("git", args...).Output()
+	if err != nil {
+		return commits
+	}
+	for _, line := range strings.Split(string(out), "\n") {
+		// 160000 <commit> 0	<path>
+		meta, path, ok := strings.Cut(line, "\t")
+		if fields := strings.Fields(meta); ok && len(fields) == 3 && fields[0] == "160000" {
+			commits[path] = fields[1]
+		}
+	}
+	return commits
+}
+
+// checkSubmodules reports submodules that were never initialised or are
+// checked out at another commit than the one recorded
+func checkSubmodules(states []submoduleState, recorded map[string]string) []Issue {
+	issues := []Issue{}
+	uninitialised, moved := []string{}, []string{}
+	for _, state := range states {
+		switch state.Flag {
+		case '-':
+			uninitialised = append(uninitialised, state.Path)
+		case '+', 'U':
+			description := fmt.Sprintf("%s (checked out at %s", state.Path, shortCommit(state.Commit))
+			if commit := recorded[state.Path]; commit != "" {
+				description += ", recorded " + shortCommit(commit)
+			}
+			moved = append(moved, description+")")
+		}
+	}
+
+	if len(uninitialised) > 0 {
+		issues = append(issues, Issue{
+			Severity:    SeverityError,
+			ProjectType: "Git",
+			Message:     fmt.Sprintf("%d submodules in .gitmodules are not initialised: %s", len(uninitialised), strings.Join(uninitialised, ", ")),
+			Suggestion:  "Run 'git submodule update --init --recursive'",
+		})
+	}
+	if len(moved) > 0 {
+		issues = append(issues, Issue{
+			Severity:    SeverityWarning,
+			ProjectType: "Git",
+			Message:     fmt.Sprintf("%d submodules are not at the commit the repository records: %s", len(moved), strings.Join(moved, ", ")),
+			Suggestion:  "Run 'git submodule update --recursive' to check out the recorded commits, after committing or stashing work inside them",
+		})
+	}
+	return issues
+}
+
+func shortCommit(commit string) string {
+	if len(commit) > 7 {
+		return commit[:7]
+	}
+	return commit
+}
+
+// lfsPatterns returns the .gitattributes patterns with filter=lfs
+func lfsPatterns(worktree string) []string {
+	data, err := os.ReadFile(filepath.Join(worktree, ".gitattributes"))
+	if err != nil {
+		return nil
+	}
+	patterns := []string{}
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		if containsString(fields[1:], "filter=lfs") {
+			patterns = append(patterns, fields[0])
+		}
+	}
+	return patterns
+}
+
+// gitAttrMatch matches a path relative to the worktree against a
+// .gitattributes pattern. Patterns without a slash match the file name at
+// any depth; "**/" matches any leading directories and "/**" anything
+// below a directory.
+func gitAttrMatch(pattern, rel string) bool {
+	pattern = strings.TrimPrefix(pattern, "/")
+	if !strings.Contains(pattern, "/") {
+		ok, _ := filepath.Match(pattern, filepath.Base(filepath.FromSlash(rel)))
+		return ok
+	}
+	if dir, ok := strings.CutSuffix(pattern, "/**"); ok {
+		return strings.HasPrefix(rel, dir+"/")
+	}
+	if rest, ok := strings.CutPrefix(pattern, "**/"); ok {
+		parts := strings.Split(rel, "/")
+		for i := range parts {
+			if ok, _ := filepath.Match(rest, strings.Join(parts[i:], "/")); ok {
+				return true
+			}
+		}
+		return false
+	}
+	ok, _ := filepath.Match(pattern, rel)
+	return ok
+}
+
+// isLFSPointer reports whether a file is a Git LFS pointer rather than the
+// content it points to
+func isLFSPointer(file string) bool {
+	f, err := os.Open(file)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+	header := make([]byte, len(lfsPointerHeader))
+	n, _ := f.Read(header)
+	return string(header[:n]) == lfsPointerHeader
+}
+
+// findLFSPointers returns the files tracked by Git LFS that are still
+// pointer files
+func findLFSPointers(worktree string, patterns []string) []string {
+	pointers := []string{}
+	files := 0
+	filepath.WalkDir(worktree, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() {
+			if p != worktree && detector.SkipDir(d.Name()) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if files++; files > maxLFSScanFiles {
+			return filepath.SkipAll
+		}
+		rel, _ := filepath.Rel(worktree, p)
+		rel = filepath.ToSlash(rel)
+		for _, pattern := range patterns {
+			if gitAttrMatch(pattern, rel) {
+				if isLFSPointer(p) {
+					pointers = append(pointers, rel)
+				}
+				break
+			}
+		}
+		return nil
+	})
+	return pointers
+}
+
+// checkLFS reports LFS-tracked files whose content was never fetched
+func checkLFS(repo *gitRepo) []Issue {
+	issues := []Issue{}
+	patterns := lfsPatterns(repo.WorkTree)
+	if len(patterns) == 0 {
+		return issues
+	}
+	pointers := findLFSPointers(repo.WorkTree, patterns)
+	if len(pointers) == 0 {
+		return issues
+	}
+
+	listed := pointers
+	if len(listed) > maxListed {
+		listed = append(append([]string{}, pointers[:maxListed]...), fmt.Sprintf("and %d more", len(pointers)-maxListed))
+	}
+	suggestion := "Run 'git lfs pull' to download their content"
+	if !isCommandAvailable("git-lfs") {
+		suggestion = "Install Git LFS (https://git-lfs.com), then run 'git lfs install' and 'git lfs pull'"
+	}
+	issues = append(issues, Issue{
+		Severity:    SeverityError,
+		ProjectType: "Git",
+		Message:     fmt.Sprintf("%d files tracked by Git LFS are pointer files, not their content: %s", len(pointers), strings.Join(listed, ", ")),
+		Suggestion:  suggestion,
+	})
+	return issues
+}
+
+// hooksDir returns the directory git runs hooks from and the configured
+// core.hooksPath, if any
+func hooksDir(repo *gitRepo) (string, string) {
+	if hooksPath := repo.Config["core"]["hookspath"]; hooksPath != "" {
+		dir := hooksPath
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(repo.WorkTree, filepath.FromSlash(dir))
+		}
+		return dir, hooksPath
+	}
+	return filepath.Join(repo.Common, "hooks"), ""
+}
+
+// hookInstalled reports whether dir holds the hook and its script mentions
+// the hook manager
+func hookInstalled(dir, hook, manager string) bool {
+	data, err := os.ReadFile(filepath.Join(dir, hook))
+	return err == nil && strings.Contains(string(data), manager)
+}
+
+// checkHooks reports hooks husky, lefthook and pre-commit are configured to
+// install that are missing from the hooks directory
+func checkHooks(repo *gitRepo) []Issue {
+	issues := []Issue{}
+	dir, hooksPath := hooksDir(repo)
+
+	// husky 6+ points core.hooksPath at .husky (or .husky/_ since v9);
+	// earlier versions wrote scripts into .git/hooks
+	if info, err := os.Stat(filepath.Join(repo.WorkTree, ".husky")); err == nil && info.IsDir() {
+		hooksPath = filepath.ToSlash(filepath.Clean(hooksPath))
+		if hooksPath != ".husky" && hooksPath != ".husky/_" && !hookInstalled(dir, "pre-commit", "husky") {
+			issues = append(issues, Issue{
+				Severity:    SeverityWarning,
+				ProjectType: "Git",
+				Message:     "Git hooks in .husky are not installed (core.hooksPath does not point to .husky)",
+				Suggestion:  "Run 'npm install' so the prepare script installs them, or 'npx husky'",
+			})
+		}
+	}
+
+	for _, name := range []string{"lefthook.yml", ".lefthook.yml", "lefthook.yaml", ".lefthook.yaml"} {
+		data, err := os.ReadFile(filepath.Join(repo.WorkTree, name))
+		if err != nil {
+			continue
+		}
+		doc, err := yaml.Decode(data)
+		if err != nil {
+			break
+		}
+		missing := []string{}
+		for _, hook := range yaml.Keys(yaml.Map(doc)) {
+			if gitHooks[hook] && !hookInstalled(dir, hook, "lefthook") {
+				missing = append(missing, hook)
+			}
+		}
+		if len(missing) > 0 {
+			issues = append(issues, Issue{
+				Severity:    SeverityWarning,
+				ProjectType: "Git",
+				Message:     fmt.Sprintf("Git hooks configured in %s are not installed: %s", name, strings.Join(missing, ", ")),
+				Suggestion:  "Run 'lefthook install'",
+			})
+		}
+		break
+	}
+
+	if data, err := os.ReadFile(filepath.Join(repo.WorkTree, ".pre-commit-config.yaml")); err == nil {
+		hooks := []string{"pre-commit"}
+		if doc, err := yaml.Decode(data); err == nil {
+			if types := yaml.List(yaml.Lookup(doc, "default_install_hook_types")); len(types) > 0 {
+				hooks = hooks[:0]
+				for _, hookType := range types {
+					hooks = append(hooks, yaml.String(hookType))
+				}
+			}
+		}
+		missing := []string{}
+		for _, hook := range hooks {
+			if !hookInstalled(dir, hook, "pre-commit") {
+				missing = append(missing, hook)
+			}
+		}
+		if len(missing) > 0 {
+			suggestion := "Run 'pre-commit install'"
+			if !isCommandAvailable("pre-commit") {
+				suggestion = "Install pre-commit with 'pipx install pre-commit' and run 'pre-commit install'"
+			}
+			issues = append(issues, Issue{
+				Severity:    SeverityWarning,
+				ProjectType: "Git",
+				Message:     fmt.Sprintf("Git hooks configured in .pre-commit-config.yaml are not installed: %s", strings.Join(missing, ", ")),
+				Suggestion:  suggestion,
+			})
+		}
+	}
+	return issues
+}
+
+// historyToolsUsed returns the tools of the project at path that need the
+// full commit history or tags
+func historyToolsUsed(path string) []string {
+	tools := []string{}
+	if data, err := os.ReadFile(filepath.Join(path, "package.json")); err == nil {
+		var packageJSON map[string]interface{}
+		json.Unmarshal(data, &packageJSON)
+		deps, _ := nodeDependencies(packageJSON)
+		for _, name := range historyTools.Node {
+			if _, ok := deps[name]; ok {
+				tools = append(tools, name)
+			}
+		}
+	}
+	for _, file := range []string{"pyproject.toml", "setup.cfg", "setup.py"} {
+		data, err := os.ReadFile(filepath.Join(path, file))
+		if err != nil {
+			continue
+		}
+		for _, name := range historyTools.Python {
+			if strings.Contains(string(data), name) && !containsString(tools, normalizePyName(name)) {
+				tools = append(tools, normalizePyName(name))
+			}
+		}
+	}
+	for _, file := range historyTools.Files {
+		if fileExists(path, file[0]) && !containsString(tools, file[1]) {
+			tools = append(tools, file[1])
+		}
+	}
+	return tools
+}
+
+// checkShallow reports a shallow clone when the project uses tools that
+// read the history
+func checkShallow(repo *gitRepo, path string) []Issue {
+	issues := []Issue{}
+	if !pathExists(filepath.Join(repo.Common, "shallow")) {
+		return issues
+	}
+	tools := historyToolsUsed(path)
+	if path != repo.WorkTree {
+		for _, tool := range historyToolsUsed(repo.WorkTree) {
+			if !containsString(tools, tool) {
+				tools = append(tools, tool)
+			}
+		}
+	}
+	if len(tools) == 0 {
+		return issues
+	}
+	issues = append(issues, Issue{
+		Severity:    SeverityWarning,
+		ProjectType: "Git",
+		Message:     fmt.Sprintf("The repository is a shallow clone, but the project uses %s, which read the commit history and tags", strings.Join(tools, ", ")),
+		Suggestion:  "Run 'git fetch --unshallow --tags'",
+	})
+	return issues
+}
+
+// CheckGit checks the git repository path belongs to: submodules, Git LFS
+// content, hook managers and shallow clones. Its issues have the root ".";
+// there are none outside a repository.
+func CheckGit(path string) []Issue {
+	issues := []Issue{}
+	repo := findGitRepo(path)
+	if repo == nil {
+		return issues
+	}
+
+	states := submoduleStates(repo)
+	recorded := map[string]string{}
+	moved := []string{}
+	for _, state := range states {
+		if state.Flag == '+' || state.Flag == 'U' {
+			moved = append(moved, state.Path)
+		}
+	}
+	if len(moved) > 0 {
+		recorded = recordedSubmoduleCommits(repo.WorkTree, moved)
+	}
+	issues = append(issues, checkSubmodules(states, recorded)...)
+	issues = append(issues, checkLFS(repo)...)
+	issues = append(issues, checkHooks(repo)...)
+	issues = append(issues, checkShallow(repo, path)...)
+
+	// Repository issues belong to the scan root
+	for i := range issues {
+		issues[i].Root = "."
+	}
+	return issues
+}
@@ -0,0 +1,149 @@
+package checker
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// fakeGitRepo creates a worktree with an empty .git directory and returns
+// a function that writes files into it
+func fakeGitRepo(t *testing.T) (string, func(rel, content string)) {
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, ".git", "hooks"), 0755)
+	return dir, func(rel, content string) {
+		file := filepath.Join(dir, filepath.FromSlash(rel))
+		os.MkdirAll(filepath.Dir(file), 0755)
+		os.WriteFile(file, []byte(content), 0644)
+	}
+}
+
+func TestParseGitConfig(t *testing.T) {
+	config := parseGitConfig(`[core]
+	hooksPath = .husky/_
+# comment
+[submodule "lib/json"]
+	path = lib/json
+	url = https://github.com/nlohmann/json.git
+`)
+	if config["core"]["hookspath"] != ".husky/_" {
+		t.Errorf("core.hookspath = %q", config["core"]["hookspath"])
+	}
+	if config["submodule.lib/json"]["path"] != "lib/json" {
+		t.Errorf("submodule path = %q", config["submodule.lib/json"]["path"])
+	}
+}
+
+func TestParseSubmoduleStatus(t *testing.T) {
+	out := " 3f2504e04f8911d39a0c0305e82c3301a1b2c3d4 lib/ok (v1.0)\n" +
+		"-9a0c0305e82c3301a1b2c3d43f2504e04f8911d3 lib/missing\n" +
+		"+0305e82c3301a1b2c3d43f2504e04f8911d39a0c lib/moved (heads/main)\n"
+	states := parseSubmoduleStatus(out)
+	if len(states) != 3 || states[1].Path != "lib/missing" || states[1].Flag != '-' || states[2].Flag != '+' {
+		t.Fatalf("parseSubmoduleStatus() = %+v", states)
+	}
+
+	issues := checkSubmodules(states, map[string]string{"lib/moved": "1111111111111111111111111111111111111111"})
+	if len(issues) != 2 {
+		t.Fatalf("checkSubmodules() = %v, want two issues", issues)
+	}
+	if issues[0].Severity != SeverityError || !strings.HasSuffix(issues[0].Message, "not initialised: lib/missing") {
+		t.Errorf("checkSubmodules() uninitialised = %v", issues[0])
+	}
+	if !strings.HasSuffix(issues[1].Message, "lib/moved (checked out at 0305e82, recorded 1111111)") {
+		t.Errorf("checkSubmodules() moved = %v", issues[1])
+	}
+}
+
+func TestGitAttrMatch(t *testing.T) {
+	tests := []struct {
+		pattern, rel string
+		want         bool
+	}{
+		{"*.psd", "assets/art/logo.psd", true},
+		{"*.psd", "logo.psd.txt", false},
+		{"models/*.bin", "models/weights.bin", true},
+		{"models/*.bin", "src/models/weights.bin", false},
+		{"/data/**", "data/a/b.csv", true},
+		{"**/fixtures/*.zip", "test/unit/fixtures/big.zip", true},
+		{"**/fixtures/*.zip", "fixtures/big.zip", true},
+	}
+	for _, tt := range tests {
+		if got := gitAttrMatch(tt.pattern, tt.rel); got != tt.want {
+			t.Errorf("gitAttrMatch(%q, %q) = %v, want %v", tt.pattern, tt.rel, got, tt.want)
+		}
+	}
+}
+
+func TestCheckLFS(t *testing.T) {
+	dir, write := fakeGitRepo(t)
+	write(".gitattributes", "*.psd filter=lfs diff=lfs merge=lfs -text\n*.md text\n")
+	write("assets/logo.psd", lfsPointerHeader+"\noid sha256:4d7a2146\nsize 12345\n")
+	write("assets/fetched.psd", "8BPS binary content")
+	write("README.md", lfsPointerHeader+"\n")
+
+	issues := checkLFS(findGitRepo(dir))
+	if len(issues) != 1 || !strings.HasSuffix(issues[0].Message, "pointer files, not their content: assets/logo.psd") {
+		t.Errorf("checkLFS() = %v, want assets/logo.psd only", issues)
+	}
+}
+
+func TestCheckHooks(t *testing.T) {
+	dir, write := fakeGitRepo(t)
+	write(".husky/pre-commit", "npx lint-staged\n")
+	write(".pre-commit-config.yaml", "default_install_hook_types: [pre-commit, commit-msg]\nrepos: []\n")
+	write("lefthook.yml", "pre-push:\n  commands:\n    test:\n      run: go test ./...\nskip_output:\n  - meta\n")
+	write(".git/hooks/pre-commit", "#!/usr/bin/env bash\n# File generated by pre-commit: https://pre-commit.com\n")
+
+	got := []string{}
+	for _, issue := range checkHooks(findGitRepo(dir)) {
+		got = append(got, issue.Message)
+	}
+	want := []string{
+		"Git hooks in .husky are not installed (core.hooksPath does not point to .husky)",
+		"Git hooks configured in lefthook.yml are not installed: pre-push",
+		"Git hooks configured in .pre-commit-config.yaml are not installed: commit-msg",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("checkHooks() = %q, want %q", got, want)
+	}
+
+	// husky installs itself by pointing core.hooksPath at .husky/_
+	write(".git/config", "[core]\n\thooksPath = .husky/_\n")
+	for _, issue := range checkHooks(findGitRepo(dir)) {
+		if strings.Contains(issue.Message, ".husky") {
+			t.Errorf("checkHooks() with core.hooksPath = %v", issue)
+		}
+	}
+}
+
+func TestCheckShallow(t *testing.T) {
+	dir, write := fakeGitRepo(t)
+	write("package.json", `{"devDependencies": {"semantic-release": "^23.0.0"}}`)
+	write("pyproject.toml", "[build-system]\nrequires = [\"setuptools_scm>=8\"]\n")
+	if issues := checkShallow(findGitRepo(dir), dir); len(issues) != 0 {
+		t.Errorf("checkShallow() on a full clone = %v", issues)
+	}
+
+	write(".git/shallow", "3f2504e04f8911d39a0c0305e82c3301a1b2c3d4\n")
+	issues := checkShallow(findGitRepo(dir), dir)
+	if len(issues) != 1 || !strings.Contains(issues[0].Message, "uses semantic-release, setuptools-scm, which read") {
+		t.Errorf("checkShallow() = %v", issues)
+	}
+}
+
+func TestFindGitRepoWorktree(t *testing.T) {
+	main, write := fakeGitRepo(t)
+	write(".git/config", "[core]\n\tbare = false\n")
+	write(".git/worktrees/feature/commondir", "../..\n")
+
+	worktree := t.TempDir()
+	os.WriteFile(filepath.Join(worktree, ".git"), []byte("gitdir: "+filepath.Join(main, ".git", "worktrees", "feature")+"\n"), 0644)
+	os.MkdirAll(filepath.Join(worktree, "src"), 0755)
+
+	repo := findGitRepo(filepath.Join(worktree, "src"))
+	if repo == nil || repo.WorkTree != worktree || repo.Common != filepath.Join(main, ".git") || repo.Config["core"]["bare"] != "false" {
+		t.Errorf("findGitRepo() = %+v", repo)
+	}
+}
@@ -0,0 +1,436 @@
+package checker
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// goModFile holds the directives of a go.mod or go.work file
+type goModFile struct {
+	Module    string
+	Go        string
+	Toolchain string
+	Require   map[string]string
+	Use       []string
+}
+
+// parseGoMod parses the directives of go.mod and go.work files. Replace,
+// exclude and retract directives are skipped.
+func parseGoMod(data []byte) *goModFile {
+	mod := &goModFile{Require: map[string]string{}}
+	block := ""
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.Index(line, "//"); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		for i := range fields {
+			fields[i] = strings.Trim(fields[i], "\"`")
+		}
+
+		if block != "" {
+			if fields[0] == ")" {
+				block = ""
+				continue
+			}
+			mod.add(block, fields)
+			continue
+		}
+		if len(fields) == 2 && fields[1] == "(" {
+			block = fields[0]
+			continue
+		}
+		mod.add(fields[0], fields[1:])
+	}
+	return mod
+}
+
+func (m *goModFile) add(directive string, args []string) {
+	if len(args) == 0 {
+		return
+	}
+	switch directive {
+	case "module":
+		m.Module = args[0]
+	case "go":
+		m.Go = args[0]
+	case "toolchain":
+		m.Toolchain = args[0]
+	case "require":
+		if len(args) >= 2 {
+			m.Require[args[0]] = args[1]
+		}
+	case "use":
+		m.Use = append(m.Use, args[0])
+	}
+}
+
+var goVersionRe = regexp.MustCompile(`^(?:go)?(\d+)(?:\.(\d+))?(?:\.(\d+))?(?:(beta|rc)(\d+))?`)
+
+// compareGoVersions compares Go versions such as 1.21, 1.21rc1 and 1.21.3.
+// From Go 1.21 on, a language version like 1.21 sorts before its release
+// candidates, which sort before 1.21.0.
+func compareGoVersions(a, b string) int {
+	ka, kb := goVersionKey(a), goVersionKey(b)
+	for i := range ka {
+		if ka[i] < kb[i] {
+			return -1
+		}
+		if ka[i] > kb[i] {
+			return 1
+		}
+	}
+	return 0
+}
+
+func goVersionKey(v string) [5]int {
+	m := goVersionRe.FindStringSubmatch(v)
+	if m == nil {
+		return [5]int{}
+	}
+	major, _ := strconv.Atoi(m[1])
+	minor, _ := strconv.Atoi(m[2])
+	key := [5]int{major, minor}
+	switch {
+	case m[4] == "beta":
+		key[2] = 1
+		key[3], _ = strconv.Atoi(m[5])
+	case m[4] == "rc":
+		key[2] = 2
+		key[3], _ = strconv.Atoi(m[5])
+	case m[3] != "" || major == 1 && minor < 21:
+		// Before Go 1.21, "1.20" named the 1.20.0 release
+		key[2] = 3
+		key[4], _ = strconv.Atoi(m[3])
+	}
+	return key
+}
+
+// goEnv asks the go command for settings so that go.env files are honoured,
+// falling back to the environment when Go is not installed. Toolchain
+// switching is disabled so this never downloads anything.
+func goEnv(keys ...string) map[string]string {
+	env := map[string]string{}
+	cmd := exec.Command("go", append([]string{"env"}, keys...)...)
+	cmd.Dir = os.TempDir()
+	cmd.Env = append(os.Environ(), "GOTOOLCHAIN=local")
+	if out, err := cmd.Output(); err == nil {
+		values := strings.Split(strings.TrimRight(string(out), "\r\n"), "\n")
+		if len(values) == len(keys) {
+			for i, key := range keys {
+				env[key] = strings.TrimSpace(values[i])
+			}
+			return env
+		}
+	}
+
+	for _, key := range keys {
+		env[key] = os.Getenv(key)
+	}
+	if _, ok := env["GOMODCACHE"]; ok && env["GOMODCACHE"] == "" {
+		gopath := filepath.SplitList(os.Getenv("GOPATH"))
+		if len(gopath) == 0 {
+			home, _ := os.UserHomeDir()
+			gopath = []string{filepath.Join(home, "go")}
+		}
+		env["GOMODCACHE"] = filepath.Join(gopath[0], "pkg", "mod")
+	}
+	return env
+}
+
+// goToolchainSetting returns the effective GOTOOLCHAIN: the environment
+// variable, then the user's go env file, then $GOROOT/go.env
+func goToolchainSetting(env map[string]string) string {
+	if setting := os.Getenv("GOTOOLCHAIN"); setting != "" {
+		return setting
+	}
+	for _, file := range []string{env["GOENV"], filepath.Join(env["GOROOT"], "go.env")} {
+		if file == "" || file == "off" {
+			continue
+		}
+		data, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
+		for _, line := range strings.Split(string(data), "\n") {
+			if value, ok := strings.CutPrefix(strings.TrimSpace(line), "GOTOOLCHAIN="); ok && value != "" {
+				return value
+			}
+		}
+	}
+	return "auto"
+}
+
+// checkGoToolchain compares the go and toolchain directives of file with
+// the local Go version, following the GOTOOLCHAIN rules: "local" never
+// switches, "auto" downloads newer toolchains, "path" looks for them in
+// PATH, and a toolchain name such as go1.22.3 is always used.
+func checkGoToolchain(file string, mod *goModFile, local, setting string) []Issue {
+	issues := []Issue{}
+	if mod == nil || mod.Go == "" || local == "" {
+		return issues
+	}
+
+	name, mode, _ := strings.Cut(setting, "+")
+	switch name {
+	case "auto", "path":
+		name, mode = "local", name
+	}
+	base := local
+	if name != "local" {
+		base = strings.TrimPrefix(name, "go")
+	}
+	want := mod.Go
+	if toolchain := strings.TrimPrefix(mod.Toolchain, "go"); toolchain != "" && toolchain != "default" && compareGoVersions(toolchain, want) > 0 {
+		want = toolchain
+	}
+
+	tooOld := compareGoVersions(base, mod.Go) < 0
+	if compareGoVersions(base, want) >= 0 || !tooOld && mode == "" {
+		return issues
+	}
+
+	switch mode {
+	case "":
+		issues = append(issues, Issue{
+			Severity:    SeverityError,
+			ProjectType: "Go",
+			Message:     fmt.Sprintf("%s requires go %s but the Go toolchain is %s (GOTOOLCHAIN=%s)", file, mod.Go, base, setting),
+			Suggestion:  fmt.Sprintf("Install Go %s or later, or set GOTOOLCHAIN=auto to let the go command download it", mod.Go),
+		})
+	case "auto":
+		severity := SeverityInfo
+		if tooOld {
+			severity = SeverityWarning
+		}
+		issues = append(issues, Issue{
+			Severity:    severity,
+			ProjectType: "Go",
+			Message:     fmt.Sprintf("%s asks for go%s; Go %s will download it on first use", file, want, base),
+			Suggestion:  fmt.Sprintf("Install Go %s to build offline", want),
+		})
+	case "path":
+		if _, err := exec.LookPath("go" + want); err == nil {
+			return issues
+		}
+		severity := SeverityInfo
+		if tooOld {
+			severity = SeverityError
+		}
+		issues = append(issues, Issue{
+			Severity:    severity,
+			ProjectType: "Go",
+			Message:     fmt.Sprintf("%s asks for go%s, which is not in PATH (GOTOOLCHAIN=%s)", file, want, setting),
+			Suggestion:  fmt.Sprintf("Install it with 'go install golang.org/dl/go%s@latest && go%s download'", want, want),
+		})
+	}
+	return issues
+}
+
+// findGoWork returns the go.work file that applies to path: $GOWORK, or the
+// first go.work found in path or one of its parents
+func findGoWork(path string) string {
+	switch gowork := os.Getenv("GOWORK"); {
+	case gowork == "off":
+		return ""
+	case gowork != "":
+		return gowork
+	}
+	dir, err := filepath.Abs(path)
+	if err != nil {
+		return ""
+	}
+	for {
+		if file := filepath.Join(dir, "go.work"); pathExists(file) {
+			return file
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// checkGoWork verifies the workspace that path belongs to. It returns the
+// workspace when path is one of its modules, so its go and toolchain lines
+// apply.
+func checkGoWork(path, workFile string, mod *goModFile) ([]Issue, *goModFile) {
+	issues := []Issue{}
+	data, err := os.ReadFile(workFile)
+	if err != nil {
+		return issues, nil
+	}
+	work := parseGoMod(data)
+	workDir := filepath.Dir(workFile)
+	abs, _ := filepath.Abs(path)
+	label := displayPath(abs, workFile)
+
+	member := false
+	for _, use := range work.Use {
+		dir := use
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(workDir, filepath.FromSlash(use))
+		}
+		if dir == abs {
+			member = true
+		}
+		// Report broken use directives once, for the workspace root
+		if workDir == abs && !fileExists(dir, "go.mod") {
+			issues = append(issues, Issue{
+				Severity:    SeverityError,
+				ProjectType: "Go",
+				Message:     fmt.Sprintf("go.work uses %s, which has no go.mod", use),
+				Suggestion:  fmt.Sprintf("Remove it with 'go work edit -dropuse=%s' or restore the module", use),
+			})
+		}
+	}
+
+	if mod == nil {
+		return issues, work
+	}
+	if !member {
+		return append(issues, Issue{
+			Severity:    SeverityError,
+			ProjectType: "Go",
+			Message:     fmt.Sprintf("Module is inside the workspace %s but not listed in its use directives", label),
+			Suggestion:  "Run 'go work use .' to add it, or set GOWORK=off to build it on its own",
+		}), nil
+	}
+	if work.Go != "" && mod.Go != "" && compareGoVersions(work.Go, mod.Go) < 0 {
+		issues = append(issues, Issue{
+			Severity:    SeverityError,
+			ProjectType: "Go",
+			Message:     fmt.Sprintf("%s lists go %s but this module requires go %s", label, work.Go, mod.Go),
+			Suggestion:  fmt.Sprintf("Run 'go work edit -go=%s'", mod.Go),
+		})
+	}
+	return issues, work
+}
+
+// escapeModulePath applies the module cache's case encoding, which replaces
+// each upper-case letter with "!" and its lower-case form
+func escapeModulePath(s string) string {
+	var sb strings.Builder
+	for _, r := range s {
+		if unicode.IsUpper(r) {
+			sb.WriteByte('!')
+			r = unicode.ToLower(r)
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+// missingGoModules returns the modules required by go.mod that go.sum lists
+// but the module cache lacks. Content hashes need the extracted module or its
+// zip; go.mod hashes need the .mod file. go.sum also keeps the go.mod hashes
+// of versions that minimal version selection discarded, which 'go mod
+// download' never fetches, so only the required versions are checked.
+func missingGoModules(goSum []byte, require map[string]string, modCache string) []string {
+	missing := map[string]bool{}
+	for _, line := range strings.Split(string(goSum), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 3 {
+			continue
+		}
+		module, version := fields[0], fields[1]
+		goModOnly := strings.HasSuffix(version, "/go.mod")
+		version = strings.TrimSuffix(version, "/go.mod")
+		if require[module] != version {
+			continue
+		}
+
+		download := filepath.Join(modCache, "cache", "download", filepath.FromSlash(escapeModulePath(module)), "@v", escapeModulePath(version))
+		present := pathExists(download + ".mod")
+		if !goModOnly {
+			present = pathExists(download+".zip") ||
+				pathExists(filepath.Join(modCache, filepath.FromSlash(escapeModulePath(module))+"@"+escapeModulePath(version)))
+		}
+		if !present {
+			missing[module+"@"+version] = true
+		}
+	}
+
+	list := make([]string, 0, len(missing))
+	for module := range missing {
+		list = append(list, module)
+	}
+	sort.Strings(list)
+	return list
+}
+
+// checkGoVendor compares vendor/modules.txt with the requirements in go.mod,
+// as the go command does before building with -mod=vendor
+func checkGoVendor(path string, mod *goModFile) []Issue {
+	issues := []Issue{}
+	data, err := os.ReadFile(filepath.Join(path, "vendor", "modules.txt"))
+	if err != nil {
+		return append(issues, Issue{
+			Severity:    SeverityError,
+			ProjectType: "Go",
+			Message:     "vendor directory has no modules.txt",
+			Suggestion:  "Run 'go mod vendor' to regenerate it",
+		})
+	}
+
+	// # path version [=> replacement]
+	// ## explicit; go 1.21
+	vendored := map[string]string{}
+	explicit := map[string]bool{}
+	current := ""
+	for _, line := range strings.Split(string(data), "\n") {
+		switch {
+		case strings.HasPrefix(line, "## "):
+			if current != "" && strings.Contains(line, "explicit") {
+				explicit[current] = true
+			}
+		case strings.HasPrefix(line, "# "):
+			fields := strings.Fields(line[2:])
+			current = ""
+			if len(fields) >= 2 && fields[1] != "=>" {
+				current = fields[0]
+				vendored[current] = fields[1]
+			}
+		}
+	}
+
+	problems := []string{}
+	for module, version := range mod.Require {
+		switch v, ok := vendored[module]; {
+		case !ok || !explicit[module]:
+			problems = append(problems, fmt.Sprintf("%s (not vendored)", module))
+		case v != version:
+			problems = append(problems, fmt.Sprintf("%s (go.mod %s, vendor %s)", module, version, v))
+		}
+	}
+	for module := range explicit {
+		if _, ok := mod.Require[module]; !ok {
+			problems = append(problems, fmt.Sprintf("%s (vendored but not required)", module))
+		}
+	}
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		issues = append(issues, Issue{
+			Severity:    SeverityError,
+			ProjectType: "Go",
+			Message:     fmt.Sprintf("vendor/modules.txt does not match go.mod: %s", strings.Join(problems, ", ")),
+			Suggestion:  "Run 'go mod vendor' to update the vendor directory",
+		})
+	}
+	return issues
+}
@@ -0,0 +1,168 @@
+package checker
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseGoMod(t *testing.T) {
+	mod := parseGoMod([]byte(`module example.com/app // the app
+
+go 1.22
+
+toolchain go1.22.3
+
+require github.com/pkg/errors v0.9.1
+
+require (
+	golang.org/x/sync v0.6.0
+	github.com/BurntSushi/toml v1.3.2 // indirect
+)
+
+replace (
+	golang.org/x/sync => ../sync
+)
+`))
+	if mod.Module != "example.com/app" || mod.Go != "1.22" || mod.Toolchain != "go1.22.3" {
+		t.Errorf("parseGoMod() = %+v", mod)
+	}
+	want := map[string]string{
+		"github.com/pkg/errors":      "v0.9.1",
+		"golang.org/x/sync":          "v0.6.0",
+		"github.com/BurntSushi/toml": "v1.3.2",
+	}
+	if !reflect.DeepEqual(mod.Require, want) {
+		t.Errorf("Require = %v, want %v", mod.Require, want)
+	}
+
+	work := parseGoMod([]byte("go 1.22\n\nuse (\n\t./api\n\t./web\n)\nuse ./tools\n"))
+	if !reflect.DeepEqual(work.Use, []string{"./api", "./web", "./tools"}) {
+		t.Errorf("Use = %v", work.Use)
+	}
+}
+
+func TestCompareGoVersions(t *testing.T) {
+	ordered := []string{"1.19", "1.20", "1.20.5", "1.21", "1.21rc1", "1.21rc2", "1.21.0", "1.21.3", "1.22"}
+	for i := 0; i+1 < len(ordered); i++ {
+		if compareGoVersions(ordered[i], ordered[i+1]) != -1 || compareGoVersions(ordered[i+1], ordered[i]) != 1 {
+			t.Errorf("Expected %s < %s", ordered[i], ordered[i+1])
+		}
+	}
+	if compareGoVersions("1.20", "1.20.0") != 0 {
+		t.Error("Expected 1.20 == 1.20.0")
+	}
+}
+
+func TestCheckGoToolchain(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+	mod := &goModFile{Go: "1.22", Toolchain: "go1.22.3"}
+	tests := []struct {
+		local, setting string
+		want           Severity
+	}{
+		{"1.22.3", "auto", ""},
+		{"1.22.1", "local", ""},
+		{"1.22.1", "auto", SeverityInfo},
+		{"1.21.5", "auto", SeverityWarning},
+		{"1.21.5", "local", SeverityError},
+		{"1.21.5", "local+auto", SeverityWarning},
+		{"1.21.5", "path", SeverityError},
+		{"1.21.5", "go1.22.0", ""},
+		{"1.23.0", "go1.21.0", SeverityError},
+	}
+	for _, tt := range tests {
+		issues := checkGoToolchain("go.mod", mod, tt.local, tt.setting)
+		var got Severity
+		if len(issues) > 0 {
+			got = issues[0].Severity
+		}
+		if got != tt.want {
+			t.Errorf("local %s, GOTOOLCHAIN=%s: got %v, want %q", tt.local, tt.setting, issues, tt.want)
+		}
+	}
+}
+
+func TestCheckGoWork(t *testing.T) {
+	t.Setenv("GOWORK", "")
+	root := t.TempDir()
+	os.WriteFile(filepath.Join(root, "go.work"), []byte("go 1.21\n\nuse (\n\t./api\n\t./gone\n)\n"), 0644)
+	for _, dir := range []string{"api", "cli"} {
+		os.MkdirAll(filepath.Join(root, dir), 0755)
+		os.WriteFile(filepath.Join(root, dir, "go.mod"), []byte("module example.com/"+dir+"\n\ngo 1.22\n"), 0644)
+	}
+
+	messages := func(issues []Issue) string {
+		var out []string
+		for _, issue := range issues {
+			out = append(out, issue.Message)
+		}
+		return strings.Join(out, "\n")
+	}
+
+	if got := messages(checkGo(root)); !strings.Contains(got, "go.work uses ./gone, which has no go.mod") {
+		t.Errorf("Expected missing use directory error, got:\n%s", got)
+	}
+	if got := messages(checkGo(filepath.Join(root, "api"))); !strings.Contains(got, "lists go 1.21 but this module requires go 1.22") {
+		t.Errorf("Expected go version error, got:\n%s", got)
+	}
+	if got := messages(checkGo(filepath.Join(root, "cli"))); !strings.Contains(got, "not listed in its use directives") {
+		t.Errorf("Expected unlisted module error, got:\n%s", got)
+	}
+
+	t.Setenv("GOWORK", "off")
+	if got := messages(checkGo(filepath.Join(root, "cli"))); strings.Contains(got, "go.work") {
+		t.Errorf("Expected no workspace issues with GOWORK=off, got:\n%s", got)
+	}
+}
+
+func TestMissingGoModules(t *testing.T) {
+	cache := t.TempDir()
+	os.MkdirAll(filepath.Join(cache, "github.com", "!burnt!sushi", "toml@v1.3.2"), 0755)
+	download := filepath.Join(cache, "cache", "download", "github.com", "pkg", "errors", "@v")
+	os.MkdirAll(download, 0755)
+	os.WriteFile(filepath.Join(download, "v0.9.1.mod"), nil, 0644)
+
+	goSum := []byte(`github.com/BurntSushi/toml v1.3.2 h1:abc=
+github.com/BurntSushi/toml v1.3.2/go.mod h1:def=
+github.com/pkg/errors v0.9.1/go.mod h1:ghi=
+golang.org/x/sync v0.6.0 h1:jkl=
+golang.org/x/sync v0.6.0/go.mod h1:mno=
+golang.org/x/text v0.3.0/go.mod h1:pqr=
+`)
+	require := map[string]string{
+		"github.com/BurntSushi/toml": "v1.3.2",
+		"github.com/pkg/errors":      "v0.9.1",
+		"golang.org/x/sync":          "v0.6.0",
+	}
+	got := missingGoModules(goSum, require, cache)
+	want := []string{"github.com/BurntSushi/toml@v1.3.2", "golang.org/x/sync@v0.6.0"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("missingGoModules() = %v, want %v", got, want)
+	}
+}
+
+func TestCheckGoVendor(t *testing.T) {
+	tmpDir := t.TempDir()
+	os.MkdirAll(filepath.Join(tmpDir, "vendor"), 0755)
+	os.WriteFile(filepath.Join(tmpDir, "vendor", "modules.txt"), []byte(`# github.com/pkg/errors v0.9.0
+## explicit
+github.com/pkg/errors
+# golang.org/x/text v0.14.0
+## explicit; go 1.18
+golang.org/x/text/unicode
+# example.com/local => ../local
+`), 0644)
+	mod := &goModFile{Require: map[string]string{
+		"github.com/pkg/errors": "v0.9.1",
+		"golang.org/x/sync":     "v0.6.0",
+	}}
+
+	issues := checkGoVendor(tmpDir, mod)
+	want := "vendor/modules.txt does not match go.mod: github.com/pkg/errors (go.mod v0.9.1, vendor v0.9.0), golang.org/x/sync (not vendored), golang.org/x/text (vendored but not required)"
+	if len(issues) != 1 || issues[0].Message != want {
+		t.Errorf("checkGoVendor() = %v", issues)
+	}
+}
@@ -0,0 +1,188 @@
+package checker
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/Sw3bbl3/devdoctor/internal/yaml"
+)
+
+// stackLTSCompilers maps Stackage LTS major versions to the GHC release
+// series they are built with. Minor LTS releases move to newer GHC patch
+// releases, so only the series is compared.
+var stackLTSCompilers = map[int]string{
+	10: "8.2",
+	11: "8.2",
+	12: "8.4",
+	13: "8.6",
+	14: "8.6",
+	15: "8.8",
+	16: "8.8",
+	17: "8.10",
+	18: "8.10",
+	19: "9.0",
+	20: "9.2",
+	21: "9.4",
+	22: "9.6",
+	23: "9.8",
+	24: "9.10",
+}
+
+// stackConfig holds the settings of stack.yaml that select the compiler
+type stackConfig struct {
+	Resolver   string
+	Compiler   string
+	SystemGHC  bool
+	InstallGHC bool
+}
+
+// readStackConfig reads stack.yaml; install-ghc defaults to true
+func readStackConfig(path string) (*stackConfig, error) {
+	data, err := os.ReadFile(filepath.Join(path, "stack.yaml"))
+	if err != nil {
+		return nil, err
+	}
+	doc, err := yaml.Decode(data)
+	if err != nil {
+		return nil, err
+	}
+	cfg := &stackConfig{
+		Resolver:   yaml.String(yaml.Lookup(doc, "snapshot")),
+		Compiler:   yaml.String(yaml.Lookup(doc, "compiler")),
+		SystemGHC:  yaml.String(yaml.Lookup(doc, "system-ghc")) == "true",
+		InstallGHC: yaml.String(yaml.Lookup(doc, "install-ghc")) != "false",
+	}
+	if cfg.Resolver == "" {
+		cfg.Resolver = yaml.String(yaml.Lookup(doc, "resolver"))
+	}
+	return cfg, nil
+}
+
+var stackLTSRe = regexp.MustCompile(`^lts-(\d+)(?:\.\d+)?$`)
+
+// ghcVersion returns the GHC version, or release series, that a stack
+// resolver or compiler setting selects: 9.6.4 for ghc-9.6.4, 9.6 for
+// lts-22.7. Nightly snapshots and custom snapshot files are unknown.
+func ghcVersion(setting string) string {
+	if v, ok := strings.CutPrefix(setting, "ghc-"); ok {
+		return v
+	}
+	if m := stackLTSRe.FindStringSubmatch(setting); m != nil {
+		major, _ := strconv.Atoi(m[1])
+		return stackLTSCompilers[major]
+	}
+	return ""
+}
+
+// ghcMatches reports whether an installed GHC version is the wanted version
+// or belongs to the wanted release series
+func ghcMatches(installed, want string) bool {
+	return installed == want || strings.HasPrefix(installed, want+".")
+}
+
+var ghcDirRe = regexp.MustCompile(`^ghc-(?:[a-z0-9]+-)?(\d+\.\d+\.\d+)$`)
+
+// installedGHCs returns the GHC versions installed by stack (under
+// $STACK_ROOT/programs) and ghcup (under ~/.ghcup/ghc)
+func installedGHCs() []string {
+	versions := []string{}
+	stackRoot := os.Getenv("STACK_ROOT")
+	if stackRoot == "" {
+		stackRoot = userHomePath(".stack")
+	}
+	if stackRoot != "" {
+		dirs, _ := filepath.Glob(filepath.Join(stackRoot, "programs", "*", "ghc-*"))
+		for _, dir := range dirs {
+			if m := ghcDirRe.FindStringSubmatch(filepath.Base(dir)); m != nil {
+				versions = append(versions, m[1])
+			}
+		}
+	}
+	if ghcup := userHomePath(".ghcup", "ghc"); ghcup != "" {
+		entries, _ := os.ReadDir(ghcup)
+		for _, entry := range entries {
+			versions = append(versions, entry.Name())
+		}
+	}
+	return versions
+}
+
+// checkStackCompiler checks that the GHC the stack resolver selects is
+// installed, or that stack may install it. ghc is the version in PATH.
+func checkStackCompiler(cfg *stackConfig, ghc string, installed []string) []Issue {
+	issues := []Issue{}
+	setting, source := cfg.Resolver, "resolver "+cfg.Resolver
+	if cfg.Compiler != "" {
+		setting, source = cfg.Compiler, "compiler "+cfg.Compiler
+	}
+	want := ghcVersion(setting)
+	if want == "" {
+		return issues
+	}
+
+	if cfg.SystemGHC && ghc != "" && ghcMatches(ghc, want) {
+		return issues
+	}
+	for _, v := range installed {
+		if ghcMatches(v, want) {
+			return issues
+		}
+	}
+
+	if !cfg.InstallGHC {
+		return append(issues, Issue{
+			Severity:    SeverityError,
+			ProjectType: "Haskell",
+			Message:     fmt.Sprintf("GHC %s for the stack %s is not installed and install-ghc is false (ghc in PATH is %s)", want, source, orNone(ghc)),
+			Suggestion:  fmt.Sprintf("Install GHC %s (e.g. 'ghcup install ghc %s') or set install-ghc: true in stack.yaml", want, want),
+		})
+	}
+	return append(issues, Issue{
+		Severity:    SeverityWarning,
+		ProjectType: "Haskell",
+		Message:     fmt.Sprintf("GHC %s for the stack %s is not installed; stack will download it on the first build", want, source),
+		Suggestion:  "Run 'stack setup' to install it ahead of time",
+	})
+}
+
+var cabalWithCompilerRe = regexp.MustCompile(`(?m)^\s*with-compiler\s*:\s*(\S+)`)
+
+// checkCabalCompiler checks the with-compiler setting of cabal.project
+// against the GHCs in PATH
+func checkCabalCompiler(path, ghc string) []Issue {
+	issues := []Issue{}
+	data, err := os.ReadFile(filepath.Join(path, "cabal.project"))
+	if err != nil {
+		return issues
+	}
+	m := cabalWithCompilerRe.FindStringSubmatch(string(data))
+	if m == nil {
+		return issues
+	}
+	// with-compiler: ghc-9.4.8 runs that executable, as ghcup installs it
+	compiler := m[1]
+	if isCommandAvailable(compiler) {
+		return issues
+	}
+	if want := ghcVersion(filepath.Base(compiler)); want != "" && ghcMatches(ghc, want) {
+		return issues
+	}
+	return append(issues, Issue{
+		Severity:    SeverityError,
+		ProjectType: "Haskell",
+		Message:     fmt.Sprintf("cabal.project uses with-compiler: %s, which is not installed (ghc in PATH is %s)", compiler, orNone(ghc)),
+		Suggestion:  fmt.Sprintf("Install it with 'ghcup install ghc %s'", strings.TrimPrefix(filepath.Base(compiler), "ghc-")),
+	})
+}
+
+// orNone returns s, or "none" when it is empty
+func orNone(s string) string {
+	if s == "" {
+		return "none"
+	}
+	return s
+}
@@ -0,0 +1,89 @@
+package checker
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGHCVersion(t *testing.T) {
+	tests := []struct {
+		setting string
+		want    string
+	}{
+		{"lts-22.7", "9.6"},
+		{"lts-21", "9.4"},
+		{"ghc-9.8.1", "9.8.1"},
+		{"nightly-2024-01-15", ""},
+		{"lts-99.1", ""},
+		{"./snapshot.yaml", ""},
+	}
+	for _, tt := range tests {
+		if got := ghcVersion(tt.setting); got != tt.want {
+			t.Errorf("ghcVersion(%q) = %q, want %q", tt.setting, got, tt.want)
+		}
+	}
+}
+
+func TestReadStackConfig(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "stack.yaml"), []byte(`# stack.yaml
+resolver: lts-22.7
+packages:
+  - .
+system-ghc: true
+install-ghc: false
+`), 0644)
+	cfg, err := readStackConfig(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := stackConfig{Resolver: "lts-22.7", SystemGHC: true, InstallGHC: false}
+	if *cfg != want {
+		t.Errorf("readStackConfig() = %+v, want %+v", *cfg, want)
+	}
+}
+
+func TestCheckStackCompiler(t *testing.T) {
+	tests := []struct {
+		name      string
+		cfg       stackConfig
+		ghc       string
+		installed []string
+		want      string
+	}{
+		{"installed by stack", stackConfig{Resolver: "lts-22.7", InstallGHC: true}, "", []string{"9.4.8", "9.6.4"}, ""},
+		{"system ghc", stackConfig{Resolver: "lts-22.7", SystemGHC: true}, "9.6.3", nil, ""},
+		{"ghc in PATH without system-ghc", stackConfig{Resolver: "lts-22.7", InstallGHC: true}, "9.6.3", nil,
+			"WARNING GHC 9.6 for the stack resolver lts-22.7 is not installed; stack will download it on the first build"},
+		{"compiler override", stackConfig{Resolver: "lts-22.7", Compiler: "ghc-9.8.1"}, "9.6.4", []string{"9.6.4"},
+			"ERROR GHC 9.8.1 for the stack compiler ghc-9.8.1 is not installed and install-ghc is false (ghc in PATH is 9.6.4)"},
+		{"nightly", stackConfig{Resolver: "nightly-2024-01-15"}, "", nil, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := []string{}
+			for _, issue := range checkStackCompiler(&tt.cfg, tt.ghc, tt.installed) {
+				got = append(got, string(issue.Severity)+" "+issue.Message)
+			}
+			if strings.Join(got, "\n") != tt.want {
+				t.Errorf("checkStackCompiler() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckCabalCompiler(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "cabal.project"), []byte("packages: .\nwith-compiler: ghc-9.4.8\n"), 0644)
+
+	if issues := checkCabalCompiler(dir, "9.4.8"); len(issues) != 0 {
+		t.Errorf("checkCabalCompiler() with ghc 9.4.8 in PATH = %v, want none", issues)
+	}
+	issues := checkCabalCompiler(dir, "9.6.4")
+	want := "cabal.project uses with-compiler: ghc-9.4.8, which is not installed (ghc in PATH is 9.6.4)"
+	if len(issues) != 1 || issues[0].Message != want {
+		t.Errorf("checkCabalCompiler() = %v, want %q", issues, want)
+	}
+}
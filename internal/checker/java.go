@@ -0,0 +1,107 @@
+package checker
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/Sw3bbl3/devdoctor/internal/detector"
+)
+
+// checkBuildWrapper verifies that a Maven or Gradle wrapper can run offline:
+// the script is executable, its properties and jar are present, and the
+// distribution it pins has already been downloaded
+func checkBuildWrapper(path, projectType string, wrapper *detector.BuildWrapper) []Issue {
+	issues := []Issue{}
+	label := "Gradle"
+	propertiesFile := filepath.Join("gradle", "wrapper", "gradle-wrapper.properties")
+	if wrapper.Tool == "mvn" {
+		label = "Maven"
+		propertiesFile = filepath.Join(".mvn", "wrapper", "maven-wrapper.properties")
+	}
+
+	if runtime.GOOS != "windows" {
+		info, err := os.Stat(filepath.Join(path, wrapper.Script))
+		if err != nil {
+			issues = append(issues, Issue{
+				Severity:    SeverityError,
+				ProjectType: projectType,
+				Message:     fmt.Sprintf("%s wrapper script '%s' not found (only the Windows script is present)", label, wrapper.Script),
+				Suggestion:  fmt.Sprintf("Restore '%s' from version control", wrapper.Script),
+			})
+		} else if info.Mode()&0111 == 0 {
+			issues = append(issues, Issue{
+				Severity:    SeverityError,
+				ProjectType: projectType,
+				Message:     fmt.Sprintf("%s wrapper script '%s' is not executable", label, wrapper.Script),
+				Suggestion:  fmt.Sprintf("Run 'chmod +x %s' (and 'git update-index --chmod=+x %s' to fix it for everyone)", wrapper.Script, wrapper.Script),
+			})
+		}
+	}
+
+	if wrapper.Properties == "" {
+		issues = append(issues, Issue{
+			Severity:    SeverityError,
+			ProjectType: projectType,
+			Message:     fmt.Sprintf("%s wrapper properties not found (%s)", label, filepath.ToSlash(propertiesFile)),
+			Suggestion:  fmt.Sprintf("Restore the wrapper files from version control or regenerate them with '%s wrapper'", wrapper.Tool),
+		})
+		return issues
+	}
+
+	if wrapper.Tool == "gradle" && !fileExists(path, filepath.Join("gradle", "wrapper", "gradle-wrapper.jar")) {
+		issues = append(issues, Issue{
+			Severity:    SeverityError,
+			ProjectType: projectType,
+			Message:     "Gradle wrapper jar not found (gradle/wrapper/gradle-wrapper.jar)",
+			Suggestion:  "Commit gradle-wrapper.jar (it is often excluded by a global *.jar ignore rule) or run 'gradle wrapper'",
+		})
+	}
+
+	version := wrapper.Version()
+	if version == "" {
+		return issues
+	}
+	if !wrapperDistributionCached(wrapper) {
+		issues = append(issues, Issue{
+			Severity:    SeverityWarning,
+			ProjectType: projectType,
+			Message:     fmt.Sprintf("%s %s pinned by the wrapper is not in the local wrapper cache", label, version),
+			Suggestion:  fmt.Sprintf("'./%s' will download it on first run; run './%s --version' while online to fetch it now", wrapper.Script, wrapper.Script),
+		})
+	}
+
+	return issues
+}
+
+// wrapperDistributionCached reports whether the wrapper's distribution has
+// been unpacked into ~/.gradle/wrapper/dists or ~/.m2/wrapper/dists
+func wrapperDistributionCached(wrapper *detector.BuildWrapper) bool {
+	var home, unpacked string
+	if wrapper.Tool == "mvn" {
+		home = os.Getenv("MAVEN_USER_HOME")
+		if home == "" {
+			home = userHomePath(".m2")
+		}
+		unpacked = "apache-maven-" + wrapper.Version()
+	} else {
+		home = os.Getenv("GRADLE_USER_HOME")
+		if home == "" {
+			home = userHomePath(".gradle")
+		}
+		unpacked = "gradle-" + wrapper.Version()
+	}
+
+	// dists/<distribution>/<hash>/<unpacked directory>
+	matches, _ := filepath.Glob(filepath.Join(home, "wrapper", "dists", wrapper.DistributionName(), "*", unpacked))
+	return len(matches) > 0
+}
+
+func userHomePath(elem ...string) string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(append([]string{home}, elem...)...)
+}
@@ -0,0 +1,61 @@
+package checker
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/Sw3bbl3/devdoctor/internal/detector"
+)
+
+func TestCheckBuildWrapper(t *testing.T) {
+	tmpDir := t.TempDir()
+	gradleHome := t.TempDir()
+	t.Setenv("GRADLE_USER_HOME", gradleHome)
+
+	files := map[string]string{
+		"build.gradle": "",
+		"gradlew":      "#!/bin/sh\n",
+		"gradle/wrapper/gradle-wrapper.properties": "distributionUrl=https\\://services.gradle.org/distributions/gradle-8.5-bin.zip\n",
+		"gradle/wrapper/gradle-wrapper.jar":        "",
+	}
+	for file, content := range files {
+		filePath := filepath.Join(tmpDir, file)
+		if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	wrapper := detector.DetectGradleWrapper(tmpDir)
+	issues := checkBuildWrapper(tmpDir, "Java", wrapper)
+	hasExecError := false
+	hasCacheWarning := false
+	for _, issue := range issues {
+		if strings.Contains(issue.Message, "is not executable") {
+			hasExecError = true
+		}
+		if strings.Contains(issue.Message, "not in the local wrapper cache") {
+			hasCacheWarning = true
+		}
+	}
+	if runtime.GOOS != "windows" && !hasExecError {
+		t.Error("Expected error for non-executable gradlew")
+	}
+	if !hasCacheWarning {
+		t.Error("Expected warning for missing Gradle distribution")
+	}
+
+	// Make the script executable and populate the wrapper cache
+	os.Chmod(filepath.Join(tmpDir, "gradlew"), 0755)
+	if err := os.MkdirAll(filepath.Join(gradleHome, "wrapper", "dists", "gradle-8.5-bin", "abc123", "gradle-8.5"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if issues := checkBuildWrapper(tmpDir, "Java", wrapper); len(issues) != 0 {
+		t.Errorf("Expected no issues, got %v", issues)
+	}
+}
@@ -0,0 +1,414 @@
+package checker
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/Sw3bbl3/devdoctor/internal/detector"
+	"github.com/Sw3bbl3/devdoctor/internal/envcheck"
+	"github.com/Sw3bbl3/devdoctor/internal/semver"
+)
+
+// gradleJDKSupport lists, for each JDK, the first Gradle release that can
+// run on it (https://docs.gradle.org/current/userguide/compatibility.html)
+var gradleJDKSupport = []struct {
+	JDK    int
+	Gradle string
+}{
+	{8, "2.0"},
+	{9, "4.3"},
+	{10, "4.7"},
+	{11, "5.0"},
+	{12, "5.4"},
+	{13, "6.0"},
+	{14, "6.3"},
+	{15, "6.7"},
+	{16, "7.0"},
+	{17, "7.3"},
+	{18, "7.5"},
+	{19, "7.6"},
+	{20, "8.3"},
+	{21, "8.5"},
+	{22, "8.8"},
+	{23, "8.10"},
+	{24, "8.14"},
+	{25, "9.1"},
+}
+
+// javaLevel is a Java release a build file compiles for
+type javaLevel struct {
+	Version int
+	Setting string // e.g. "maven.compiler.release"
+	File    string
+}
+
+func (l javaLevel) String() string {
+	return fmt.Sprintf("%s in %s", l.Setting, l.File)
+}
+
+// javaFeatureVersion returns the feature release of a Java version: 8 for
+// "1.8.0_392", "1.8" and "1_8", 21 for "21.0.2"
+func javaFeatureVersion(v string) int {
+	v = strings.ReplaceAll(strings.TrimSpace(v), "_", ".")
+	if strings.HasPrefix(v, "1.") {
+		v = v[2:]
+	}
+	major, _, _ := strings.Cut(v, ".")
+	n, _ := strconv.Atoi(major)
+	return n
+}
+
+// minJavaTarget returns the oldest release a JDK can still compile for
+func minJavaTarget(jdk int) int {
+	switch {
+	case jdk >= 20:
+		return 8
+	case jdk >= 12:
+		return 7
+	case jdk >= 9:
+		return 6
+	}
+	return 1
+}
+
+// installedJDK returns the feature version of the JDK that Maven and Gradle
+// run on: JAVA_HOME if set, otherwise the java the project directory selects
+func installedJDK(path string) (int, string) {
+	if home := os.Getenv("JAVA_HOME"); home != "" {
+		if v := jdkReleaseVersion(home); v != "" {
+			return javaFeatureVersion(v), "JAVA_HOME"
+		}
+	}
+	if status := envcheck.LookupIn(path, "java"); status.Found && status.Version != "" {
+		return javaFeatureVersion(status.Version), "PATH"
+	}
+	return 0, ""
+}
+
+// jdkReleaseVersion reads JAVA_VERSION from the release file of a JDK
+func jdkReleaseVersion(home string) string {
+	data, err := os.ReadFile(filepath.Join(home, "release"))
+	if err != nil {
+		return ""
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if value, ok := strings.CutPrefix(strings.TrimSpace(line), "JAVA_VERSION="); ok {
+			return strings.Trim(value, `"`)
+		}
+	}
+	return ""
+}
+
+type pomPlugin struct {
+	ArtifactID    string `xml:"artifactId"`
+	Configuration struct {
+		Source  string `xml:"source"`
+		Target  string `xml:"target"`
+		Release string `xml:"release"`
+	} `xml:"configuration"`
+}
+
+type pomFile struct {
+	Parent struct {
+		ArtifactID string `xml:"artifactId"`
+	} `xml:"parent"`
+	Properties struct {
+		Entries []struct {
+			XMLName xml.Name
+			Value   string `xml:",chardata"`
+		} `xml:",any"`
+	} `xml:"properties"`
+	Plugins        []pomPlugin `xml:"build>plugins>plugin"`
+	ManagedPlugins []pomPlugin `xml:"build>pluginManagement>plugins>plugin"`
+}
+
+var pomPropertyRe = regexp.MustCompile(`\$\{([^}]+)\}`)
+
+// mavenJavaLevels reads the compiler levels from pom.xml: the
+// maven.compiler.* properties and maven-compiler-plugin configuration
+func mavenJavaLevels(path string) []javaLevel {
+	data, err := os.ReadFile(filepath.Join(path, "pom.xml"))
+	if err != nil {
+		return nil
+	}
+	var pom pomFile
+	if xml.Unmarshal(data, &pom) != nil {
+		return nil
+	}
+
+	props := map[string]string{}
+	for _, entry := range pom.Properties.Entries {
+		props[entry.XMLName.Local] = strings.TrimSpace(entry.Value)
+	}
+	resolve := func(value string) string {
+		for i := 0; i < 5 && strings.Contains(value, "${"); i++ {
+			value = pomPropertyRe.ReplaceAllStringFunc(value, func(ref string) string {
+				return props[ref[2:len(ref)-1]]
+			})
+		}
+		return strings.TrimSpace(value)
+	}
+
+	levels := []javaLevel{}
+	add := func(value, setting string) {
+		if v := javaFeatureVersion(resolve(value)); v > 0 {
+			levels = append(levels, javaLevel{Version: v, Setting: setting, File: "pom.xml"})
+		}
+	}
+	for _, key := range []string{"maven.compiler.release", "maven.compiler.source", "maven.compiler.target"} {
+		add(props[key], key)
+	}
+	// Spring Boot's parent sets the compiler release from java.version
+	if strings.HasPrefix(pom.Parent.ArtifactID, "spring-boot-starter-parent") && props["maven.compiler.release"] == "" {
+		add(props["java.version"], "java.version")
+	}
+	for _, plugin := range append(pom.Plugins, pom.ManagedPlugins...) {
+		if plugin.ArtifactID != "maven-compiler-plugin" {
+			continue
+		}
+		add(plugin.Configuration.Release, "maven-compiler-plugin <release>")
+		add(plugin.Configuration.Source, "maven-compiler-plugin <source>")
+		add(plugin.Configuration.Target, "maven-compiler-plugin <target>")
+	}
+	return levels
+}
+
+var (
+	gradleCompatibilityRe = regexp.MustCompile(`\b(source|target)Compatibility\s*=\s*(?:JavaVersion\.(?:VERSION_|toVersion\(\s*))?['"]?(\d+(?:[._]\d+)?)`)
+	gradleReleaseRe       = regexp.MustCompile(`\boptions\.release(?:\.set\(\s*|\s*=\s*)(\d+)`)
+	gradleToolchainRe     = regexp.MustCompile(`JavaLanguageVersion\.of\(\s*['"]?(\d+)|\bjvmToolchain\(\s*(\d+)`)
+)
+
+// gradleJavaLevels reads sourceCompatibility, targetCompatibility and
+// options.release from the root build script, and the Java toolchain
+// version if one is configured
+func gradleJavaLevels(path string) ([]javaLevel, *javaLevel) {
+	levels := []javaLevel{}
+	var toolchain *javaLevel
+	for _, file := range []string{"build.gradle", "build.gradle.kts"} {
+		data, err := os.ReadFile(filepath.Join(path, file))
+		if err != nil {
+			continue
+		}
+		content := string(data)
+		for _, m := range gradleCompatibilityRe.FindAllStringSubmatch(content, -1) {
+			levels = append(levels, javaLevel{Version: javaFeatureVersion(m[2]), Setting: m[1] + "Compatibility", File: file})
+		}
+		for _, m := range gradleReleaseRe.FindAllStringSubmatch(content, -1) {
+			levels = append(levels, javaLevel{Version: javaFeatureVersion(m[1]), Setting: "options.release", File: file})
+		}
+		if m := gradleToolchainRe.FindStringSubmatch(content); m != nil && toolchain == nil {
+			toolchain = &javaLevel{Version: javaFeatureVersion(m[1] + m[2]), Setting: "Java toolchain", File: file}
+		}
+	}
+	return levels, toolchain
+}
+
+// checkJavaLevels compares the levels a build compiles for with the JDK
+// that compiles them
+func checkJavaLevels(levels []javaLevel, jdk int, jdkSource string) []Issue {
+	issues := []Issue{}
+	if len(levels) == 0 || jdk == 0 {
+		return issues
+	}
+
+	highest, lowest := levels[0], levels[0]
+	for _, level := range levels[1:] {
+		if level.Version > highest.Version {
+			highest = level
+		}
+		if level.Version < lowest.Version {
+			lowest = level
+		}
+	}
+
+	if highest.Version > jdk {
+		issues = append(issues, Issue{
+			Severity:    SeverityError,
+			ProjectType: "Java",
+			Message:     fmt.Sprintf("Build targets Java %d (%s) but the JDK is %d (from %s)", highest.Version, highest, jdk, jdkSource),
+			Suggestion:  fmt.Sprintf("Install JDK %d or later and point JAVA_HOME at it", highest.Version),
+		})
+	}
+	if min := minJavaTarget(jdk); lowest.Version < min {
+		issues = append(issues, Issue{
+			Severity:    SeverityError,
+			ProjectType: "Java",
+			Message:     fmt.Sprintf("JDK %d cannot compile for Java %d (%s)", jdk, lowest.Version, lowest),
+			Suggestion:  fmt.Sprintf("Raise it to %d or later, or build with an older JDK", min),
+		})
+	}
+	return issues
+}
+
+// checkGradle checks the Gradle wrapper, and that Gradle can run on its JDK
+// and compile for the configured Java levels
+func checkGradle(path string, wrapper *detector.BuildWrapper) []Issue {
+	issues := []Issue{}
+	jdk, jdkSource := installedJDK(path)
+	// org.gradle.java.home selects the JDK the Gradle daemon runs on
+	if home := gradleProperty(path, "org.gradle.java.home"); home != "" {
+		v := jdkReleaseVersion(home)
+		if v == "" {
+			return append(issues, Issue{
+				Severity:    SeverityError,
+				ProjectType: "Java",
+				Message:     fmt.Sprintf("No JDK found at %s (org.gradle.java.home in gradle.properties)", home),
+				Suggestion:  "Fix org.gradle.java.home or remove it to use JAVA_HOME",
+			})
+		}
+		jdk, jdkSource = javaFeatureVersion(v), "org.gradle.java.home"
+	}
+
+	gradleVersion, source := "", ""
+	if wrapper != nil {
+		issues = append(issues, checkBuildWrapper(path, "Java", wrapper)...)
+		gradleVersion, source = wrapper.Version(), "wrapper"
+	} else if status := envcheck.LookupIn(path, "gradle"); status.Found {
+		gradleVersion, source = status.Version, "PATH"
+	}
+	issues = append(issues, checkGradleJDK(gradleVersion, source, jdk, jdkSource)...)
+
+	// With a toolchain, Gradle compiles with that JDK instead of its own
+	levels, toolchain := gradleJavaLevels(path)
+	if toolchain != nil {
+		issues = append(issues, checkGradleToolchain(path, *toolchain, jdk)...)
+		jdk, jdkSource = toolchain.Version, "Java toolchain"
+	}
+	issues = append(issues, checkJavaLevels(levels, jdk, jdkSource)...)
+
+	return issues
+}
+
+// checkGradleJDK checks that a Gradle version can run on the JDK
+func checkGradleJDK(gradleVersion, source string, jdk int, jdkSource string) []Issue {
+	issues := []Issue{}
+	gradle, err := semver.Parse(gradleVersion)
+	if err != nil || jdk == 0 {
+		return issues
+	}
+
+	// Gradle 5 to 8 need Java 8 to run, Gradle 9 needs Java 17
+	minJDK := 8
+	if gradle.Major >= 9 {
+		minJDK = 17
+	}
+	if jdk < minJDK && gradle.Major >= 5 {
+		return append(issues, Issue{
+			Severity:    SeverityError,
+			ProjectType: "Java",
+			Message:     fmt.Sprintf("Gradle %s (%s) needs JDK %d or later to run, but the JDK is %d (from %s)", gradleVersion, source, minJDK, jdk, jdkSource),
+			Suggestion:  fmt.Sprintf("Point JAVA_HOME at JDK %d or later", minJDK),
+		})
+	}
+
+	for _, entry := range gradleJDKSupport {
+		if entry.JDK != jdk {
+			continue
+		}
+		required, _ := semver.Parse(entry.Gradle)
+		if semver.Compare(gradle, required) < 0 {
+			issues = append(issues, Issue{
+				Severity:    SeverityError,
+				ProjectType: "Java",
+				Message:     fmt.Sprintf("Gradle %s (%s) cannot run on JDK %d (from %s); JDK %d needs Gradle %s or later", gradleVersion, source, jdk, jdkSource, jdk, entry.Gradle),
+				Suggestion:  fmt.Sprintf("Set distributionUrl in gradle-wrapper.properties to Gradle %s or later, or point JAVA_HOME at an older JDK", entry.Gradle),
+			})
+		}
+	}
+	return issues
+}
+
+// checkGradleToolchain checks that a JDK for the configured Java toolchain
+// is installed where Gradle looks for one, or that Gradle may download it
+func checkGradleToolchain(path string, toolchain javaLevel, jdk int) []Issue {
+	issues := []Issue{}
+	if toolchain.Version == jdk || findJDK(toolchain.Version, gradleInstallationPaths(path)) != "" {
+		return issues
+	}
+
+	for _, file := range []string{"settings.gradle", "settings.gradle.kts"} {
+		data, err := os.ReadFile(filepath.Join(path, file))
+		if err == nil && strings.Contains(string(data), "foojay-resolver") {
+			return append(issues, Issue{
+				Severity:    SeverityInfo,
+				ProjectType: "Java",
+				Message:     fmt.Sprintf("JDK %d for the Java toolchain (%s) is not installed; Gradle will download it", toolchain.Version, toolchain.File),
+				Suggestion:  fmt.Sprintf("Install JDK %d to build offline", toolchain.Version),
+			})
+		}
+	}
+	return append(issues, Issue{
+		Severity:    SeverityError,
+		ProjectType: "Java",
+		Message:     fmt.Sprintf("No JDK %d found for the Java toolchain (%s)", toolchain.Version, toolchain.File),
+		Suggestion:  fmt.Sprintf("Install JDK %d (e.g. 'sdk install java %d-tem') or apply the org.gradle.toolchains.foojay-resolver-convention plugin so Gradle can download it", toolchain.Version, toolchain.Version),
+	})
+}
+
+// gradleProperty returns a setting from the project's gradle.properties
+func gradleProperty(path, key string) string {
+	data, err := os.ReadFile(filepath.Join(path, "gradle.properties"))
+	if err != nil {
+		return ""
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		k, value, ok := strings.Cut(line, "=")
+		if ok && strings.TrimSpace(k) == key {
+			return strings.TrimSpace(value)
+		}
+	}
+	return ""
+}
+
+// gradleInstallationPaths returns the extra JDK locations listed in
+// org.gradle.java.installations.paths
+func gradleInstallationPaths(path string) []string {
+	if paths := gradleProperty(path, "org.gradle.java.installations.paths"); paths != "" {
+		return strings.Split(paths, ",")
+	}
+	return nil
+}
+
+// findJDK looks for an installed JDK of the given feature version in the
+// locations Gradle detects automatically and in extra
+func findJDK(feature int, extra []string) string {
+	candidates := append([]string{os.Getenv("JAVA_HOME")}, extra...)
+	patterns := []string{
+		"/usr/lib/jvm/*",
+		"/usr/java/*",
+		"/opt/java/*",
+		"/Library/Java/JavaVirtualMachines/*/Contents/Home",
+	}
+	for _, dir := range [][]string{
+		{".sdkman", "candidates", "java", "*"},
+		{".asdf", "installs", "java", "*"},
+		{".gradle", "jdks", "*"},
+		{".gradle", "jdks", "*", "Contents", "Home"},
+		{".jdks", "*"},
+	} {
+		if home := userHomePath(dir...); home != "" {
+			patterns = append(patterns, home)
+		}
+	}
+	for _, pattern := range patterns {
+		matches, _ := filepath.Glob(pattern)
+		candidates = append(candidates, matches...)
+	}
+
+	for _, home := range candidates {
+		home = strings.TrimSpace(home)
+		if home == "" {
+			continue
+		}
+		if v := jdkReleaseVersion(home); v != "" && javaFeatureVersion(v) == feature {
+			return home
+		}
+	}
+	return ""
+}
@@ -0,0 +1,144 @@
+package checker
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestJavaFeatureVersion(t *testing.T) {
+	tests := map[string]int{"1.8.0_392": 8, "1.8": 8, "1_8": 8, "21.0.2": 21, "17": 17, "": 0}
+	for in, want := range tests {
+		if got := javaFeatureVersion(in); got != want {
+			t.Errorf("javaFeatureVersion(%q) = %d, want %d", in, got, want)
+		}
+	}
+}
+
+func TestMavenJavaLevels(t *testing.T) {
+	tmpDir := t.TempDir()
+	os.WriteFile(filepath.Join(tmpDir, "pom.xml"), []byte(`<project>
+  <properties>
+    <java.version>17</java.version>
+    <maven.compiler.source>${java.version}</maven.compiler.source>
+  </properties>
+  <build>
+    <plugins>
+      <plugin>
+        <artifactId>maven-compiler-plugin</artifactId>
+        <configuration>
+          <target>1.8</target>
+        </configuration>
+      </plugin>
+    </plugins>
+  </build>
+</project>`), 0644)
+
+	want := []javaLevel{
+		{17, "maven.compiler.source", "pom.xml"},
+		{8, "maven-compiler-plugin <target>", "pom.xml"},
+	}
+	if got := mavenJavaLevels(tmpDir); !reflect.DeepEqual(got, want) {
+		t.Errorf("mavenJavaLevels() = %v, want %v", got, want)
+	}
+}
+
+func TestGradleJavaLevels(t *testing.T) {
+	tmpDir := t.TempDir()
+	os.WriteFile(filepath.Join(tmpDir, "build.gradle.kts"), []byte(`java {
+    sourceCompatibility = JavaVersion.VERSION_1_8
+    targetCompatibility = JavaVersion.VERSION_11
+    toolchain {
+        languageVersion.set(JavaLanguageVersion.of(21))
+    }
+}
+tasks.withType<JavaCompile> { options.release.set(17) }
+`), 0644)
+
+	levels, toolchain := gradleJavaLevels(tmpDir)
+	want := []javaLevel{
+		{8, "sourceCompatibility", "build.gradle.kts"},
+		{11, "targetCompatibility", "build.gradle.kts"},
+		{17, "options.release", "build.gradle.kts"},
+	}
+	if !reflect.DeepEqual(levels, want) {
+		t.Errorf("levels = %v, want %v", levels, want)
+	}
+	if toolchain == nil || toolchain.Version != 21 {
+		t.Errorf("toolchain = %v, want 21", toolchain)
+	}
+}
+
+func TestCheckJavaLevels(t *testing.T) {
+	levels := []javaLevel{{17, "maven.compiler.release", "pom.xml"}}
+	if issues := checkJavaLevels(levels, 21, "PATH"); len(issues) != 0 {
+		t.Errorf("Expected no issues, got %v", issues)
+	}
+	issues := checkJavaLevels(levels, 11, "JAVA_HOME")
+	if len(issues) != 1 || !strings.Contains(issues[0].Message, "Build targets Java 17 (maven.compiler.release in pom.xml) but the JDK is 11") {
+		t.Errorf("Expected JDK too old error, got %v", issues)
+	}
+	issues = checkJavaLevels([]javaLevel{{7, "targetCompatibility", "build.gradle"}}, 21, "PATH")
+	if len(issues) != 1 || !strings.Contains(issues[0].Message, "JDK 21 cannot compile for Java 7") {
+		t.Errorf("Expected unsupported target error, got %v", issues)
+	}
+}
+
+func TestCheckGradleJDK(t *testing.T) {
+	tests := []struct {
+		gradle string
+		jdk    int
+		want   string
+	}{
+		{"7.2", 21, "Gradle 7.2 (wrapper) cannot run on JDK 21 (from PATH); JDK 21 needs Gradle 8.5 or later"},
+		{"8.5", 21, ""},
+		{"8.10.2", 23, ""},
+		{"9.0.0", 11, "Gradle 9.0.0 (wrapper) needs JDK 17 or later to run"},
+		{"8.5", 30, ""},
+	}
+	for _, tt := range tests {
+		issues := checkGradleJDK(tt.gradle, "wrapper", tt.jdk, "PATH")
+		if tt.want == "" && len(issues) != 0 || tt.want != "" && (len(issues) != 1 || !strings.HasPrefix(issues[0].Message, tt.want)) {
+			t.Errorf("Gradle %s on JDK %d: got %v, want %q", tt.gradle, tt.jdk, issues, tt.want)
+		}
+	}
+}
+
+func TestCheckGradleToolchain(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	jdkHome := t.TempDir()
+	os.WriteFile(filepath.Join(jdkHome, "release"), []byte("JAVA_VERSION=\"17.0.9\"\n"), 0644)
+	t.Setenv("JAVA_HOME", jdkHome)
+
+	tmpDir := t.TempDir()
+	toolchain := javaLevel{Version: 17, Setting: "Java toolchain", File: "build.gradle"}
+	if issues := checkGradleToolchain(tmpDir, toolchain, 21); len(issues) != 0 {
+		t.Errorf("Expected the JAVA_HOME JDK to satisfy the toolchain, got %v", issues)
+	}
+
+	toolchain.Version = 22
+	issues := checkGradleToolchain(tmpDir, toolchain, 21)
+	if len(issues) != 1 || issues[0].Severity != SeverityError {
+		t.Errorf("Expected missing toolchain error, got %v", issues)
+	}
+
+	os.WriteFile(filepath.Join(tmpDir, "settings.gradle"), []byte(`plugins { id 'org.gradle.toolchains.foojay-resolver-convention' version '0.8.0' }`), 0644)
+	issues = checkGradleToolchain(tmpDir, toolchain, 21)
+	if len(issues) != 1 || issues[0].Severity != SeverityInfo {
+		t.Errorf("Expected auto-provisioning info, got %v", issues)
+	}
+}
+
+func TestInstalledJDKRunsFromProject(t *testing.T) {
+	installDirShims(t, "21.0.2", map[string]string{"java": `openjdk version \"$v\" 2024-01-16`})
+	t.Setenv("JAVA_HOME", "")
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, ".shim-version"), []byte("17.0.10\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if jdk, source := installedJDK(dir); jdk != 17 || source != "PATH" {
+		t.Errorf("installedJDK() = %d, %q, want the java the project selects", jdk, source)
+	}
+}
@@ -0,0 +1,157 @@
+package checker
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/Sw3bbl3/devdoctor/internal/semver"
+	"github.com/Sw3bbl3/devdoctor/internal/toml"
+)
+
+// kotlinGradleSupport lists the first Gradle release each Kotlin Gradle
+// plugin release line supports
+// (https://kotlinlang.org/docs/gradle-configure-project.html)
+var kotlinGradleSupport = []struct {
+	Kotlin string
+	Gradle string
+}{
+	{"1.6.20", "6.1.1"},
+	{"1.7.0", "6.7.1"},
+	{"1.8.0", "6.8.3"},
+	{"2.1.0", "7.6.3"},
+}
+
+// kotlinJVMTargets lists the first Kotlin release that can target each JVM
+// bytecode version above 17
+var kotlinJVMTargets = []struct {
+	JVM    int
+	Kotlin string
+}{
+	{17, "1.6.0"},
+	{18, "1.7.0"},
+	{19, "1.8.0"},
+	{20, "1.9.0"},
+	{21, "1.9.20"},
+	{22, "2.0.0"},
+	{23, "2.1.0"},
+}
+
+var (
+	kotlinPluginRes = []*regexp.Regexp{
+		regexp.MustCompile(`\bkotlin\(\s*"[a-z.-]+"\s*\)\s+version\s+"([^"]+)"`),
+		regexp.MustCompile(`\bid\s*\(?\s*["']org\.jetbrains\.kotlin\.[a-z.-]+["']\s*\)?\s+version\s+["']([^"']+)["']`),
+		regexp.MustCompile(`kotlin-gradle-plugin:(\d[^"')\s]*)`),
+	}
+	kotlinJVMTargetRe = regexp.MustCompile(`\bjvmToolchain\(\s*(\d+)|JvmTarget\.JVM_(\d+(?:_\d+)?)|\bjvmTarget\s*=\s*"(\d+(?:\.\d+)?)"`)
+)
+
+// kotlinPluginVersion returns the Kotlin Gradle plugin version from the
+// build scripts or the version catalog, and the file it was found in
+func kotlinPluginVersion(path string) (string, string) {
+	for _, file := range []string{"build.gradle.kts", "settings.gradle.kts", "build.gradle", "settings.gradle"} {
+		data, err := os.ReadFile(filepath.Join(path, file))
+		if err != nil {
+			continue
+		}
+		for _, re := range kotlinPluginRes {
+			if m := re.FindStringSubmatch(string(data)); m != nil {
+				return m[1], file
+			}
+		}
+	}
+
+	catalog := filepath.Join("gradle", "libs.versions.toml")
+	data, err := os.ReadFile(filepath.Join(path, catalog))
+	if err != nil {
+		return "", ""
+	}
+	doc, err := toml.Decode(data)
+	if err != nil {
+		return "", ""
+	}
+	for _, plugin := range toml.Table(doc, "plugins") {
+		// id = "...", version = "2.0.0" or version.ref = "kotlin"; or "id:version"
+		if s, ok := plugin.(string); ok {
+			if id, version, found := strings.Cut(s, ":"); found && strings.HasPrefix(id, "org.jetbrains.kotlin.") {
+				return version, filepath.ToSlash(catalog)
+			}
+			continue
+		}
+		table, _ := plugin.(map[string]interface{})
+		if id, _ := table["id"].(string); !strings.HasPrefix(id, "org.jetbrains.kotlin.") {
+			continue
+		}
+		if version := toml.String(table, "version"); version != "" {
+			return version, filepath.ToSlash(catalog)
+		}
+		if ref := toml.String(table, "version", "ref"); ref != "" {
+			return toml.String(doc, "versions", ref), filepath.ToSlash(catalog)
+		}
+	}
+	return "", ""
+}
+
+// kotlinJVMTarget returns the highest JVM target or toolchain set in the
+// root build script
+func kotlinJVMTarget(path string) (int, string) {
+	highest, source := 0, ""
+	for _, file := range []string{"build.gradle.kts", "build.gradle"} {
+		data, err := os.ReadFile(filepath.Join(path, file))
+		if err != nil {
+			continue
+		}
+		for _, m := range kotlinJVMTargetRe.FindAllStringSubmatch(string(data), -1) {
+			if v := javaFeatureVersion(m[1] + m[2] + m[3]); v > highest {
+				highest, source = v, file
+			}
+		}
+	}
+	return highest, source
+}
+
+// checkKotlinPlugin checks that the Kotlin Gradle plugin supports the
+// Gradle version and the JVM target of the build
+func checkKotlinPlugin(path, gradleVersion, gradleSource string) []Issue {
+	issues := []Issue{}
+	version, file := kotlinPluginVersion(path)
+	kotlin, err := semver.Parse(version)
+	if err != nil {
+		return issues
+	}
+
+	if gradle, err := semver.Parse(gradleVersion); err == nil {
+		required := ""
+		for _, entry := range kotlinGradleSupport {
+			if v, _ := semver.Parse(entry.Kotlin); semver.Compare(kotlin, v) >= 0 {
+				required = entry.Gradle
+			}
+		}
+		if min, err := semver.Parse(required); err == nil && semver.Compare(gradle, min) < 0 {
+			issues = append(issues, Issue{
+				Severity:    SeverityError,
+				ProjectType: "Kotlin",
+				Message:     fmt.Sprintf("Kotlin Gradle plugin %s (%s) needs Gradle %s or later, but Gradle is %s (%s)", version, file, required, gradleVersion, gradleSource),
+				Suggestion:  fmt.Sprintf("Run './gradlew wrapper --gradle-version %s' or use an older Kotlin plugin", required),
+			})
+		}
+	}
+
+	target, targetFile := kotlinJVMTarget(path)
+	for _, entry := range kotlinJVMTargets {
+		if entry.JVM != target {
+			continue
+		}
+		if required, _ := semver.Parse(entry.Kotlin); semver.Compare(kotlin, required) < 0 {
+			issues = append(issues, Issue{
+				Severity:    SeverityError,
+				ProjectType: "Kotlin",
+				Message:     fmt.Sprintf("%s targets JVM %d, which Kotlin %s cannot compile for; JVM %d needs Kotlin %s or later", targetFile, target, version, target, entry.Kotlin),
+				Suggestion:  fmt.Sprintf("Upgrade the Kotlin plugin in %s to %s or later, or lower the JVM target", file, entry.Kotlin),
+			})
+		}
+	}
+	return issues
+}
@@ -0,0 +1,73 @@
+package checker
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestKotlinPluginVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   map[string]string
+		version string
+		source  string
+	}{
+		{"kotlin dsl", map[string]string{
+			"build.gradle.kts": "plugins {\n    kotlin(\"jvm\") version \"1.9.22\"\n}\n",
+		}, "1.9.22", "build.gradle.kts"},
+		{"plugin id", map[string]string{
+			"settings.gradle.kts": "pluginManagement {\n  plugins {\n    id(\"org.jetbrains.kotlin.jvm\") version \"2.0.0\"\n  }\n}\n",
+		}, "2.0.0", "settings.gradle.kts"},
+		{"buildscript classpath", map[string]string{
+			"build.gradle": "buildscript {\n  dependencies {\n    classpath 'org.jetbrains.kotlin:kotlin-gradle-plugin:1.8.10'\n  }\n}\n",
+		}, "1.8.10", "build.gradle"},
+		{"version catalog", map[string]string{
+			"build.gradle.kts":          "plugins {\n    alias(libs.plugins.kotlin.jvm)\n}\n",
+			"gradle/libs.versions.toml": "[versions]\nkotlin = \"2.1.0\"\n\n[plugins]\nkotlin-jvm = { id = \"org.jetbrains.kotlin.jvm\", version.ref = \"kotlin\" }\n",
+		}, "2.1.0", "gradle/libs.versions.toml"},
+		{"none", map[string]string{"build.gradle.kts": "plugins { java }\n"}, "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for name, content := range tt.files {
+				os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0755)
+				os.WriteFile(filepath.Join(dir, name), []byte(content), 0644)
+			}
+			version, source := kotlinPluginVersion(dir)
+			if version != tt.version || source != tt.source {
+				t.Errorf("kotlinPluginVersion() = %q, %q, want %q, %q", version, source, tt.version, tt.source)
+			}
+		})
+	}
+}
+
+func TestCheckKotlinPlugin(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "build.gradle.kts"), []byte(`plugins {
+    kotlin("jvm") version "1.9.10"
+}
+
+kotlin {
+    jvmToolchain(21)
+}
+`), 0644)
+
+	got := []string{}
+	for _, issue := range checkKotlinPlugin(dir, "6.7", "wrapper") {
+		got = append(got, issue.Message)
+	}
+	want := []string{
+		"Kotlin Gradle plugin 1.9.10 (build.gradle.kts) needs Gradle 6.8.3 or later, but Gradle is 6.7 (wrapper)",
+		"build.gradle.kts targets JVM 21, which Kotlin 1.9.10 cannot compile for; JVM 21 needs Kotlin 1.9.20 or later",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("checkKotlinPlugin():\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	if issues := checkKotlinPlugin(dir, "8.5", "wrapper"); len(issues) != 1 {
+		t.Errorf("checkKotlinPlugin() with Gradle 8.5 = %v, want only the JVM target issue", issues)
+	}
+}
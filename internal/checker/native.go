@@ -0,0 +1,743 @@
+package checker
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/Sw3bbl3/devdoctor/internal/semver"
+)
+
+// maxCMakeFiles bounds how many CMakeLists.txt add_subdirectory() may pull in
+const maxCMakeFiles = 50
+
+// nativeRequirement is a library or tool a C/C++ build looks up
+type nativeRequirement struct {
+	Name     string // find_package name, pkg-config module or Meson dependency
+	Kind     string // find_package, pkg_check_modules, pkg_search_module or dependency
+	Op       string // version operator of pkg-config and Meson requirements
+	Version  string
+	Required bool
+	Source   string // file:line
+	// Alternatives are the other modules pkg_search_module accepts
+	Alternatives []string
+}
+
+func (r nativeRequirement) String() string {
+	name := r.Name
+	if r.Op != "" {
+		name = fmt.Sprintf("%s %s %s", r.Name, r.Op, r.Version)
+	}
+	for _, alt := range r.Alternatives {
+		name += " or " + alt
+	}
+	return fmt.Sprintf("%s (%s in %s)", name, r.Kind, r.Source)
+}
+
+// nativeBuild is what the build files of a C/C++ project require
+type nativeBuild struct {
+	CMakeMinimum string // cmake_minimum_required VERSION
+	MesonVersion string // meson_version of the Meson project()
+	Requirements []nativeRequirement
+	// Vendored lists the names of dependencies built from source with
+	// FetchContent, CPM or add_subdirectory, which find_package may refer to
+	Vendored map[string]bool
+	// ModuleDirs are the CMAKE_MODULE_PATH entries the project sets,
+	// relative to the project directory
+	ModuleDirs []string
+}
+
+var (
+	cmakeBracketCommentRe = regexp.MustCompile(`(?s)#\[(=*)\[.*?\]=*\]`)
+	cmakeLineCommentRe    = regexp.MustCompile(`#[^\n]*`)
+	cmakeCallRe           = regexp.MustCompile(`(?is)\b(cmake_minimum_required|find_package|pkg_check_modules|pkg_search_module|add_subdirectory|fetchcontent_declare|cpmaddpackage|project|list|set)\s*\(([^)]*)\)`)
+	pkgModuleSpecRe       = regexp.MustCompile(`^([^<>=\s]+)\s*(?:(<=|>=|=|<|>)\s*(\S+))?$`)
+	mesonDependencyRe     = regexp.MustCompile(`(?s)\bdependency\(\s*'([^']*)'([^)]*)\)`)
+	mesonVersionArgRe     = regexp.MustCompile(`\bversion\s*:\s*'([^']+)'`)
+	mesonRequiredFalseRe  = regexp.MustCompile(`\brequired\s*:\s*false\b`)
+	mesonProjectVersionRe = regexp.MustCompile(`(?s)\bproject\([^)]*\bmeson_version\s*:\s*'([^']+)'`)
+)
+
+// pkgCheckKeywords are the options of pkg_check_modules and pkg_search_module
+var pkgCheckKeywords = map[string]bool{
+	"REQUIRED": true, "QUIET": true, "NO_CMAKE_PATH": true, "NO_CMAKE_ENVIRONMENT_PATH": true,
+	"IMPORTED_TARGET": true, "GLOBAL": true,
+}
+
+// stripCMakeComments removes comments, keeping line breaks so that line
+// numbers stay correct
+func stripCMakeComments(content string) string {
+	content = cmakeBracketCommentRe.ReplaceAllStringFunc(content, func(c string) string {
+		return strings.Repeat("\n", strings.Count(c, "\n"))
+	})
+	return cmakeLineCommentRe.ReplaceAllString(content, "")
+}
+
+// cmakeDirVars map the variables a CMAKE_MODULE_PATH entry may start with
+// to the directory they name: "" for the directory of the CMakeLists.txt,
+// "." for the top-level source directory
+var cmakeDirVars = map[string]string{
+	"${CMAKE_CURRENT_LIST_DIR}": "", "${CMAKE_CURRENT_SOURCE_DIR}": "",
+	"${CMAKE_SOURCE_DIR}": ".", "${PROJECT_SOURCE_DIR}": ".", "${CMAKE_HOME_DIRECTORY}": ".",
+}
+
+// cmakeModuleDir resolves a CMAKE_MODULE_PATH entry set in the
+// CMakeLists.txt of dir. It returns "" for entries that use other
+// variables.
+func cmakeModuleDir(entry, dir string) string {
+	if entry == "" {
+		return ""
+	}
+	base := dir
+	if strings.HasPrefix(entry, "${") {
+		variable, rest, _ := strings.Cut(entry, "}")
+		named, ok := cmakeDirVars[variable+"}"]
+		if !ok {
+			return ""
+		}
+		if named != "" {
+			base = named
+		}
+		entry = strings.TrimPrefix(rest, "/")
+	} else if filepath.IsAbs(entry) {
+		return filepath.Clean(entry)
+	}
+	if strings.Contains(entry, "${") || strings.Contains(entry, "$<") {
+		return ""
+	}
+	return filepath.Join(base, filepath.FromSlash(entry))
+}
+
+// parseCMake reads the requirements of the CMakeLists.txt in dir, relative
+// to the project, into build. It returns the subdirectories added with
+// add_subdirectory().
+func parseCMake(content, dir, file string, build *nativeBuild) []string {
+	content = stripCMakeComments(content)
+	subdirs := []string{}
+	for _, m := range cmakeCallRe.FindAllStringSubmatchIndex(content, -1) {
+		command := strings.ToLower(content[m[2]:m[3]])
+		args := strings.Fields(content[m[4]:m[5]])
+		for i, arg := range args {
+			args[i] = strings.Trim(arg, `"`)
+		}
+		if len(args) == 0 {
+			continue
+		}
+		source := fmt.Sprintf("%s:%d", file, strings.Count(content[:m[0]], "\n")+1)
+
+		switch command {
+		case "list", "set":
+			// list(APPEND CMAKE_MODULE_PATH ...), set(CMAKE_MODULE_PATH ...)
+			entries := []string{}
+			if command == "set" && args[0] == "CMAKE_MODULE_PATH" {
+				entries = args[1:]
+			} else if command == "list" && len(args) > 2 && (strings.EqualFold(args[0], "APPEND") || strings.EqualFold(args[0], "PREPEND")) && args[1] == "CMAKE_MODULE_PATH" {
+				entries = args[2:]
+			}
+			for _, entry := range entries {
+				for _, e := range strings.Split(entry, ";") {
+					if moduleDir := cmakeModuleDir(e, dir); moduleDir != "" {
+						build.ModuleDirs = append(build.ModuleDirs, moduleDir)
+					}
+				}
+			}
+		case "cmake_minimum_required":
+			if len(args) > 1 && strings.EqualFold(args[0], "VERSION") && build.CMakeMinimum == "" {
+				build.CMakeMinimum, _, _ = strings.Cut(args[1], "...")
+			}
+		case "find_package":
+			if strings.Contains(args[0], "${") {
+				continue
+			}
+			req := nativeRequirement{Name: args[0], Kind: "find_package", Source: source}
+			if len(args) > 1 && args[1] != "" && args[1][0] >= '0' && args[1][0] <= '9' {
+				req.Version = args[1]
+			}
+			req.Required = containsString(args, "REQUIRED")
+			build.Requirements = append(build.Requirements, req)
+		case "pkg_check_modules", "pkg_search_module":
+			required := containsString(args, "REQUIRED")
+			specs := []nativeRequirement{}
+			for _, arg := range args[1:] {
+				if pkgCheckKeywords[arg] || strings.Contains(arg, "${") {
+					continue
+				}
+				sm := pkgModuleSpecRe.FindStringSubmatch(arg)
+				if sm == nil {
+					continue
+				}
+				specs = append(specs, nativeRequirement{Name: sm[1], Kind: command, Op: sm[2], Version: sm[3], Required: required, Source: source})
+			}
+			if command == "pkg_check_modules" {
+				build.Requirements = append(build.Requirements, specs...)
+			} else if len(specs) > 0 {
+				first := specs[0]
+				for _, alt := range specs[1:] {
+					first.Alternatives = append(first.Alternatives, alt.Name)
+				}
+				build.Requirements = append(build.Requirements, first)
+			}
+		case "add_subdirectory":
+			if !strings.Contains(args[0], "${") {
+				subdirs = append(subdirs, args[0])
+			}
+		case "fetchcontent_declare", "project":
+			build.Vendored[strings.ToLower(args[0])] = true
+		case "cpmaddpackage":
+			// CPMAddPackage(NAME fmt ...) or CPMAddPackage("gh:fmtlib/fmt#10.2.1")
+			if i := indexOf(args, "NAME"); i >= 0 && i+1 < len(args) {
+				build.Vendored[strings.ToLower(args[i+1])] = true
+			} else if _, repo, ok := strings.Cut(args[0], "/"); ok {
+				name, _, _ := strings.Cut(repo, "#")
+				build.Vendored[strings.ToLower(name)] = true
+			}
+		}
+	}
+	return subdirs
+}
+
+// indexOf returns the index of s in list, or -1
+func indexOf(list []string, s string) int {
+	for i, item := range list {
+		if item == s {
+			return i
+		}
+	}
+	return -1
+}
+
+// parseMeson reads the dependency() calls and the meson_version of a
+// meson.build into build
+func parseMeson(content, file string, build *nativeBuild) {
+	content = cmakeLineCommentRe.ReplaceAllString(content, "")
+	if m := mesonProjectVersionRe.FindStringSubmatch(content); m != nil {
+		build.MesonVersion = m[1]
+	}
+	for _, m := range mesonDependencyRe.FindAllStringSubmatchIndex(content, -1) {
+		name, options := content[m[2]:m[3]], content[m[4]:m[5]]
+		// threads and the compiler's own libraries need no lookup
+		if name == "" || name == "threads" || name == "openmp" {
+			continue
+		}
+		req := nativeRequirement{
+			Name:     name,
+			Kind:     "dependency",
+			Required: !mesonRequiredFalseRe.MatchString(options),
+			Source:   fmt.Sprintf("%s:%d", file, strings.Count(content[:m[0]], "\n")+1),
+		}
+		if v := mesonVersionArgRe.FindStringSubmatch(options); v != nil {
+			if sm := pkgModuleSpecRe.FindStringSubmatch(name + v[1]); sm != nil {
+				req.Op, req.Version = sm[2], sm[3]
+			}
+		}
+		build.Requirements = append(build.Requirements, req)
+	}
+}
+
+// readNativeBuild parses CMakeLists.txt, following add_subdirectory(), and
+// meson.build at path
+func readNativeBuild(path string) *nativeBuild {
+	build := &nativeBuild{Vendored: map[string]bool{}}
+
+	queue := []string{"."}
+	seen := map[string]bool{}
+	for len(queue) > 0 && len(seen) < maxCMakeFiles {
+		dir := filepath.Clean(queue[0])
+		queue = queue[1:]
+		if seen[dir] {
+			continue
+		}
+		seen[dir] = true
+		file := filepath.Join(path, dir, "CMakeLists.txt")
+		data, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
+		for _, sub := range parseCMake(string(data), dir, displayPath(path, file), build) {
+			build.Vendored[strings.ToLower(filepath.Base(sub))] = true
+			queue = append(queue, filepath.Join(dir, filepath.FromSlash(sub)))
+		}
+	}
+
+	if data, err := os.ReadFile(filepath.Join(path, "meson.build")); err == nil {
+		parseMeson(string(data), "meson.build", build)
+	}
+	return build
+}
+
+// cmakeFindModule describes how the libraries behind a CMake Find module
+// are found without CMake: their pkg-config module and a header
+type cmakeFindModule struct {
+	PkgConfig string
+	Headers   []string
+}
+
+// cmakeFindModules lists Find modules shipped with CMake for common
+// development libraries. An empty entry needs no system library.
+var cmakeFindModules = map[string]cmakeFindModule{
+	"Threads":    {},
+	"OpenMP":     {},
+	"Iconv":      {},
+	"Intl":       {},
+	"OpenSSL":    {"openssl", []string{"openssl/ssl.h"}},
+	"ZLIB":       {"zlib", []string{"zlib.h"}},
+	"CURL":       {"libcurl", []string{"curl/curl.h"}},
+	"PNG":        {"libpng", []string{"png.h"}},
+	"JPEG":       {"libjpeg", []string{"jpeglib.h"}},
+	"TIFF":       {"libtiff-4", []string{"tiffio.h"}},
+	"SQLite3":    {"sqlite3", []string{"sqlite3.h"}},
+	"BZip2":      {"bzip2", []string{"bzlib.h"}},
+	"LibLZMA":    {"liblzma", []string{"lzma.h"}},
+	"LibXml2":    {"libxml-2.0", []string{"libxml2/libxml/parser.h", "libxml/parser.h"}},
+	"EXPAT":      {"expat", []string{"expat.h"}},
+	"Freetype":   {"freetype2", []string{"freetype2/ft2build.h", "ft2build.h"}},
+	"LibArchive": {"libarchive", []string{"archive.h"}},
+	"PostgreSQL": {"libpq", []string{"libpq-fe.h", "postgresql/libpq-fe.h"}},
+	"Boost":      {"", []string{"boost/version.hpp"}},
+	"GTest":      {"gtest", []string{"gtest/gtest.h"}},
+	"Protobuf":   {"protobuf", []string{"google/protobuf/message.h"}},
+	"OpenGL":     {"gl", []string{"GL/gl.h", "OpenGL/gl.h"}},
+	"GLEW":       {"glew", []string{"GL/glew.h"}},
+	"X11":        {"x11", []string{"X11/Xlib.h"}},
+}
+
+// cmakeFindTools maps Find modules that look up programs to the commands
+var cmakeFindTools = map[string][]string{
+	"PkgConfig": {"pkg-config", "pkgconf"},
+	"Git":       {"git"},
+	"Python":    {"python3", "python"},
+	"Python3":   {"python3", "python"},
+	"Perl":      {"perl"},
+	"Doxygen":   {"doxygen"},
+	"BISON":     {"bison"},
+	"FLEX":      {"flex"},
+	"SWIG":      {"swig"},
+}
+
+// nativeEnv is where the build looks for libraries
+type nativeEnv struct {
+	// PkgConfig runs 'pkg-config --exists'; it is nil when pkg-config is
+	// not installed and .pc files are searched in PCDirs instead
+	PkgConfig   func(spec string) bool
+	PCDirs      []string
+	PrefixPaths []string // CMAKE_PREFIX_PATH and the system prefixes
+	IncludeDirs []string
+	// CMakeModuleDirs holds the Modules directory of the installed CMake
+	CMakeModuleDirs []string
+}
+
+// splitPathList splits a PATH-style environment variable
+func splitPathList(value string) []string {
+	dirs := []string{}
+	for _, dir := range filepath.SplitList(value) {
+		if dir != "" {
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs
+}
+
+// globAll returns the matches of several glob patterns
+func globAll(patterns ...string) []string {
+	matches := []string{}
+	for _, pattern := range patterns {
+		m, _ := filepath.Glob(pattern)
+		matches = append(matches, m...)
+	}
+	return matches
+}
+
+// defaultNativeEnv describes the library search paths of this machine
+func defaultNativeEnv() *nativeEnv {
+	env := &nativeEnv{}
+	if pkgConfig, err := exec.LookPath("pkg-config"); err == nil {
+		env.PkgConfig = func(spec string) bool {
+			return exec.Command(pkgConfig, "--exists", spec).Run() == nil
+		}
+	}
+
+	env.PrefixPaths = splitPathList(os.Getenv("CMAKE_PREFIX_PATH"))
+	if runtime.GOOS != "windows" {
+		env.PrefixPaths = append(env.PrefixPaths, "/usr", "/usr/local", "/opt/local", "/opt/homebrew")
+		// Homebrew keeps some libraries out of its prefix (keg-only)
+		env.PrefixPaths = append(env.PrefixPaths, globAll("/opt/homebrew/opt/*", "/usr/local/opt/*")...)
+	}
+
+	env.PCDirs = splitPathList(os.Getenv("PKG_CONFIG_PATH"))
+	for _, prefix := range env.PrefixPaths {
+		env.PCDirs = append(env.PCDirs, filepath.Join(prefix, "lib", "pkgconfig"), filepath.Join(prefix, "lib64", "pkgconfig"), filepath.Join(prefix, "share", "pkgconfig"))
+		env.PCDirs = append(env.PCDirs, globAll(filepath.Join(prefix, "lib", "*-linux-gnu*", "pkgconfig"))...)
+	}
+
+	for _, name := range []string{"CPATH", "C_INCLUDE_PATH", "CPLUS_INCLUDE_PATH"} {
+		env.IncludeDirs = append(env.IncludeDirs, splitPathList(os.Getenv(name))...)
+	}
+	for _, prefix := range env.PrefixPaths {
+		env.IncludeDirs = append(env.IncludeDirs, filepath.Join(prefix, "include"))
+	}
+	env.IncludeDirs = append(env.IncludeDirs, globAll("/usr/include/*-linux-gnu*")...)
+
+	if cmake, err := exec.LookPath("cmake"); err == nil {
+		if resolved, err := filepath.EvalSymlinks(cmake); err == nil {
+			cmake = resolved
+		}
+		env.CMakeModuleDirs = globAll(filepath.Join(filepath.Dir(filepath.Dir(cmake)), "share", "cmake*", "Modules"))
+	}
+	return env
+}
+
+// hasModule looks up a pkg-config module with an optional version
+// constraint
+func (e *nativeEnv) hasModule(name, op, version string) bool {
+	if e.PkgConfig != nil {
+		spec := name
+		if op != "" {
+			spec = fmt.Sprintf("%s %s %s", name, op, version)
+		}
+		return e.PkgConfig(spec)
+	}
+	for _, dir := range e.PCDirs {
+		data, err := os.ReadFile(filepath.Join(dir, name+".pc"))
+		if err != nil {
+			continue
+		}
+		if op == "" {
+			return true
+		}
+		for _, line := range strings.Split(string(data), "\n") {
+			if v, ok := strings.CutPrefix(strings.TrimSpace(line), "Version:"); ok {
+				constraint := op + strings.TrimSpace(version)
+				if op == "=" {
+					constraint = strings.TrimSpace(version)
+				}
+				if ok, err := semver.Satisfies(strings.TrimSpace(v), constraint); err != nil || ok {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// hasCMakeConfig looks for the <Name>Config.cmake or <name>-config.cmake
+// file of a package where find_package searches in config mode
+func (e *nativeEnv) hasCMakeConfig(name string) bool {
+	files := []string{name + "Config.cmake", strings.ToLower(name) + "-config.cmake"}
+	prefixes := append([]string{}, e.PrefixPaths...)
+	for _, variable := range []string{name + "_DIR", name + "_ROOT", strings.ToUpper(name) + "_ROOT"} {
+		if dir := os.Getenv(variable); dir != "" {
+			prefixes = append(prefixes, dir)
+		}
+	}
+
+	for _, prefix := range prefixes {
+		dirs := []string{prefix, filepath.Join(prefix, "cmake"), filepath.Join(prefix, "CMake")}
+		for _, n := range []string{name, strings.ToLower(name)} {
+			dirs = append(dirs, globAll(
+				filepath.Join(prefix, n+"*", "cmake"),
+				filepath.Join(prefix, n+"*", "CMake"),
+				filepath.Join(prefix, "lib*", "cmake", n+"*"),
+				filepath.Join(prefix, "lib", "*-linux-gnu*", "cmake", n+"*"),
+				filepath.Join(prefix, "share", "cmake", n+"*"),
+				filepath.Join(prefix, "lib*", n+"*"),
+				filepath.Join(prefix, "share", n+"*"),
+				filepath.Join(prefix, "share", n+"*", "cmake"),
+			)...)
+		}
+		for _, dir := range dirs {
+			for _, file := range files {
+				if pathExists(filepath.Join(dir, file)) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// hasHeader looks for a header in the include directories. The header may
+// be a glob pattern such as python3*/Python.h.
+func (e *nativeEnv) hasHeader(header string) bool {
+	for _, dir := range e.IncludeDirs {
+		if len(globAll(filepath.Join(dir, filepath.FromSlash(header)))) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// hasCMakeFindModule reports whether the installed CMake ships a Find
+// module for a package that cmakeFindModules does not describe
+func (e *nativeEnv) hasCMakeFindModule(name string) bool {
+	for _, dir := range e.CMakeModuleDirs {
+		if pathExists(filepath.Join(dir, "Find"+name+".cmake")) {
+			return true
+		}
+	}
+	return false
+}
+
+// projectFindModule reports whether the project ships its own
+// Find<Name>.cmake, in the usual cmake directories or in the directories
+// it adds to CMAKE_MODULE_PATH. Its logic cannot be evaluated.
+func projectFindModule(path string, moduleDirs []string, name string) bool {
+	dirs := append([]string{"cmake", "CMake", filepath.Join("cmake", "modules"), filepath.Join("cmake", "Modules"), filepath.Join("cmake", "find")}, moduleDirs...)
+	for _, dir := range dirs {
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(path, dir)
+		}
+		if fileExists(dir, "Find"+name+".cmake") {
+			return true
+		}
+	}
+	return false
+}
+
+// satisfied reports whether a requirement can be found on this machine,
+// and whether that could be evaluated at all: a find_package of a package
+// that is not catalogued and has no Find module or package config file
+// may still be found by logic devdoctor does not know.
+func (e *nativeEnv) satisfied(path string, moduleDirs []string, req nativeRequirement) (found, evaluated bool) {
+	switch req.Kind {
+	case "pkg_check_modules", "pkg_search_module":
+		if e.hasModule(req.Name, req.Op, req.Version) {
+			return true, true
+		}
+		for _, alt := range req.Alternatives {
+			if e.hasModule(alt, "", "") {
+				return true, true
+			}
+		}
+		return false, true
+	case "dependency":
+		// Meson tries pkg-config, then CMake
+		return e.hasModule(req.Name, req.Op, req.Version) || e.hasCMakeConfig(req.Name), true
+	}
+
+	if commands, ok := cmakeFindTools[req.Name]; ok {
+		for _, command := range commands {
+			if isCommandAvailable(command) {
+				return true, true
+			}
+		}
+		return false, true
+	}
+	if e.hasCMakeConfig(req.Name) {
+		return true, true
+	}
+	if module, ok := cmakeFindModules[req.Name]; ok {
+		if module.PkgConfig == "" && len(module.Headers) == 0 {
+			return true, true
+		}
+		if module.PkgConfig != "" && e.hasModule(module.PkgConfig, "", "") {
+			return true, true
+		}
+		for _, header := range module.Headers {
+			if e.hasHeader(header) {
+				return true, true
+			}
+		}
+		return false, true
+	}
+	// Find modules of the project or of CMake that are not catalogued
+	if projectFindModule(path, moduleDirs, req.Name) || e.hasCMakeFindModule(req.Name) {
+		return true, true
+	}
+	return false, false
+}
+
+// packageManagerDeps returns the dependencies of conanfile.txt,
+// conanfile.py and vcpkg.json, which the package managers install instead
+// of the system
+func packageManagerDeps(path string) (conan, vcpkg []string) {
+	if data, err := os.ReadFile(filepath.Join(path, "conanfile.txt")); err == nil {
+		section := ""
+		for _, line := range strings.Split(string(data), "\n") {
+			line = strings.TrimSpace(line)
+			if strings.HasPrefix(line, "[") {
+				section = strings.Trim(line, "[]")
+				continue
+			}
+			if (section == "requires" || section == "tool_requires") && line != "" && !strings.HasPrefix(line, "#") {
+				name, _, _ := strings.Cut(line, "/")
+				conan = append(conan, name)
+			}
+		}
+	}
+	if data, err := os.ReadFile(filepath.Join(path, "conanfile.py")); err == nil {
+		for _, m := range conanPyRequireRe.FindAllStringSubmatch(string(data), -1) {
+			conan = append(conan, m[1])
+		}
+	}
+	if data, err := os.ReadFile(filepath.Join(path, "vcpkg.json")); err == nil {
+		var manifest struct {
+			Dependencies []json.RawMessage `json:"dependencies"`
+		}
+		json.Unmarshal(data, &manifest)
+		for _, raw := range manifest.Dependencies {
+			var name string
+			var dep struct {
+				Name string `json:"name"`
+			}
+			if json.Unmarshal(raw, &name) == nil {
+				vcpkg = append(vcpkg, name)
+			} else if json.Unmarshal(raw, &dep) == nil && dep.Name != "" {
+				vcpkg = append(vcpkg, dep.Name)
+			}
+		}
+	}
+	return conan, vcpkg
+}
+
+var conanPyRequireRe = regexp.MustCompile(`["']([A-Za-z0-9_.+-]+)/\[?[0-9][^"']*["']`)
+
+// normalizeLibName folds package names of CMake, Conan and vcpkg into one
+// form: OpenSSL, openssl and libopenssl match, as do boost and boost-asio
+func normalizeLibName(name string) string {
+	name = strings.ToLower(name)
+	name, _, _ = strings.Cut(name, "-")
+	name = strings.NewReplacer("_", "", ".", "").Replace(name)
+	return strings.TrimPrefix(name, "lib")
+}
+
+// conanInstalled reports whether 'conan install' has generated the files
+// the build consumes
+func conanInstalled(path string) bool {
+	matches := globAll(
+		filepath.Join(path, "conan_toolchain.cmake"),
+		filepath.Join(path, "build", "conan_toolchain.cmake"),
+		filepath.Join(path, "build", "generators", "conan_toolchain.cmake"),
+		filepath.Join(path, "build", "*", "generators", "conan_toolchain.cmake"),
+		filepath.Join(path, "build", "*", "conan_toolchain.cmake"),
+		filepath.Join(path, "conanbuildinfo.*"),
+		filepath.Join(path, "build", "conanbuildinfo.*"),
+		filepath.Join(path, "build", "conan_meson_native.ini"),
+	)
+	return len(matches) > 0
+}
+
+// checkNativeBuild checks the build tools and libraries a C/C++ project
+// needs. cmake and meson are the installed versions, or "" if unknown.
+func checkNativeBuild(path, projectType string, env *nativeEnv, cmake, meson string) []Issue {
+	issues := []Issue{}
+	build := readNativeBuild(path)
+
+	if build.CMakeMinimum != "" && cmake != "" {
+		required, err1 := semver.Parse(build.CMakeMinimum)
+		installed, err2 := semver.Parse(cmake)
+		if err1 == nil && err2 == nil && semver.Compare(installed, required) < 0 {
+			issues = append(issues, Issue{
+				Severity:    SeverityError,
+				ProjectType: projectType,
+				Message:     fmt.Sprintf("CMakeLists.txt requires CMake %s or later but %s is installed", build.CMakeMinimum, cmake),
+				Suggestion:  fmt.Sprintf("Install CMake %s or later (e.g. 'pip install cmake' or from https://cmake.org/download/)", build.CMakeMinimum),
+			})
+		}
+	}
+	if build.MesonVersion != "" && meson != "" {
+		if ok, err := semver.Satisfies(meson, strings.ReplaceAll(build.MesonVersion, " ", "")); err == nil && !ok {
+			issues = append(issues, Issue{
+				Severity:    SeverityError,
+				ProjectType: projectType,
+				Message:     fmt.Sprintf("meson.build requires Meson %s but %s is installed", build.MesonVersion, meson),
+				Suggestion:  "Upgrade Meson with 'pip install --upgrade meson'",
+			})
+		}
+	}
+
+	// A Ninja generator in CMakePresets.json needs ninja in PATH
+	if data, err := os.ReadFile(filepath.Join(path, "CMakePresets.json")); err == nil && !isCommandAvailable("ninja") {
+		var presets struct {
+			ConfigurePresets []struct {
+				Generator string `json:"generator"`
+			} `json:"configurePresets"`
+		}
+		json.Unmarshal(data, &presets)
+		for _, preset := range presets.ConfigurePresets {
+			if strings.HasPrefix(preset.Generator, "Ninja") {
+				issues = append(issues, Issue{
+					Severity:    SeverityError,
+					ProjectType: projectType,
+					Message:     fmt.Sprintf("CMakePresets.json uses the %s generator but ninja is not installed", preset.Generator),
+					Suggestion:  "Install Ninja (e.g. 'apt install ninja-build', 'brew install ninja' or 'pip install ninja')",
+				})
+				break
+			}
+		}
+	}
+
+	conan, vcpkg := packageManagerDeps(path)
+	managed := map[string]bool{}
+	for _, name := range append(append([]string{}, conan...), vcpkg...) {
+		managed[normalizeLibName(name)] = true
+	}
+	if len(conan) > 0 && !conanInstalled(path) {
+		issues = append(issues, Issue{
+			Severity:    SeverityWarning,
+			ProjectType: projectType,
+			Message:     fmt.Sprintf("Conan dependencies not installed (no conan_toolchain.cmake found): %s", strings.Join(conan, ", ")),
+			Suggestion:  "Run 'conan install . --output-folder=build --build=missing'",
+		})
+	}
+	if len(vcpkg) > 0 && os.Getenv("VCPKG_ROOT") == "" && !isCommandAvailable("vcpkg") {
+		issues = append(issues, Issue{
+			Severity:    SeverityError,
+			ProjectType: projectType,
+			Message:     fmt.Sprintf("vcpkg.json lists %d dependencies but vcpkg was not found (VCPKG_ROOT is not set and vcpkg is not in PATH)", len(vcpkg)),
+			Suggestion:  "Clone and bootstrap vcpkg (https://learn.microsoft.com/vcpkg/get_started/get-started), set VCPKG_ROOT and configure with its toolchain file",
+		})
+	}
+
+	required, optional, unknown := []string{}, []string{}, []string{}
+	reported := map[string]bool{}
+	for _, req := range build.Requirements {
+		if managed[normalizeLibName(req.Name)] || build.Vendored[strings.ToLower(req.Name)] || reported[req.Name] {
+			continue
+		}
+		found, evaluated := env.satisfied(path, build.ModuleDirs, req)
+		if found {
+			continue
+		}
+		reported[req.Name] = true
+		if !evaluated {
+			unknown = append(unknown, req.String())
+		} else if req.Required {
+			required = append(required, req.String())
+		} else {
+			optional = append(optional, req.String())
+		}
+	}
+	sort.Strings(optional)
+
+	if len(required) > 0 {
+		issues = append(issues, Issue{
+			Severity:    SeverityError,
+			ProjectType: projectType,
+			Message:     fmt.Sprintf("%d required libraries or tools are not installed: %s", len(required), strings.Join(required, ", ")),
+			Suggestion:  "Install their development packages (usually lib<name>-dev on Debian/Ubuntu, <name>-devel on Fedora), or add their install prefix to CMAKE_PREFIX_PATH or PKG_CONFIG_PATH",
+		})
+	}
+	if len(optional) > 0 {
+		issues = append(issues, Issue{
+			Severity:    SeverityInfo,
+			ProjectType: projectType,
+			Message:     fmt.Sprintf("%d optional libraries are not installed, so the features that use them will be disabled: %s", len(optional), strings.Join(optional, ", ")),
+			Suggestion:  "Install their development packages if you need those features",
+		})
+	}
+	if len(unknown) > 0 {
+		issues = append(issues, Issue{
+			Severity:    SeverityInfo,
+			ProjectType: projectType,
+			Message:     fmt.Sprintf("%d packages have no CMake package config or Find module that devdoctor could locate, so whether they are installed is unknown: %s", len(unknown), strings.Join(unknown, ", ")),
+			Suggestion:  "If CMake cannot find them, install their development packages or add their install prefix to CMAKE_PREFIX_PATH",
+		})
+	}
+	return issues
+}
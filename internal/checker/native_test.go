@@ -0,0 +1,169 @@
+package checker
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReadNativeBuild(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, "src"), 0755)
+	os.WriteFile(filepath.Join(dir, "CMakeLists.txt"), []byte(`cmake_minimum_required(VERSION 3.20...3.28)
+project(app LANGUAGES CXX)
+
+# find_package(Commented REQUIRED)
+#[[
+find_package(AlsoCommented REQUIRED)
+]]
+find_package(OpenSSL 3.0 REQUIRED)
+find_package(PkgConfig)
+pkg_check_modules(GLIB REQUIRED IMPORTED_TARGET glib-2.0>=2.56 gio-2.0)
+pkg_search_module(UUID uuid libuuid)
+
+include(FetchContent)
+FetchContent_Declare(fmt GIT_REPOSITORY https://github.com/fmtlib/fmt GIT_TAG 10.2.1)
+add_subdirectory(src)
+`), 0644)
+	os.WriteFile(filepath.Join(dir, "src", "CMakeLists.txt"), []byte(`find_package(ZLIB REQUIRED)
+find_package(${DEP})
+list(APPEND CMAKE_MODULE_PATH "${CMAKE_CURRENT_LIST_DIR}/cmake" ${EXTERNAL_MODULES})
+set(CMAKE_MODULE_PATH ${CMAKE_MODULE_PATH} "${PROJECT_SOURCE_DIR}/third_party/find;extra")
+`), 0644)
+
+	build := readNativeBuild(dir)
+	if build.CMakeMinimum != "3.20" {
+		t.Errorf("CMakeMinimum = %q, want 3.20", build.CMakeMinimum)
+	}
+	got := []string{}
+	for _, req := range build.Requirements {
+		got = append(got, req.String())
+	}
+	want := []string{
+		"OpenSSL (find_package in CMakeLists.txt:8)",
+		"PkgConfig (find_package in CMakeLists.txt:9)",
+		"glib-2.0 >= 2.56 (pkg_check_modules in CMakeLists.txt:10)",
+		"gio-2.0 (pkg_check_modules in CMakeLists.txt:10)",
+		"uuid or libuuid (pkg_search_module in CMakeLists.txt:11)",
+		"ZLIB (find_package in " + filepath.Join("src", "CMakeLists.txt") + ":1)",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("readNativeBuild() requirements:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	if !build.Requirements[0].Required || build.Requirements[1].Required || build.Requirements[0].Version != "3.0" {
+		t.Errorf("readNativeBuild() OpenSSL/PkgConfig = %+v, %+v", build.Requirements[0], build.Requirements[1])
+	}
+	if !build.Vendored["fmt"] {
+		t.Errorf("readNativeBuild() did not record FetchContent_Declare(fmt)")
+	}
+	wantDirs := []string{filepath.Join("src", "cmake"), filepath.Join("third_party", "find"), filepath.Join("src", "extra")}
+	if strings.Join(build.ModuleDirs, ",") != strings.Join(wantDirs, ",") {
+		t.Errorf("readNativeBuild() ModuleDirs = %q, want %q", build.ModuleDirs, wantDirs)
+	}
+}
+
+func TestParseMeson(t *testing.T) {
+	build := &nativeBuild{Vendored: map[string]bool{}}
+	parseMeson(`project('app', 'c', meson_version : '>= 1.1.0')
+
+thread_dep = dependency('threads')
+ssl_dep = dependency('openssl', version : '>=3.0')
+# dependency('commented')
+png_dep = dependency('libpng',
+  required : false)
+`, "meson.build", build)
+
+	if build.MesonVersion != ">= 1.1.0" {
+		t.Errorf("MesonVersion = %q", build.MesonVersion)
+	}
+	got := []string{}
+	for _, req := range build.Requirements {
+		got = append(got, req.String())
+	}
+	want := []string{
+		"openssl >= 3.0 (dependency in meson.build:4)",
+		"libpng (dependency in meson.build:6)",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("parseMeson() = %q, want %q", got, want)
+	}
+	if !build.Requirements[0].Required || build.Requirements[1].Required {
+		t.Errorf("parseMeson() required flags = %+v", build.Requirements)
+	}
+}
+
+func TestNativeEnvHasModule(t *testing.T) {
+	pcDir := t.TempDir()
+	os.WriteFile(filepath.Join(pcDir, "zlib.pc"), []byte("prefix=/usr\nName: zlib\nVersion: 1.2.13\n"), 0644)
+	env := &nativeEnv{PCDirs: []string{pcDir}}
+
+	tests := []struct {
+		name, op, version string
+		want              bool
+	}{
+		{"zlib", "", "", true},
+		{"zlib", ">=", "1.2", true},
+		{"zlib", ">=", "1.3", false},
+		{"openssl", "", "", false},
+	}
+	for _, tt := range tests {
+		if got := env.hasModule(tt.name, tt.op, tt.version); got != tt.want {
+			t.Errorf("hasModule(%q, %q, %q) = %v, want %v", tt.name, tt.op, tt.version, got, tt.want)
+		}
+	}
+}
+
+func TestCheckNativeBuild(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "CMakeLists.txt"), []byte(`cmake_minimum_required(VERSION 3.25)
+project(app CXX)
+find_package(OpenSSL REQUIRED)
+find_package(ZLIB REQUIRED)
+find_package(CURL)
+find_package(fmt CONFIG REQUIRED)
+find_package(Threads REQUIRED)
+find_package(Boost REQUIRED)
+pkg_check_modules(SDL2 REQUIRED sdl2)
+list(APPEND CMAKE_MODULE_PATH "${CMAKE_CURRENT_LIST_DIR}/build-aux/modules")
+find_package(Sodium REQUIRED)
+find_package(Frobnicate REQUIRED)
+`), 0644)
+	os.MkdirAll(filepath.Join(dir, "build-aux", "modules"), 0755)
+	os.WriteFile(filepath.Join(dir, "build-aux", "modules", "FindSodium.cmake"), nil, 0644)
+	os.WriteFile(filepath.Join(dir, "conanfile.txt"), []byte("[requires]\nfmt/10.2.1\n\n[generators]\nCMakeToolchain\n"), 0644)
+
+	prefix := t.TempDir()
+	os.MkdirAll(filepath.Join(prefix, "include", "boost"), 0755)
+	os.WriteFile(filepath.Join(prefix, "include", "boost", "version.hpp"), nil, 0644)
+	os.MkdirAll(filepath.Join(prefix, "lib", "cmake", "ZLIB"), 0755)
+	os.WriteFile(filepath.Join(prefix, "lib", "cmake", "ZLIB", "ZLIBConfig.cmake"), nil, 0644)
+	env := &nativeEnv{
+		PkgConfig:   func(spec string) bool { return false },
+		PrefixPaths: []string{prefix},
+		IncludeDirs: []string{filepath.Join(prefix, "include")},
+	}
+
+	got := []string{}
+	for _, issue := range checkNativeBuild(dir, "C++", env, "3.22.1", "") {
+		got = append(got, string(issue.Severity)+" "+issue.Message)
+	}
+	want := []string{
+		"ERROR CMakeLists.txt requires CMake 3.25 or later but 3.22.1 is installed",
+		"WARNING Conan dependencies not installed (no conan_toolchain.cmake found): fmt",
+		"ERROR 2 required libraries or tools are not installed: OpenSSL (find_package in CMakeLists.txt:3), sdl2 (pkg_check_modules in CMakeLists.txt:9)",
+		"INFO 1 optional libraries are not installed, so the features that use them will be disabled: CURL (find_package in CMakeLists.txt:5)",
+		"INFO 1 packages have no CMake package config or Find module that devdoctor could locate, so whether they are installed is unknown: Frobnicate (find_package in CMakeLists.txt:12)",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("checkNativeBuild():\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	os.MkdirAll(filepath.Join(dir, "build", "generators"), 0755)
+	os.WriteFile(filepath.Join(dir, "build", "generators", "conan_toolchain.cmake"), nil, 0644)
+	for _, issue := range checkNativeBuild(dir, "C++", env, "3.28.0", "") {
+		if strings.Contains(issue.Message, "Conan") || strings.Contains(issue.Message, "CMake 3.25") {
+			t.Errorf("checkNativeBuild() after conan install with CMake 3.28 = %q", issue.Message)
+		}
+	}
+}
@@ -0,0 +1,342 @@
+package checker
+
+import (
+	"debug/elf"
+	"debug/macho"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Sw3bbl3/devdoctor/internal/detector"
+)
+
+// maxAddonScanEntries bounds the walk of node_modules
+const maxAddonScanEntries = 300000
+
+// nodeAddon is a compiled *.node file in node_modules
+type nodeAddon struct {
+	Package string // package name, e.g. sqlite3 or @scope/name
+	File    string // path relative to the project
+	ABI     int    // NODE_MODULE_VERSION it was built for, 0 if unknown
+	NAPI    bool   // built against Node-API, which is ABI stable
+	// Prebuild marks one of several prebuilt binaries the package picks
+	// from at runtime, under prebuilds/ or a node-pre-gyp binding path
+	Prebuild bool
+}
+
+var (
+	addonABISymbolRe  = regexp.MustCompile(`^_?node_register_module_v(\d+)$`)
+	nodePreGypRe      = regexp.MustCompile(`(?:^|/)(node|electron|napi)-v(\d+)(?:\.\d+)*-([a-z0-9]+)(?:-glibc|-musl|-unknown)?-([a-z0-9]+)/`)
+	prebuildifyRe     = regexp.MustCompile(`(?:^|/)prebuilds/([a-z0-9]+)-([a-z0-9+]+)/[^/]+$`)
+	prebuildABITagRe  = regexp.MustCompile(`\.abi(\d+)\.`)
+	prebuildNAPITagRe = regexp.MustCompile(`\.napi(?:\.|$)`)
+	gypiModuleRe      = regexp.MustCompile(`['"]node_module_version['"]\s*:\s*['"]?(\d+)`)
+)
+
+// nodeABIReleases maps NODE_MODULE_VERSION to the Node.js major release
+var nodeABIReleases = map[int]string{
+	72: "12", 79: "13", 83: "14", 88: "15", 93: "16", 102: "17", 108: "18",
+	111: "19", 115: "20", 120: "21", 127: "22", 131: "23", 137: "24", 141: "25",
+}
+
+// nodePlatform returns process.platform-process.arch for this machine,
+// e.g. linux-x64 or darwin-arm64
+func nodePlatform() string {
+	platform := runtime.GOOS
+	if platform == "windows" {
+		platform = "win32"
+	}
+	arch := map[string]string{"amd64": "x64", "386": "ia32", "arm64": "arm64", "arm": "arm"}[runtime.GOARCH]
+	if arch == "" {
+		arch = runtime.GOARCH
+	}
+	return platform + "-" + arch
+}
+
+// binaryAddonABI reads the ABI of an addon from its ELF or Mach-O symbols.
+// Addons registered with NODE_MODULE_INIT export node_register_module_v<ABI>;
+// Node-API addons export napi_register_module_v1 or import only napi_*
+// functions, while V8 addons import mangled v8:: and node:: symbols.
+func binaryAddonABI(file string) (abi int, napi bool) {
+	var names []string
+	if f, err := elf.Open(file); err == nil {
+		defer f.Close()
+		syms, _ := f.DynamicSymbols()
+		for _, sym := range syms {
+			names = append(names, sym.Name)
+		}
+	} else if f, err := macho.Open(file); err == nil {
+		defer f.Close()
+		if f.Symtab != nil {
+			for _, sym := range f.Symtab.Syms {
+				names = append(names, sym.Name)
+			}
+		}
+	} else if fat, err := macho.OpenFat(file); err == nil {
+		defer fat.Close()
+		if len(fat.Arches) > 0 && fat.Arches[0].Symtab != nil {
+			for _, sym := range fat.Arches[0].Symtab.Syms {
+				names = append(names, sym.Name)
+			}
+		}
+	}
+
+	usesNAPI, usesV8 := false, false
+	for _, name := range names {
+		name = strings.TrimPrefix(name, "_")
+		if m := addonABISymbolRe.FindStringSubmatch(name); m != nil {
+			abi, _ := strconv.Atoi(m[1])
+			return abi, false
+		}
+		switch {
+		case name == "napi_register_module_v1":
+			return 0, true
+		case strings.HasPrefix(name, "napi_"):
+			usesNAPI = true
+		case strings.HasPrefix(name, "ZN2v8") || strings.HasPrefix(name, "ZN4node"):
+			usesV8 = true
+		}
+	}
+	return 0, usesNAPI && !usesV8
+}
+
+// prebuildAddonABI reads the ABI from the path of a prebuilt binary. It
+// reports whether the path is a prebuild and whether it is for platform.
+func prebuildAddonABI(rel, platform string) (abi int, napi, prebuild, forPlatform bool) {
+	if m := nodePreGypRe.FindStringSubmatch(rel); m != nil {
+		forPlatform = m[3]+"-"+m[4] == platform
+		switch m[1] {
+		case "node":
+			abi, _ = strconv.Atoi(m[2])
+		case "napi":
+			napi = true
+		}
+		return abi, napi, true, forPlatform
+	}
+	if m := prebuildifyRe.FindStringSubmatch(rel); m != nil {
+		// Universal builds list several architectures: darwin-x64+arm64
+		for _, arch := range strings.Split(m[2], "+") {
+			forPlatform = forPlatform || m[1]+"-"+arch == platform
+		}
+		base := filepath.Base(rel)
+		if strings.Contains(base, "electron") {
+			return 0, false, true, forPlatform
+		}
+		if tag := prebuildABITagRe.FindStringSubmatch(base); tag != nil {
+			abi, _ = strconv.Atoi(tag[1])
+		}
+		return abi, prebuildNAPITagRe.MatchString(base), true, forPlatform
+	}
+	return 0, false, false, true
+}
+
+// gypiAddonABI reads node_module_version from the build/config.gypi that
+// node-gyp wrote when it compiled the package
+func gypiAddonABI(pkgDir string) int {
+	data, err := os.ReadFile(filepath.Join(pkgDir, "build", "config.gypi"))
+	if err != nil {
+		return 0
+	}
+	if m := gypiModuleRe.FindSubmatch(data); m != nil {
+		abi, _ := strconv.Atoi(string(m[1]))
+		return abi
+	}
+	return 0
+}
+
+// addonPackage splits the path of an addon below node_modules into the
+// package name and the package directory
+func addonPackage(rel string) (string, string) {
+	i := strings.LastIndex(rel, "node_modules/")
+	if i < 0 {
+		return "", ""
+	}
+	parts := strings.Split(rel[i+len("node_modules/"):], "/")
+	n := 1
+	if strings.HasPrefix(parts[0], "@") && len(parts) > 2 {
+		n = 2
+	}
+	name := strings.Join(parts[:n], "/")
+	return name, rel[:i] + "node_modules/" + name
+}
+
+// scanNodeAddons finds the *.node files in node_modules and the ABI each
+// was built for. Prebuilds for other platforms are skipped.
+func scanNodeAddons(path, platform string) []nodeAddon {
+	addons := []nodeAddon{}
+	root := filepath.Join(path, "node_modules")
+	entries := 0
+	filepath.WalkDir(root, func(file string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		entries++
+		if entries > maxAddonScanEntries {
+			return filepath.SkipAll
+		}
+		if d.IsDir() {
+			if d.Name() == ".bin" || d.Name() == ".cache" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(d.Name(), ".node") || !d.Type().IsRegular() {
+			return nil
+		}
+
+		rel, _ := filepath.Rel(path, file)
+		rel = filepath.ToSlash(rel)
+		name, pkgDir := addonPackage(rel)
+		if name == "" {
+			return nil
+		}
+		addon := nodeAddon{Package: name, File: rel}
+		abi, napi, prebuild, forPlatform := prebuildAddonABI(strings.TrimPrefix(rel, pkgDir+"/"), platform)
+		if !forPlatform {
+			return nil
+		}
+		addon.Prebuild = prebuild
+		addon.ABI, addon.NAPI = binaryAddonABI(file)
+		if addon.ABI == 0 && !addon.NAPI {
+			addon.ABI, addon.NAPI = abi, napi
+		}
+		if addon.ABI == 0 && !addon.NAPI && !prebuild {
+			addon.ABI = gypiAddonABI(filepath.Join(path, filepath.FromSlash(pkgDir)))
+		}
+		addons = append(addons, addon)
+		return nil
+	})
+	return addons
+}
+
+// describeNodeABI names a NODE_MODULE_VERSION with its Node.js release
+func describeNodeABI(abi int) string {
+	if release, ok := nodeABIReleases[abi]; ok {
+		return fmt.Sprintf("%d, Node.js %s", abi, release)
+	}
+	return strconv.Itoa(abi)
+}
+
+// nodeRebuildCommand returns the command that recompiles native addons
+func nodeRebuildCommand(pm detector.NodePackageManager) string {
+	switch {
+	case pm.Name == "pnpm":
+		return "pnpm rebuild"
+	case pm.IsYarnBerry():
+		return "yarn rebuild"
+	default:
+		return "npm rebuild"
+	}
+}
+
+// checkNodeAddons reports packages whose addons were built for another
+// NODE_MODULE_VERSION than abi, the one of the installed node. A package
+// with compiled addons is judged by those; one with only prebuilds is fine
+// if any of them matches, since it picks the matching one at runtime.
+func checkNodeAddons(addons []nodeAddon, abi int, version string, pm detector.NodePackageManager) []Issue {
+	issues := []Issue{}
+	byPackage := map[string][]nodeAddon{}
+	for _, addon := range addons {
+		byPackage[addon.Package] = append(byPackage[addon.Package], addon)
+	}
+
+	mismatched := []string{}
+	names := []string{}
+	for name, pkgAddons := range byPackage {
+		built := []nodeAddon{}
+		for _, addon := range pkgAddons {
+			if !addon.Prebuild {
+				built = append(built, addon)
+			}
+		}
+
+		abis := map[int]bool{}
+		if len(built) > 0 {
+			for _, addon := range built {
+				if addon.ABI != 0 && !addon.NAPI && addon.ABI != abi {
+					abis[addon.ABI] = true
+				}
+			}
+		} else {
+			usable := false
+			for _, addon := range pkgAddons {
+				usable = usable || addon.NAPI || addon.ABI == 0 || addon.ABI == abi
+				if addon.ABI != 0 {
+					abis[addon.ABI] = true
+				}
+			}
+			if usable {
+				continue
+			}
+		}
+		if len(abis) == 0 {
+			continue
+		}
+
+		found := []int{}
+		for a := range abis {
+			found = append(found, a)
+		}
+		sort.Ints(found)
+		described := []string{}
+		for _, a := range found {
+			described = append(described, describeNodeABI(a))
+		}
+		mismatched = append(mismatched, fmt.Sprintf("%s (built for %s)", name, strings.Join(described, "; ")))
+		names = append(names, name)
+	}
+
+	if len(mismatched) > 0 {
+		sort.Strings(mismatched)
+		sort.Strings(names)
+		issues = append(issues, Issue{
+			Severity:    SeverityError,
+			ProjectType: "Node.js",
+			Message:     fmt.Sprintf("%d packages have native addons built for a different NODE_MODULE_VERSION than node %s (%d): %s", len(mismatched), version, abi, strings.Join(mismatched, ", ")),
+			Suggestion:  fmt.Sprintf("Run '%s %s' with the current node, or switch back to the Node.js version they were built with", nodeRebuildCommand(pm), strings.Join(names, " ")),
+		})
+	}
+	return issues
+}
+
+// installedNodeABI returns process.versions.modules and the version of the
+// node that fnm, asdf or volta select for path
+func installedNodeABI(path string) (int, string) {
+	cmd := exec.Command("node", "-p", "process.versions.modules + ' ' + process.version")
+	cmd.Dir = path
+	out, err := cmd.Output()
+	if err != nil {
+		return 0, ""
+	}
+	fields := strings.Fields(string(out))
+	if len(fields) != 2 {
+		return 0, ""
+	}
+	abi, _ := strconv.Atoi(fields[0])
+	return abi, fields[1]
+}
+
+// checkNodeABI checks the native addons in node_modules against the
+// installed node
+func checkNodeABI(path string, pm detector.NodePackageManager) []Issue {
+	// Electron apps rebuild their addons for Electron's ABI
+	if pathExists(filepath.Join(path, "node_modules", "electron")) {
+		return []Issue{}
+	}
+	addons := scanNodeAddons(path, nodePlatform())
+	if len(addons) == 0 {
+		return []Issue{}
+	}
+	abi, version := installedNodeABI(path)
+	if abi == 0 {
+		return []Issue{}
+	}
+	return checkNodeAddons(addons, abi, version, pm)
+}
@@ -0,0 +1,125 @@
+package checker
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Sw3bbl3/devdoctor/internal/detector"
+)
+
+func TestPrebuildAddonABI(t *testing.T) {
+	tests := []struct {
+		rel         string
+		abi         int
+		napi        bool
+		prebuild    bool
+		forPlatform bool
+	}{
+		{"build/Release/addon.node", 0, false, false, true},
+		{"lib/binding/node-v108-linux-x64/node_sqlite3.node", 108, false, true, true},
+		{"lib/binding/node-v115-darwin-arm64/node_sqlite3.node", 115, false, true, false},
+		{"lib/binding/napi-v6-linux-glibc-x64/node_sqlite3.node", 0, true, true, true},
+		{"binding/napi-v3-linux-x64/binding.node", 0, true, true, true},
+		{"prebuilds/linux-x64/node.abi115.node", 115, false, true, true},
+		{"prebuilds/linux-x64/classic-level.glibc.node", 0, false, true, true},
+		{"prebuilds/linux-x64/node.napi.glibc.node", 0, true, true, true},
+		{"prebuilds/darwin-x64+arm64/node.napi.node", 0, true, true, false},
+		{"prebuilds/linux-x64/electron.abi118.node", 0, false, true, true},
+	}
+	for _, tt := range tests {
+		abi, napi, prebuild, forPlatform := prebuildAddonABI(tt.rel, "linux-x64")
+		if abi != tt.abi || napi != tt.napi || prebuild != tt.prebuild || forPlatform != tt.forPlatform {
+			t.Errorf("prebuildAddonABI(%q) = %d, %v, %v, %v, want %d, %v, %v, %v", tt.rel, abi, napi, prebuild, forPlatform, tt.abi, tt.napi, tt.prebuild, tt.forPlatform)
+		}
+	}
+}
+
+func TestAddonPackage(t *testing.T) {
+	tests := []struct {
+		rel, name, dir string
+	}{
+		{"node_modules/bcrypt/lib/binding/napi-v3/bcrypt_lib.node", "bcrypt", "node_modules/bcrypt"},
+		{"node_modules/@img/sharp-linux-x64/lib/sharp.node", "@img/sharp-linux-x64", "node_modules/@img/sharp-linux-x64"},
+		{"node_modules/.pnpm/sqlite3@5.1.7/node_modules/sqlite3/build/Release/node_sqlite3.node", "sqlite3", "node_modules/.pnpm/sqlite3@5.1.7/node_modules/sqlite3"},
+	}
+	for _, tt := range tests {
+		name, dir := addonPackage(tt.rel)
+		if name != tt.name || dir != tt.dir {
+			t.Errorf("addonPackage(%q) = %q, %q, want %q, %q", tt.rel, name, dir, tt.name, tt.dir)
+		}
+	}
+}
+
+func TestScanNodeAddons(t *testing.T) {
+	dir := t.TempDir()
+	write := func(rel, content string) {
+		file := filepath.Join(dir, filepath.FromSlash(rel))
+		os.MkdirAll(filepath.Dir(file), 0755)
+		os.WriteFile(file, []byte(content), 0644)
+	}
+	// The binaries are not ELF files, so their ABI comes from the paths
+	// and from build/config.gypi
+	write("node_modules/sqlite3/build/Release/node_sqlite3.node", "binary")
+	write("node_modules/sqlite3/build/config.gypi", "# Do not edit. File was generated by node-gyp's \"configure\" step\n{\n  \"variables\": {\n    \"napi_build_version\": \"9\",\n    \"node_module_version\": 108,\n  }\n}\n")
+	write("node_modules/leveldown/prebuilds/linux-x64/node.napi.glibc.node", "binary")
+	write("node_modules/leveldown/prebuilds/darwin-x64+arm64/node.napi.node", "binary")
+	write("node_modules/usb/prebuilds/linux-x64/node.abi108.node", "binary")
+	write("node_modules/usb/prebuilds/linux-x64/node.abi115.node", "binary")
+	write("node_modules/.bin/fake.node", "binary")
+
+	got := []string{}
+	for _, addon := range scanNodeAddons(dir, "linux-x64") {
+		got = append(got, addon.File)
+		if addon.File == "node_modules/sqlite3/build/Release/node_sqlite3.node" && (addon.ABI != 108 || addon.Prebuild) {
+			t.Errorf("scanNodeAddons() sqlite3 = %+v, want ABI 108 from config.gypi", addon)
+		}
+	}
+	want := []string{
+		"node_modules/leveldown/prebuilds/linux-x64/node.napi.glibc.node",
+		"node_modules/sqlite3/build/Release/node_sqlite3.node",
+		"node_modules/usb/prebuilds/linux-x64/node.abi108.node",
+		"node_modules/usb/prebuilds/linux-x64/node.abi115.node",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("scanNodeAddons() = %q, want %q", got, want)
+	}
+}
+
+func TestCheckNodeAddons(t *testing.T) {
+	addons := []nodeAddon{
+		{Package: "sqlite3", File: "node_modules/sqlite3/build/Release/node_sqlite3.node", ABI: 108},
+		{Package: "bcrypt", File: "node_modules/bcrypt/lib/binding/napi-v3/bcrypt_lib.node", NAPI: true},
+		{Package: "usb", File: "node_modules/usb/prebuilds/linux-x64/node.abi108.node", ABI: 108, Prebuild: true},
+		{Package: "usb", File: "node_modules/usb/prebuilds/linux-x64/node.abi115.node", ABI: 115, Prebuild: true},
+		{Package: "serialport", File: "node_modules/serialport/prebuilds/linux-x64/node.abi102.node", ABI: 102, Prebuild: true},
+		{Package: "unknown", File: "node_modules/unknown/addon.node"},
+	}
+	issues := checkNodeAddons(addons, 115, "v20.11.0", detector.NodePackageManager{Name: "pnpm"})
+	if len(issues) != 1 {
+		t.Fatalf("checkNodeAddons() = %v, want one issue", issues)
+	}
+	want := "2 packages have native addons built for a different NODE_MODULE_VERSION than node v20.11.0 (115): serialport (built for 102, Node.js 17), sqlite3 (built for 108, Node.js 18)"
+	if issues[0].Message != want {
+		t.Errorf("checkNodeAddons() message = %q, want %q", issues[0].Message, want)
+	}
+	if issues[0].Suggestion != "Run 'pnpm rebuild serialport sqlite3' with the current node, or switch back to the Node.js version they were built with" {
+		t.Errorf("checkNodeAddons() suggestion = %q", issues[0].Suggestion)
+	}
+
+	if issues := checkNodeAddons(addons[:4], 108, "v18.19.0", detector.NodePackageManager{Name: "npm"}); len(issues) != 0 {
+		t.Errorf("checkNodeAddons() on Node.js 18 = %v, want none", issues)
+	}
+}
+
+func TestInstalledNodeABIRunsFromProject(t *testing.T) {
+	installDirShims(t, "127 v22.1.0", map[string]string{"node": "$v"})
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, ".shim-version"), []byte("108 v18.20.0\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if abi, version := installedNodeABI(dir); abi != 108 || version != "v18.20.0" {
+		t.Errorf("installedNodeABI() = %d, %q, want the node the project selects", abi, version)
+	}
+}
@@ -0,0 +1,136 @@
+package checker
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Sw3bbl3/devdoctor/internal/detector"
+	"github.com/Sw3bbl3/devdoctor/internal/envcheck"
+	"github.com/Sw3bbl3/devdoctor/internal/semver"
+)
+
+// nodeInstallCommand returns the command that installs dependencies exactly
+// as locked for the given package manager
+func nodeInstallCommand(pm detector.NodePackageManager) string {
+	if len(pm.Lockfiles) == 0 {
+		return pm.Name + " install"
+	}
+	switch pm.Name {
+	case "npm":
+		return "npm ci"
+	case "yarn":
+		if pm.IsYarnBerry() {
+			return "yarn install --immutable"
+		}
+		return "yarn install --frozen-lockfile"
+	default:
+		return pm.Name + " install --frozen-lockfile"
+	}
+}
+
+// checkNodePackageManager verifies the lockfiles and the installed version of
+// the project's package manager
+func checkNodePackageManager(path string, pm detector.NodePackageManager) []Issue {
+	issues := []Issue{}
+
+	if len(pm.Lockfiles) > 1 {
+		issues = append(issues, Issue{
+			Severity:    SeverityWarning,
+			ProjectType: "Node.js",
+			Message:     fmt.Sprintf("Multiple lockfiles found (%s)", strings.Join(pm.Lockfiles, ", ")),
+			Suggestion:  fmt.Sprintf("The project uses %s (from %s); delete the other lockfiles so every install resolves the same versions", pm.Name, pm.Source),
+		})
+	}
+
+	if pm.YarnPath != "" {
+		// Any global yarn delegates to the release checked in at yarnPath
+		if !fileExists(path, pm.YarnPath) {
+			issues = append(issues, Issue{
+				Severity:    SeverityError,
+				ProjectType: "Node.js",
+				Message:     fmt.Sprintf("Yarn release '%s' referenced by yarnPath in .yarnrc.yml not found", pm.YarnPath),
+				Suggestion:  "Restore the file from version control or run 'yarn set version' to download it again",
+			})
+		}
+		return issues
+	}
+
+	if pm.Version == "" || !isCommandAvailable(pm.Name) {
+		return issues
+	}
+	installed := envcheck.LookupIn(path, pm.Name).Version
+	if installed == "" || installed == pm.Version {
+		return issues
+	}
+
+	suggestion := fmt.Sprintf("Run 'corepack enable' so Corepack provides %s@%s, or install it with 'npm install -g %s@%s'", pm.Name, pm.Version, pm.Name, pm.Version)
+	wantMajor, _, _ := strings.Cut(pm.Version, ".")
+	gotMajor, _, _ := strings.Cut(installed, ".")
+	if wantMajor != gotMajor {
+		issues = append(issues, Issue{
+			Severity:    SeverityError,
+			ProjectType: "Node.js",
+			Message:     fmt.Sprintf("Project requires %s %s (%s) but %s is installed", pm.Name, pm.Version, pm.Source, installed),
+			Suggestion:  suggestion,
+		})
+	} else {
+		issues = append(issues, Issue{
+			Severity:    SeverityWarning,
+			ProjectType: "Node.js",
+			Message:     fmt.Sprintf("Project pins %s %s (%s) but %s is installed", pm.Name, pm.Version, pm.Source, installed),
+			Suggestion:  suggestion,
+		})
+	}
+
+	return issues
+}
+
+// nodeEngines maps package.json engines keys to the command that reports
+// their version and the name shown in issues
+var nodeEngines = []struct {
+	Key, Command, Label, Suggestion string
+}{
+	{"node", "node", "Node.js", "Install a matching Node.js version, e.g. with 'nvm install' or 'fnm install'"},
+	{"npm", "npm", "npm", "Install a matching npm version with 'npm install -g npm@<version>'"},
+}
+
+// checkNodeEngines evaluates the engines ranges of package.json against the
+// node and npm the project directory selects
+func checkNodeEngines(path string, engines map[string]interface{}) []Issue {
+	issues := []Issue{}
+
+	for _, engine := range nodeEngines {
+		constraint, ok := engines[engine.Key].(string)
+		if !ok {
+			continue
+		}
+		r, err := semver.ParseRange(constraint)
+		if err != nil {
+			issues = append(issues, Issue{
+				Severity:    SeverityWarning,
+				ProjectType: "Node.js",
+				Message:     fmt.Sprintf("Cannot evaluate engines.%s range '%s': %v", engine.Key, constraint, err),
+				Suggestion:  "Use a valid semver range in package.json (see https://github.com/npm/node-semver#ranges)",
+			})
+			continue
+		}
+
+		// Run from the project so fnm, asdf or volta select its node
+		status := envcheck.LookupIn(path, engine.Command)
+		if !status.Found || status.Version == "" {
+			continue
+		}
+		installed, err := semver.Parse(status.Version)
+		if err != nil || r.Contains(installed) {
+			continue
+		}
+		issues = append(issues, Issue{
+			Severity:    SeverityError,
+			ProjectType: "Node.js",
+			Message:     fmt.Sprintf("Project requires %s %s (engines.%s) but %s is installed", engine.Label, constraint, engine.Key, status.Version),
+			Suggestion:  engine.Suggestion,
+		})
+	}
+
+	return issues
+}
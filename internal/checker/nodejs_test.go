@@ -0,0 +1,109 @@
+package checker
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Sw3bbl3/devdoctor/internal/detector"
+)
+
+func TestNodeInstallCommand(t *testing.T) {
+	tests := []struct {
+		pm   detector.NodePackageManager
+		want string
+	}{
+		{detector.NodePackageManager{Name: "npm"}, "npm install"},
+		{detector.NodePackageManager{Name: "npm", Lockfiles: []string{"package-lock.json"}}, "npm ci"},
+		{detector.NodePackageManager{Name: "pnpm", Lockfiles: []string{"pnpm-lock.yaml"}}, "pnpm install --frozen-lockfile"},
+		{detector.NodePackageManager{Name: "yarn", Lockfiles: []string{"yarn.lock"}}, "yarn install --frozen-lockfile"},
+		{detector.NodePackageManager{Name: "yarn", Version: "4.1.0", Lockfiles: []string{"yarn.lock"}}, "yarn install --immutable"},
+		{detector.NodePackageManager{Name: "bun", Lockfiles: []string{"bun.lockb"}}, "bun install --frozen-lockfile"},
+	}
+
+	for _, tt := range tests {
+		if got := nodeInstallCommand(tt.pm); got != tt.want {
+			t.Errorf("nodeInstallCommand(%+v) = %s, want %s", tt.pm, got, tt.want)
+		}
+	}
+}
+
+func TestCheckNodeJSPackageManager(t *testing.T) {
+	tmpDir := t.TempDir()
+	for _, file := range []string{"package.json", "package-lock.json", "pnpm-lock.yaml"} {
+		if err := os.WriteFile(filepath.Join(tmpDir, file), []byte("{}"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	issues := checkNodeJS(tmpDir)
+	hasLockfileWarning := false
+	hasPnpmSuggestion := false
+	for _, issue := range issues {
+		if strings.HasPrefix(issue.Message, "Multiple lockfiles found") {
+			hasLockfileWarning = true
+		}
+		if strings.Contains(issue.Suggestion, "pnpm install --frozen-lockfile") {
+			hasPnpmSuggestion = true
+		}
+	}
+	if !hasLockfileWarning {
+		t.Error("Expected multiple lockfiles warning")
+	}
+	if !hasPnpmSuggestion {
+		t.Error("Expected install suggestion to use pnpm")
+	}
+}
+
+func TestCheckNodeJSMissingYarnRelease(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmpDir, "package.json"), []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, ".yarnrc.yml"), []byte("yarnPath: .yarn/releases/yarn-4.1.0.cjs\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	issues := checkNodeJS(tmpDir)
+	found := false
+	for _, issue := range issues {
+		if issue.Severity == SeverityError && strings.Contains(issue.Message, "yarn-4.1.0.cjs") {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("Expected error for missing yarnPath release")
+	}
+}
+
+func TestCheckNodeEngines(t *testing.T) {
+	issues := checkNodeEngines(t.TempDir(), map[string]interface{}{"node": "latest-lts"})
+	if len(issues) != 1 || issues[0].Severity != SeverityWarning {
+		t.Errorf("Expected a warning for an invalid range, got %v", issues)
+	}
+
+	if !isCommandAvailable("node") {
+		t.Skip("node is not in PATH")
+	}
+	issues = checkNodeEngines(t.TempDir(), map[string]interface{}{"node": "<0.1.0"})
+	if len(issues) != 1 || issues[0].Severity != SeverityError {
+		t.Errorf("Expected an error for an unsatisfied range, got %v", issues)
+	}
+	if issues := checkNodeEngines(t.TempDir(), map[string]interface{}{"node": ">=0.1.0"}); len(issues) != 0 {
+		t.Errorf("Expected no issues for a satisfied range, got %v", issues)
+	}
+}
+
+func TestCheckNodeEnginesRunsFromProject(t *testing.T) {
+	installDirShims(t, "22.1.0", map[string]string{"node": "v$v"})
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, ".shim-version"), []byte("18.20.0\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	issues := checkNodeEngines(dir, map[string]interface{}{"node": ">=20"})
+	if len(issues) != 1 || !strings.HasSuffix(issues[0].Message, "but 18.20.0 is installed") {
+		t.Errorf("checkNodeEngines() = %v, want the node the project selects", issues)
+	}
+}
@@ -0,0 +1,402 @@
+package checker
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/Sw3bbl3/devdoctor/internal/detector"
+	"github.com/Sw3bbl3/devdoctor/internal/semver"
+	"github.com/Sw3bbl3/devdoctor/internal/yaml"
+)
+
+// nodeLock is what a lockfile records about the root package
+type nodeLock struct {
+	File string
+	// Specs maps each dependency of the root package to the ranges it was
+	// resolved for. It is nil for lockfiles that only record versions.
+	Specs map[string][]string
+	// Installed maps install paths such as node_modules/a or
+	// node_modules/a/node_modules/b to the locked version
+	Installed map[string]string
+	// Optional marks install paths that may be skipped on this platform
+	Optional map[string]bool
+}
+
+func newNodeLock(file string) *nodeLock {
+	return &nodeLock{File: file, Installed: map[string]string{}, Optional: map[string]bool{}}
+}
+
+// nodeLockfiles maps each package manager to the lockfiles it writes
+var nodeLockfiles = map[string][]string{
+	"npm":  {"package-lock.json", "npm-shrinkwrap.json"},
+	"yarn": {"yarn.lock"},
+	"pnpm": {"pnpm-lock.yaml"},
+}
+
+// nodeDependencies returns the dependencies declared in package.json, with
+// optional ones reported separately
+func nodeDependencies(packageJSON map[string]interface{}) (map[string]string, map[string]bool) {
+	deps := map[string]string{}
+	optional := map[string]bool{}
+	for _, field := range []string{"dependencies", "devDependencies", "optionalDependencies"} {
+		m, _ := packageJSON[field].(map[string]interface{})
+		for name, spec := range m {
+			s, ok := spec.(string)
+			if !ok || isLocalNodeSpec(s) {
+				continue
+			}
+			deps[name] = s
+			if field == "optionalDependencies" {
+				optional[name] = true
+			}
+		}
+	}
+	return deps, optional
+}
+
+// isLocalNodeSpec reports whether a dependency points into the repository
+// rather than at a published version
+func isLocalNodeSpec(spec string) bool {
+	for _, prefix := range []string{"workspace:", "link:", "file:", "portal:"} {
+		if strings.HasPrefix(spec, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// readNodeLock parses the lockfile of the project's package manager. It
+// returns nil when there is no lockfile it can read.
+func readNodeLock(path string, pm detector.NodePackageManager, deps map[string]string) (*nodeLock, error) {
+	for _, file := range nodeLockfiles[pm.Name] {
+		data, err := os.ReadFile(filepath.Join(path, file))
+		if err != nil {
+			continue
+		}
+		var lock *nodeLock
+		switch pm.Name {
+		case "npm":
+			lock, err = parsePackageLock(file, data)
+		case "yarn":
+			lock = parseYarnLock(file, data, deps)
+		case "pnpm":
+			lock, err = parsePnpmLock(file, data)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		return lock, nil
+	}
+	return nil, nil
+}
+
+type packageLockPackage struct {
+	Version              string            `json:"version"`
+	Link                 bool              `json:"link"`
+	Optional             bool              `json:"optional"`
+	Dependencies         map[string]string `json:"dependencies"`
+	DevDependencies      map[string]string `json:"devDependencies"`
+	OptionalDependencies map[string]string `json:"optionalDependencies"`
+}
+
+type packageLockDependency struct {
+	Version      string                           `json:"version"`
+	Optional     bool                             `json:"optional"`
+	Dependencies map[string]packageLockDependency `json:"dependencies"`
+}
+
+// parsePackageLock reads package-lock.json and npm-shrinkwrap.json. Version 2
+// and 3 lockfiles list every install path under "packages"; version 1 nests
+// "dependencies" the way node_modules is nested.
+func parsePackageLock(file string, data []byte) (*nodeLock, error) {
+	var doc struct {
+		Packages     map[string]packageLockPackage    `json:"packages"`
+		Dependencies map[string]packageLockDependency `json:"dependencies"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+
+	lock := newNodeLock(file)
+	if doc.Packages != nil {
+		root := doc.Packages[""]
+		lock.Specs = map[string][]string{}
+		for _, m := range []map[string]string{root.Dependencies, root.DevDependencies, root.OptionalDependencies} {
+			for name, spec := range m {
+				lock.Specs[name] = append(lock.Specs[name], spec)
+			}
+		}
+		for installPath, pkg := range doc.Packages {
+			if !strings.Contains(installPath, "node_modules/") || pkg.Link || pkg.Version == "" {
+				continue
+			}
+			lock.Installed[installPath] = pkg.Version
+			if pkg.Optional {
+				lock.Optional[installPath] = true
+			}
+		}
+		return lock, nil
+	}
+
+	var walk func(prefix string, deps map[string]packageLockDependency)
+	walk = func(prefix string, deps map[string]packageLockDependency) {
+		for name, dep := range deps {
+			installPath := prefix + "node_modules/" + name
+			lock.Installed[installPath] = dep.Version
+			if dep.Optional {
+				lock.Optional[installPath] = true
+			}
+			walk(installPath+"/", dep.Dependencies)
+		}
+	}
+	walk("", doc.Dependencies)
+	return lock, nil
+}
+
+// parseYarnLock reads yarn.lock in both the classic and the Berry format.
+// Entries are keyed by descriptors rather than install paths, so only the
+// direct dependencies are mapped to node_modules.
+func parseYarnLock(file string, data []byte, deps map[string]string) *nodeLock {
+	lock := newNodeLock(file)
+	lock.Specs = map[string][]string{}
+	resolved := map[string]string{}
+
+	var descriptors []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), " \r")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if !strings.HasPrefix(line, " ") {
+			descriptors = nil
+			for _, d := range strings.Split(strings.TrimSuffix(line, ":"), ",") {
+				d = strings.Trim(strings.TrimSpace(d), `"`)
+				name, spec, ok := yarnDescriptor(d)
+				if !ok {
+					continue
+				}
+				lock.Specs[name] = append(lock.Specs[name], spec)
+				descriptors = append(descriptors, d)
+			}
+			continue
+		}
+		if !strings.HasPrefix(line, "  ") || strings.HasPrefix(line, "   ") {
+			continue
+		}
+		key, value, ok := strings.Cut(strings.TrimSpace(line), " ")
+		if !ok || strings.TrimSuffix(key, ":") != "version" {
+			continue
+		}
+		for _, d := range descriptors {
+			resolved[d] = strings.Trim(strings.TrimSpace(value), `"`)
+		}
+	}
+
+	for name, spec := range deps {
+		for _, spec := range berrySpecs(spec) {
+			if version, ok := resolved[name+"@"+spec]; ok {
+				lock.Installed["node_modules/"+name] = version
+				break
+			}
+		}
+	}
+	return lock
+}
+
+// yarnDescriptor splits a yarn.lock descriptor into the package name and
+// its range. Scoped names start with "@", and the range of an alias,
+// foo@npm:bar@^1.0.0, holds another one.
+func yarnDescriptor(d string) (name, spec string, ok bool) {
+	if d == "" {
+		return "", "", false
+	}
+	at := strings.Index(d[1:], "@") + 1
+	if at <= 0 {
+		return "", "", false
+	}
+	return d[:at], d[at+1:], true
+}
+
+// berrySpecs returns the ranges yarn.lock may record for a package.json
+// range: Berry prefixes plain ranges with "npm:", while aliases
+// (npm:<name>@<range>) and other protocols are kept as they are
+func berrySpecs(spec string) []string {
+	if strings.Contains(spec, ":") {
+		return []string{spec}
+	}
+	return []string{spec, "npm:" + spec}
+}
+
+// parsePnpmLock reads pnpm-lock.yaml. Lockfile v5 keeps ranges in a separate
+// "specifiers" map; v6 and later store {specifier, version} per dependency.
+// Workspaces record the root package under importers["."].
+func parsePnpmLock(file string, data []byte) (*nodeLock, error) {
+	docs, err := yaml.DecodeAll(data)
+	if err != nil {
+		return nil, err
+	}
+
+	lock := newNodeLock(file)
+	lock.Specs = map[string][]string{}
+	for _, doc := range docs {
+		if yaml.Lookup(doc, "lockfileVersion") == nil {
+			continue
+		}
+		root := yaml.Map(doc)
+		if importer := yaml.Map(yaml.Lookup(doc, "importers", ".")); importer != nil {
+			root = importer
+		}
+		specifiers := yaml.Map(root["specifiers"])
+		for _, field := range []string{"dependencies", "devDependencies", "optionalDependencies"} {
+			for name, entry := range yaml.Map(root[field]) {
+				spec := yaml.String(specifiers[name])
+				version := yaml.String(entry)
+				if m := yaml.Map(entry); m != nil {
+					spec, version = yaml.String(m["specifier"]), yaml.String(m["version"])
+				}
+				if spec != "" {
+					lock.Specs[name] = append(lock.Specs[name], spec)
+				}
+				// Strip peer dependency suffixes: 1.0.0(react@18.2.0) or 1.0.0_react@18.2.0
+				version, _, _ = strings.Cut(version, "(")
+				version, _, _ = strings.Cut(version, "_")
+				if version == "" || strings.HasPrefix(version, "link:") {
+					continue
+				}
+				lock.Installed["node_modules/"+name] = version
+				if field == "optionalDependencies" {
+					lock.Optional["node_modules/"+name] = true
+				}
+			}
+		}
+		break
+	}
+	return lock, nil
+}
+
+// checkNodeLockfile compares package.json with the lockfile and the
+// lockfile with what is installed in node_modules
+func checkNodeLockfile(path string, pm detector.NodePackageManager, packageJSON map[string]interface{}) []Issue {
+	issues := []Issue{}
+	deps, optional := nodeDependencies(packageJSON)
+
+	lock, err := readNodeLock(path, pm, deps)
+	if err != nil {
+		return append(issues, Issue{
+			Severity:    SeverityWarning,
+			ProjectType: "Node.js",
+			Message:     fmt.Sprintf("Cannot parse lockfile %v", err),
+			Suggestion:  fmt.Sprintf("Resolve merge conflicts or regenerate the lockfile with '%s install'", pm.Name),
+		})
+	}
+	if lock == nil {
+		return issues
+	}
+
+	// (a) Dependencies added to package.json without updating the lockfile
+	missing := []string{}
+	for name, spec := range deps {
+		if lock.Specs == nil {
+			if _, ok := lock.Installed["node_modules/"+name]; !ok {
+				missing = append(missing, name)
+			}
+			continue
+		}
+		found := false
+		for _, spec := range berrySpecs(spec) {
+			found = found || containsString(lock.Specs[name], spec)
+		}
+		if !found {
+			missing = append(missing, name+"@"+spec)
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		issues = append(issues, Issue{
+			Severity:    SeverityError,
+			ProjectType: "Node.js",
+			Message:     fmt.Sprintf("%d dependencies in package.json are missing from %s: %s", len(missing), lock.File, strings.Join(missing, ", ")),
+			Suggestion:  fmt.Sprintf("Run '%s install' to update the lockfile and commit it", pm.Name),
+		})
+	}
+
+	// (b) Installed packages that differ from the lockfile. Yarn Plug'n'Play
+	// installs do not use node_modules at all.
+	if !fileExists(path, "node_modules") || isYarnPnP(path) {
+		return issues
+	}
+	drifted, absent := []string{}, []string{}
+	for installPath, locked := range lock.Installed {
+		if _, err := semver.Parse(locked); err != nil {
+			continue // git, tarball and alias dependencies
+		}
+		name := strings.TrimPrefix(installPath, "node_modules/")
+		installed, err := installedNodeVersion(filepath.Join(path, filepath.FromSlash(installPath)))
+		if err != nil {
+			if !lock.Optional[installPath] && !optional[name] {
+				absent = append(absent, name)
+			}
+			continue
+		}
+		if installed != locked {
+			drifted = append(drifted, fmt.Sprintf("%s (installed %s, locked %s)", name, installed, locked))
+		}
+	}
+	if len(absent) > 0 {
+		sort.Strings(absent)
+		issues = append(issues, Issue{
+			Severity:    SeverityError,
+			ProjectType: "Node.js",
+			Message:     fmt.Sprintf("%d packages in %s are not installed: %s", len(absent), lock.File, strings.Join(absent, ", ")),
+			Suggestion:  fmt.Sprintf("Run '%s' to install the locked versions", nodeInstallCommand(pm)),
+		})
+	}
+	if len(drifted) > 0 {
+		sort.Strings(drifted)
+		issues = append(issues, Issue{
+			Severity:    SeverityWarning,
+			ProjectType: "Node.js",
+			Message:     fmt.Sprintf("%d packages in node_modules differ from %s: %s", len(drifted), lock.File, strings.Join(drifted, ", ")),
+			Suggestion:  fmt.Sprintf("Run '%s' to install the locked versions", nodeInstallCommand(pm)),
+		})
+	}
+
+	return issues
+}
+
+// installedNodeVersion reads the version from an installed package
+func installedNodeVersion(dir string) (string, error) {
+	data, err := os.ReadFile(filepath.Join(dir, "package.json"))
+	if err != nil {
+		return "", err
+	}
+	var pkg struct {
+		Version string `json:"version"`
+	}
+	if err := json.Unmarshal(data, &pkg); err != nil {
+		return "", err
+	}
+	return pkg.Version, nil
+}
+
+// isYarnPnP reports whether Yarn installed the project with Plug'n'Play
+func isYarnPnP(path string) bool {
+	return fileExists(path, ".pnp.cjs") || fileExists(path, ".pnp.js")
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
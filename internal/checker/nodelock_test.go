@@ -0,0 +1,201 @@
+package checker
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Sw3bbl3/devdoctor/internal/detector"
+)
+
+func TestParseNodeLockfiles(t *testing.T) {
+	deps := map[string]string{"lodash": "^4.17.0", "@babel/core": "^7.22.0", "string-width-cjs": "npm:string-width@^4.2.0"}
+	tests := []struct {
+		name, file, content string
+		wantSpecs           map[string][]string
+		wantInstalled       map[string]string
+	}{
+		{
+			name: "package-lock v3",
+			file: "package-lock.json",
+			content: `{"lockfileVersion": 3, "packages": {
+				"": {"dependencies": {"lodash": "^4.17.0"}},
+				"node_modules/lodash": {"version": "4.17.21"},
+				"node_modules/a/node_modules/b": {"version": "1.0.0"},
+				"node_modules/local": {"resolved": "packages/local", "link": true}
+			}}`,
+			wantSpecs:     map[string][]string{"lodash": {"^4.17.0"}},
+			wantInstalled: map[string]string{"node_modules/lodash": "4.17.21", "node_modules/a/node_modules/b": "1.0.0"},
+		},
+		{
+			name: "package-lock v1",
+			file: "package-lock.json",
+			content: `{"lockfileVersion": 1, "dependencies": {
+				"a": {"version": "2.0.0", "dependencies": {"b": {"version": "1.0.0"}}}
+			}}`,
+			wantInstalled: map[string]string{"node_modules/a": "2.0.0", "node_modules/a/node_modules/b": "1.0.0"},
+		},
+		{
+			name: "yarn classic",
+			file: "yarn.lock",
+			content: `# yarn lockfile v1
+
+"@babel/core@^7.0.0", "@babel/core@^7.22.0":
+  version "7.22.5"
+  resolved "https://registry.yarnpkg.com/@babel/core/-/core-7.22.5.tgz"
+
+lodash@^4.17.0:
+  version "4.17.21"
+`,
+			wantSpecs:     map[string][]string{"@babel/core": {"^7.0.0", "^7.22.0"}, "lodash": {"^4.17.0"}},
+			wantInstalled: map[string]string{"node_modules/@babel/core": "7.22.5", "node_modules/lodash": "4.17.21"},
+		},
+		{
+			name: "yarn berry",
+			file: "yarn.lock",
+			content: `__metadata:
+  version: 6
+
+"lodash@npm:^4.17.0":
+  version: 4.17.21
+  dependencies:
+    version: not-this
+`,
+			wantSpecs:     map[string][]string{"lodash": {"npm:^4.17.0"}},
+			wantInstalled: map[string]string{"node_modules/lodash": "4.17.21"},
+		},
+		{
+			name: "yarn alias",
+			file: "yarn.lock",
+			content: `# yarn lockfile v1
+
+"string-width-cjs@npm:string-width@^4.2.0":
+  version "4.2.3"
+`,
+			wantSpecs:     map[string][]string{"string-width-cjs": {"npm:string-width@^4.2.0"}},
+			wantInstalled: map[string]string{"node_modules/string-width-cjs": "4.2.3"},
+		},
+		{
+			name: "pnpm v9",
+			file: "pnpm-lock.yaml",
+			content: `lockfileVersion: '9.0'
+
+importers:
+
+  .:
+    dependencies:
+      '@babel/core':
+        specifier: ^7.22.0
+        version: 7.22.5(supports-color@8.1.1)
+      local:
+        specifier: workspace:*
+        version: link:packages/local
+`,
+			wantSpecs:     map[string][]string{"@babel/core": {"^7.22.0"}, "local": {"workspace:*"}},
+			wantInstalled: map[string]string{"node_modules/@babel/core": "7.22.5"},
+		},
+		{
+			name: "pnpm v5",
+			file: "pnpm-lock.yaml",
+			content: `lockfileVersion: 5.4
+
+specifiers:
+  lodash: ^4.17.0
+
+devDependencies:
+  lodash: 4.17.21_typescript@5.0.0
+`,
+			wantSpecs:     map[string][]string{"lodash": {"^4.17.0"}},
+			wantInstalled: map[string]string{"node_modules/lodash": "4.17.21"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			os.WriteFile(filepath.Join(tmpDir, tt.file), []byte(tt.content), 0644)
+			pm := detector.DetectNodePackageManager(tmpDir)
+
+			lock, err := readNodeLock(tmpDir, pm, deps)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(lock.Specs, tt.wantSpecs) {
+				t.Errorf("Specs = %v, want %v", lock.Specs, tt.wantSpecs)
+			}
+			if !reflect.DeepEqual(lock.Installed, tt.wantInstalled) {
+				t.Errorf("Installed = %v, want %v", lock.Installed, tt.wantInstalled)
+			}
+		})
+	}
+}
+
+func TestCheckNodeLockfile(t *testing.T) {
+	tmpDir := t.TempDir()
+	os.WriteFile(filepath.Join(tmpDir, "package.json"), []byte(`{
+		"dependencies": {"a": "^1.0.0", "b": "^2.0.0", "c": "^1.0.0", "local": "file:./local"},
+		"devDependencies": {"new-dep": "^3.0.0"}
+	}`), 0644)
+	os.WriteFile(filepath.Join(tmpDir, "package-lock.json"), []byte(`{"lockfileVersion": 3, "packages": {
+		"": {"dependencies": {"a": "^1.0.0", "b": "^2.0.0", "c": "^0.9.0"}},
+		"node_modules/a": {"version": "1.2.0"},
+		"node_modules/b": {"version": "2.0.0"},
+		"node_modules/fsevents": {"version": "2.3.3", "optional": true}
+	}}`), 0644)
+	for name, version := range map[string]string{"a": "1.1.0"} {
+		dir := filepath.Join(tmpDir, "node_modules", name)
+		os.MkdirAll(dir, 0755)
+		os.WriteFile(filepath.Join(dir, "package.json"), []byte(`{"version": "`+version+`"}`), 0644)
+	}
+
+	issues := checkNodeJS(tmpDir)
+	want := map[string]bool{
+		"2 dependencies in package.json are missing from package-lock.json: c@^1.0.0, new-dep@^3.0.0": false,
+		"1 packages in package-lock.json are not installed: b":                                        false,
+		"1 packages in node_modules differ from package-lock.json: a (installed 1.1.0, locked 1.2.0)": false,
+	}
+	for _, issue := range issues {
+		if _, ok := want[issue.Message]; ok {
+			want[issue.Message] = true
+		} else if strings.Contains(issue.Message, "fsevents") {
+			t.Errorf("Unexpected issue for optional package: %s", issue.Message)
+		}
+	}
+	for message, found := range want {
+		if !found {
+			t.Errorf("Expected issue %q, got %v", message, issues)
+		}
+	}
+}
+
+func TestCheckNodeLockfileYarnAlias(t *testing.T) {
+	tmpDir := t.TempDir()
+	files := map[string]string{
+		"package.json": `{"dependencies": {"string-width-cjs": "npm:string-width@^4.2.0", "@scope/ui": "^1.0.0"}}`,
+		"yarn.lock":    "__metadata:\n  version: 6\n\n\"string-width-cjs@npm:string-width@^4.2.0\":\n  version: 4.2.3\n\n\"@scope/ui@npm:^1.0.0\":\n  version: 1.0.0\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, issue := range checkNodeJS(tmpDir) {
+		if strings.Contains(issue.Message, "missing from yarn.lock") {
+			t.Errorf("Unexpected issue for an aliased dependency: %s", issue.Message)
+		}
+	}
+}
+
+func TestCheckNodeLockfileYarnPnP(t *testing.T) {
+	tmpDir := t.TempDir()
+	os.WriteFile(filepath.Join(tmpDir, "package.json"), []byte(`{"dependencies": {"a": "^1.0.0"}}`), 0644)
+	os.WriteFile(filepath.Join(tmpDir, "yarn.lock"), []byte("__metadata:\n  version: 6\n\n\"a@npm:^1.0.0\":\n  version: 1.0.0\n"), 0644)
+	os.WriteFile(filepath.Join(tmpDir, ".pnp.cjs"), []byte(""), 0644)
+
+	if issues := checkNodeJS(tmpDir); len(issues) != 0 {
+		t.Errorf("Expected no issues for a Plug'n'Play install, got %v", issues)
+	}
+}
@@ -0,0 +1,217 @@
+package checker
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/Sw3bbl3/devdoctor/internal/semver"
+)
+
+// composerJSON holds the parts of composer.json the checks need
+type composerJSON struct {
+	Require    map[string]string `json:"require"`
+	RequireDev map[string]string `json:"require-dev"`
+}
+
+// composerPackage is a package of composer.lock or vendor/composer/installed.json
+type composerPackage struct {
+	Name    string            `json:"name"`
+	Version string            `json:"version"`
+	Require map[string]string `json:"require"`
+}
+
+// composerLock holds the parts of composer.lock the checks need
+type composerLock struct {
+	Packages    []composerPackage `json:"packages"`
+	PackagesDev []composerPackage `json:"packages-dev"`
+}
+
+// isComposerPlatformPackage reports whether a requirement names the platform
+// (php, ext-*, lib-*, composer APIs) rather than a package
+func isComposerPlatformPackage(name string) bool {
+	switch {
+	case name == "php" || strings.HasPrefix(name, "php-"):
+		return true
+	case strings.HasPrefix(name, "ext-") || strings.HasPrefix(name, "lib-"):
+		return true
+	case name == "composer" || name == "composer-plugin-api" || name == "composer-runtime-api":
+		return true
+	}
+	return false
+}
+
+// readComposerInstalled returns the versions of the packages installed in
+// vendor/, from the installed.json of Composer 2 or Composer 1
+func readComposerInstalled(path string) (map[string]string, bool) {
+	data, err := os.ReadFile(filepath.Join(path, "vendor", "composer", "installed.json"))
+	if err != nil {
+		return nil, false
+	}
+	var v2 struct {
+		Packages []composerPackage `json:"packages"`
+	}
+	var packages []composerPackage
+	if err := json.Unmarshal(data, &v2); err == nil {
+		packages = v2.Packages
+	} else if err := json.Unmarshal(data, &packages); err != nil {
+		return nil, false
+	}
+	installed := map[string]string{}
+	for _, pkg := range packages {
+		installed[strings.ToLower(pkg.Name)] = pkg.Version
+	}
+	return installed, true
+}
+
+// composerSatisfies evaluates a Composer constraint such as "^8.1",
+// "~7.4 || ^8.0" or ">=8.1,<8.4" against a version. Composer's ~ keeps all
+// but the last given segment, like RubyGems' ~>.
+func composerSatisfies(version, constraint string) (bool, error) {
+	alternatives := []string{}
+	for _, alt := range strings.Split(strings.ReplaceAll(constraint, "||", "|"), "|") {
+		parts := []string{}
+		for _, part := range strings.FieldsFunc(alt, func(r rune) bool { return r == ',' || r == ' ' }) {
+			part, _, _ = strings.Cut(part, "@") // stability flags
+			if rest, ok := strings.CutPrefix(part, "~"); ok && !strings.HasPrefix(rest, ">") {
+				part = fmt.Sprintf(">=%s <%s", rest, gemBump(rest))
+			}
+			parts = append(parts, part)
+		}
+		alternatives = append(alternatives, strings.Join(parts, " "))
+	}
+	return semver.Satisfies(version, strings.Join(alternatives, " || "))
+}
+
+// parsePHPModules parses the output of 'php -m' into Composer's extension
+// names: lower case with spaces replaced by dashes ("Zend OPcache" is
+// ext-zend-opcache)
+func parsePHPModules(out string) map[string]bool {
+	modules := map[string]bool{}
+	for _, line := range strings.Split(out, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "[") {
+			continue
+		}
+		modules[strings.ReplaceAll(strings.ToLower(line), " ", "-")] = true
+	}
+	return modules
+}
+
+// loadedPHPModules returns the extensions the project's php CLI loads, or
+// nil if php cannot be run
+func loadedPHPModules(path string) map[string]bool {
+	cmd := exec.Command("php", "-m")
+	cmd.Dir = path
+	out, err := cmd.Output()
+	if err != nil {
+		return nil
+	}
+	return parsePHPModules(string(out))
+}
+
+// checkComposer compares composer.json, composer.lock and vendor/ with each
+// other, and the PHP version and extensions they require with the php CLI.
+// An empty phpVersion or nil modules skips the platform checks.
+func checkComposer(path string, lock *composerLock, phpVersion string, modules map[string]bool) []Issue {
+	issues := []Issue{}
+	var manifest composerJSON
+	if data, err := os.ReadFile(filepath.Join(path, "composer.json")); err == nil {
+		json.Unmarshal(data, &manifest)
+	}
+
+	// ext-* requirements of the project and of every locked package
+	extensions := map[string][]string{}
+	requireExtensions := func(require map[string]string, source string) {
+		for name := range require {
+			if ext, ok := strings.CutPrefix(strings.ToLower(name), "ext-"); ok && !containsString(extensions[ext], source) {
+				extensions[ext] = append(extensions[ext], source)
+			}
+		}
+	}
+	requireExtensions(manifest.Require, "composer.json")
+	requireExtensions(manifest.RequireDev, "composer.json")
+
+	if lock != nil {
+		locked := map[string]bool{}
+		for _, pkg := range append(lock.Packages, lock.PackagesDev...) {
+			locked[strings.ToLower(pkg.Name)] = true
+			requireExtensions(pkg.Require, pkg.Name)
+		}
+
+		// composer install refuses a lock that misses a requirement
+		unlocked := []string{}
+		for _, require := range []map[string]string{manifest.Require, manifest.RequireDev} {
+			for name := range require {
+				if !isComposerPlatformPackage(strings.ToLower(name)) && !locked[strings.ToLower(name)] {
+					unlocked = append(unlocked, name)
+				}
+			}
+		}
+		sort.Strings(unlocked)
+		if len(unlocked) > 0 {
+			issues = append(issues, Issue{
+				Severity:    SeverityWarning,
+				ProjectType: "PHP",
+				Message:     fmt.Sprintf("composer.lock is out of date: %d packages from composer.json are not locked: %s", len(unlocked), strings.Join(unlocked, ", ")),
+				Suggestion:  fmt.Sprintf("Run 'composer update %s' to lock them", strings.Join(unlocked, " ")),
+			})
+		}
+
+		if installed, ok := readComposerInstalled(path); ok {
+			missing := []string{}
+			for _, pkg := range append(lock.Packages, lock.PackagesDev...) {
+				version, found := installed[strings.ToLower(pkg.Name)]
+				switch {
+				case !found:
+					missing = append(missing, pkg.Name)
+				case version != pkg.Version:
+					missing = append(missing, fmt.Sprintf("%s (%s installed, %s locked)", pkg.Name, version, pkg.Version))
+				}
+			}
+			if len(missing) > 0 {
+				issues = append(issues, Issue{
+					Severity:    SeverityError,
+					ProjectType: "PHP",
+					Message:     fmt.Sprintf("%d packages from composer.lock are not installed in vendor/: %s", len(missing), strings.Join(missing, ", ")),
+					Suggestion:  "Run 'composer install' to install the locked versions",
+				})
+			}
+		}
+	}
+
+	if constraint := manifest.Require["php"]; constraint != "" && phpVersion != "" {
+		if ok, err := composerSatisfies(phpVersion, constraint); err == nil && !ok {
+			issues = append(issues, Issue{
+				Severity:    SeverityError,
+				ProjectType: "PHP",
+				Message:     fmt.Sprintf("composer.json requires PHP %s but %s is installed", constraint, phpVersion),
+				Suggestion:  "Install a matching PHP version, e.g. with your package manager or phpbrew",
+			})
+		}
+	}
+
+	if modules != nil {
+		missing := []string{}
+		for ext, sources := range extensions {
+			if !modules[ext] {
+				missing = append(missing, fmt.Sprintf("ext-%s (%s)", ext, strings.Join(sources, ", ")))
+			}
+		}
+		sort.Strings(missing)
+		if len(missing) > 0 {
+			issues = append(issues, Issue{
+				Severity:    SeverityError,
+				ProjectType: "PHP",
+				Message:     fmt.Sprintf("%d PHP extensions required by Composer packages are not loaded: %s", len(missing), strings.Join(missing, ", ")),
+				Suggestion:  "Install and enable them (e.g. 'sudo apt install php-<name>' or an extension= line in php.ini); 'php -m' lists the loaded ones",
+			})
+		}
+	}
+
+	return issues
+}
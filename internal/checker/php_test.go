@@ -0,0 +1,93 @@
+package checker
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestComposerSatisfies(t *testing.T) {
+	tests := []struct {
+		version    string
+		constraint string
+		want       bool
+	}{
+		{"8.2.12", "^8.1", true},
+		{"7.4.33", "^8.1", false},
+		{"8.3.0", "~8.1", true},
+		{"9.0.0", "~8.1", false},
+		{"8.1.5", "~8.1.0", true},
+		{"8.2.0", "~8.1.0", false},
+		{"7.4.0", "^7.4 || ^8.0", true},
+		{"7.4.0", "^7.4|^8.0", true},
+		{"8.3.1", ">=8.1,<8.3", false},
+		{"8.2.9", ">=8.1 <8.3", true},
+		{"8.2.9", "8.2.*", true},
+		{"8.2.9", ">=8.1@dev", true},
+	}
+	for _, tt := range tests {
+		got, err := composerSatisfies(tt.version, tt.constraint)
+		if err != nil {
+			t.Errorf("composerSatisfies(%q, %q) error: %v", tt.version, tt.constraint, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("composerSatisfies(%q, %q) = %v, want %v", tt.version, tt.constraint, got, tt.want)
+		}
+	}
+}
+
+func TestParsePHPModules(t *testing.T) {
+	modules := parsePHPModules("[PHP Modules]\nCore\nctype\nPDO\npdo_mysql\nZend OPcache\n\n[Zend Modules]\nZend OPcache\n")
+	for _, ext := range []string{"core", "ctype", "pdo", "pdo_mysql", "zend-opcache"} {
+		if !modules[ext] {
+			t.Errorf("parsePHPModules() is missing %q: %v", ext, modules)
+		}
+	}
+	if len(modules) != 5 {
+		t.Errorf("parsePHPModules() = %v, want 5 modules", modules)
+	}
+}
+
+func TestCheckComposer(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "composer.json"), []byte(`{
+  "require": {"php": "^8.2", "ext-intl": "*", "ext-json": "*", "monolog/monolog": "^3.0", "guzzlehttp/guzzle": "^7.8"},
+  "require-dev": {"phpunit/phpunit": "^10.5"}
+}`), 0644)
+	os.MkdirAll(filepath.Join(dir, "vendor", "composer"), 0755)
+	os.WriteFile(filepath.Join(dir, "vendor", "composer", "installed.json"), []byte(`{"packages": [
+  {"name": "monolog/monolog", "version": "3.4.0"},
+  {"name": "phpunit/phpunit", "version": "10.5.1"}
+]}`), 0644)
+	lock := &composerLock{
+		Packages: []composerPackage{
+			{Name: "monolog/monolog", Version: "3.5.0", Require: map[string]string{"php": ">=8.1", "ext-json": "*"}},
+			{Name: "symfony/polyfill-mbstring", Version: "v1.28.0", Require: map[string]string{"ext-mbstring": "*"}},
+		},
+		PackagesDev: []composerPackage{{Name: "phpunit/phpunit", Version: "10.5.1", Require: map[string]string{"ext-dom": "*"}}},
+	}
+	modules := map[string]bool{"core": true, "json": true, "dom": true}
+
+	got := []string{}
+	for _, issue := range checkComposer(dir, lock, "8.1.27", modules) {
+		got = append(got, string(issue.Severity)+" "+issue.Message)
+	}
+	want := []string{
+		"WARNING composer.lock is out of date: 1 packages from composer.json are not locked: guzzlehttp/guzzle",
+		"ERROR 2 packages from composer.lock are not installed in vendor/: monolog/monolog (3.4.0 installed, 3.5.0 locked), symfony/polyfill-mbstring",
+		"ERROR composer.json requires PHP ^8.2 but 8.1.27 is installed",
+		"ERROR 2 PHP extensions required by Composer packages are not loaded: ext-intl (composer.json), ext-mbstring (symfony/polyfill-mbstring)",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("checkComposer():\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	// Without php the platform checks are skipped
+	for _, issue := range checkComposer(dir, lock, "", nil) {
+		if strings.Contains(issue.Message, "PHP") {
+			t.Errorf("checkComposer() without php reported %q", issue.Message)
+		}
+	}
+}
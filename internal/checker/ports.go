@@ -0,0 +1,320 @@
+package checker
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Sw3bbl3/devdoctor/internal/dotenv"
+	"github.com/Sw3bbl3/devdoctor/internal/yaml"
+)
+
+// expectedPort is a TCP port the project binds on the host, with the places
+// that configure it
+type expectedPort struct {
+	Port    int
+	Sources []string
+}
+
+// portCollector gathers the ports of a project, merging duplicates
+type portCollector struct {
+	ports map[int]*expectedPort
+}
+
+func (pc *portCollector) add(value, source string) {
+	port, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || port <= 0 || port > 65535 {
+		return
+	}
+	if pc.ports[port] == nil {
+		pc.ports[port] = &expectedPort{Port: port}
+	}
+	if !containsString(pc.ports[port].Sources, source) {
+		pc.ports[port].Sources = append(pc.ports[port].Sources, source)
+	}
+}
+
+// clientPortPrefixes are .env key prefixes of services the project connects
+// to rather than ports it listens on (DB_PORT, REDIS_PORT, ...)
+var clientPortPrefixes = []string{
+	"DB", "DATABASE", "POSTGRES", "PG", "MYSQL", "MARIADB", "MONGO", "MONGODB", "REDIS",
+	"RABBITMQ", "AMQP", "KAFKA", "ELASTIC", "ELASTICSEARCH", "MEMCACHED", "SMTP", "MAIL", "CLICKHOUSE",
+}
+
+var (
+	scriptPortRe = regexp.MustCompile(`(?:--port[= ]|\bPORT=)(\d+)\b`)
+	springPortRe = regexp.MustCompile(`^\$\{[^:}]+:(\d+)\}$`)
+)
+
+// collectPorts returns the ports the project in path expects to bind: the
+// published ports of its compose files, PORT style keys in .env, --port
+// flags and PORT= prefixes in package.json scripts and Spring's server.port
+func collectPorts(path string) []expectedPort {
+	pc := &portCollector{ports: map[int]*expectedPort{}}
+
+	env := composeEnv(path)
+	for _, file := range composeFiles(path, env) {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
+		doc, err := yaml.Decode(data)
+		if err != nil {
+			continue
+		}
+		services := yaml.Map(yaml.Lookup(doc, "services"))
+		for _, name := range yaml.Keys(services) {
+			source := fmt.Sprintf("%s service '%s'", displayPath(path, file), name)
+			for _, p := range yaml.List(yaml.Lookup(services[name], "ports")) {
+				for _, port := range composePublishedPorts(p, env) {
+					pc.add(port, source)
+				}
+			}
+		}
+	}
+
+	if data, err := os.ReadFile(filepath.Join(path, ".env")); err == nil {
+		for _, entry := range dotenv.Parse(data) {
+			key := strings.ToUpper(entry.Key)
+			if key != "PORT" && !strings.HasSuffix(key, "_PORT") {
+				continue
+			}
+			if prefix := strings.Split(key, "_")[0]; key != "PORT" && containsString(clientPortPrefixes, prefix) {
+				continue
+			}
+			pc.add(entry.Value, fmt.Sprintf("%s in .env", entry.Key))
+		}
+	}
+
+	if data, err := os.ReadFile(filepath.Join(path, "package.json")); err == nil {
+		var pkg struct {
+			Scripts map[string]string `json:"scripts"`
+		}
+		if json.Unmarshal(data, &pkg) == nil {
+			for name, script := range pkg.Scripts {
+				for _, m := range scriptPortRe.FindAllStringSubmatch(script, -1) {
+					pc.add(m[1], fmt.Sprintf("package.json script '%s'", name))
+				}
+			}
+		}
+	}
+
+	resources := filepath.Join(path, "src", "main", "resources")
+	if value := springServerPort(resources); value != "" {
+		if m := springPortRe.FindStringSubmatch(value); m != nil {
+			value = m[1] // ${PORT:8081} defaults to 8081
+		}
+		pc.add(value, "server.port in "+displayPath(path, resources))
+	}
+
+	ports := []expectedPort{}
+	for _, p := range pc.ports {
+		sort.Strings(p.Sources)
+		ports = append(ports, *p)
+	}
+	sort.Slice(ports, func(i, j int) bool { return ports[i].Port < ports[j].Port })
+	return ports
+}
+
+// composePublishedPorts returns the host ports of a compose ports entry:
+// "8080:80", "127.0.0.1:8080:80", "3000-3001:3000-3001" or the long syntax
+// with published. Container-only and UDP ports are skipped.
+func composePublishedPorts(entry interface{}, env map[string]string) []string {
+	published := ""
+	if m := yaml.Map(entry); m != nil {
+		if yaml.String(m["protocol"]) == "udp" {
+			return nil
+		}
+		published = yaml.String(m["published"])
+	} else {
+		spec, _, _ := interpolate(yaml.String(entry), env)
+		if strings.HasSuffix(spec, "/udp") {
+			return nil
+		}
+		spec = strings.TrimSuffix(spec, "/tcp")
+		parts := strings.Split(spec, ":")
+		if len(parts) < 2 {
+			return nil // the engine picks a free host port
+		}
+		published = parts[len(parts)-2]
+	}
+	published, _, _ = interpolate(published, env)
+
+	start, end, isRange := strings.Cut(published, "-")
+	if !isRange {
+		return []string{published}
+	}
+	from, err1 := strconv.Atoi(start)
+	to, err2 := strconv.Atoi(end)
+	if err1 != nil || err2 != nil || to < from || to-from > 100 {
+		return nil
+	}
+	ports := []string{}
+	for p := from; p <= to; p++ {
+		ports = append(ports, strconv.Itoa(p))
+	}
+	return ports
+}
+
+// springServerPort returns server.port from application.properties or
+// application.yml in a Spring resources directory
+func springServerPort(resources string) string {
+	if data, err := os.ReadFile(filepath.Join(resources, "application.properties")); err == nil {
+		for _, line := range strings.Split(string(data), "\n") {
+			key, value, ok := strings.Cut(line, "=")
+			if !ok {
+				key, value, ok = strings.Cut(line, ":")
+			}
+			if ok && strings.TrimSpace(key) == "server.port" {
+				return strings.TrimSpace(value)
+			}
+		}
+	}
+	for _, name := range []string{"application.yml", "application.yaml"} {
+		data, err := os.ReadFile(filepath.Join(resources, name))
+		if err != nil {
+			continue
+		}
+		if doc, err := yaml.Decode(data); err == nil {
+			if port := yaml.String(yaml.Lookup(doc, "server", "port")); port != "" {
+				return port
+			}
+			if port := yaml.String(yaml.Lookup(doc, "server.port")); port != "" {
+				return port
+			}
+		}
+	}
+	return ""
+}
+
+// parseProcNetTCP returns the socket inodes of the listening sockets in a
+// /proc/net/tcp or /proc/net/tcp6 table, by port
+func parseProcNetTCP(data string) map[int]string {
+	listening := map[int]string{}
+	scanner := bufio.NewScanner(strings.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		// sl local_address rem_address st tx_queue:rx_queue tr:tm->when retrnsmt uid timeout inode
+		if len(fields) < 10 || fields[3] != "0A" {
+			continue
+		}
+		_, hexPort, ok := strings.Cut(fields[1], ":")
+		if !ok {
+			continue
+		}
+		if port, err := strconv.ParseInt(hexPort, 16, 32); err == nil {
+			listening[int(port)] = fields[9]
+		}
+	}
+	return listening
+}
+
+// socketOwners maps socket inodes to the processes holding them. Only the
+// processes of the current user (or all, as root) can be inspected.
+func socketOwners(procDir string, inodes map[string]bool) map[string]string {
+	owners := map[string]string{}
+	pids, _ := os.ReadDir(procDir)
+	for _, pid := range pids {
+		if _, err := strconv.Atoi(pid.Name()); err != nil {
+			continue
+		}
+		fds, err := os.ReadDir(filepath.Join(procDir, pid.Name(), "fd"))
+		if err != nil {
+			continue
+		}
+		for _, fd := range fds {
+			link, err := os.Readlink(filepath.Join(procDir, pid.Name(), "fd", fd.Name()))
+			if err != nil || !strings.HasPrefix(link, "socket:[") {
+				continue
+			}
+			inode := strings.TrimSuffix(strings.TrimPrefix(link, "socket:["), "]")
+			if inodes[inode] && owners[inode] == "" {
+				comm, _ := os.ReadFile(filepath.Join(procDir, pid.Name(), "comm"))
+				owners[inode] = fmt.Sprintf("%s (pid %s)", strings.TrimSpace(string(comm)), pid.Name())
+			}
+		}
+	}
+	return owners
+}
+
+// portsInUse returns the ports among ports that are taken on localhost,
+// with the process holding each when it is known. On Linux the socket
+// tables in /proc are read; elsewhere each port is probed by binding it.
+func portsInUse(ports []expectedPort) map[int]string {
+	inUse := map[int]string{}
+
+	listening := map[int]string{}
+	readProc := false
+	for _, table := range []string{"/proc/net/tcp", "/proc/net/tcp6"} {
+		if data, err := os.ReadFile(table); err == nil {
+			readProc = true
+			for port, inode := range parseProcNetTCP(string(data)) {
+				listening[port] = inode
+			}
+		}
+	}
+
+	if readProc {
+		inodes := map[string]bool{}
+		for _, p := range ports {
+			if inode, ok := listening[p.Port]; ok {
+				inodes[inode] = true
+			}
+		}
+		owners := socketOwners("/proc", inodes)
+		for _, p := range ports {
+			if inode, ok := listening[p.Port]; ok {
+				inUse[p.Port] = owners[inode]
+			}
+		}
+		return inUse
+	}
+
+	for _, p := range ports {
+		l, err := net.Listen("tcp", fmt.Sprintf(":%d", p.Port))
+		if err != nil {
+			inUse[p.Port] = ""
+			continue
+		}
+		l.Close()
+	}
+	return inUse
+}
+
+// checkPortConflicts reports the ports the project expects to bind that
+// another process already listens on
+func checkPortConflicts(path string) []Issue {
+	issues := []Issue{}
+	ports := collectPorts(path)
+	if len(ports) == 0 {
+		return issues
+	}
+	inUse := portsInUse(ports)
+
+	for _, p := range ports {
+		owner, taken := inUse[p.Port]
+		if !taken {
+			continue
+		}
+		message := fmt.Sprintf("Port %d (%s) is already in use", p.Port, strings.Join(p.Sources, ", "))
+		suggestion := fmt.Sprintf("Stop the process listening on port %d or configure a different port", p.Port)
+		if owner != "" {
+			message += " by " + owner
+			suggestion = fmt.Sprintf("Stop %s or configure a different port", owner)
+		}
+		issues = append(issues, Issue{
+			Severity:    SeverityWarning,
+			ProjectType: "General",
+			Message:     message,
+			Suggestion:  suggestion,
+		})
+	}
+	return issues
+}
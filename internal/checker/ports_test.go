@@ -0,0 +1,91 @@
+package checker
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestCollectPorts(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, ".env"), []byte("PORT=3000\nADMIN_PORT=9000\nDB_PORT=5432\nWEB_PORT=8080\n"), 0644)
+	os.WriteFile(filepath.Join(dir, "compose.yaml"), []byte(`services:
+  web:
+    ports:
+      - "${WEB_PORT}:80"
+      - "127.0.0.1:9229:9229"
+      - "5000-5001:5000-5001"
+      - "53:53/udp"
+      - "4000"
+  api:
+    ports:
+      - target: 3000
+        published: "3000"
+`), 0644)
+	os.WriteFile(filepath.Join(dir, "package.json"), []byte(`{"scripts": {
+  "dev": "vite --port 5173",
+  "start": "PORT=3000 node server.js",
+  "storybook": "storybook dev --port=6006"
+}}`), 0644)
+	resources := filepath.Join(dir, "src", "main", "resources")
+	os.MkdirAll(resources, 0755)
+	os.WriteFile(filepath.Join(resources, "application.yml"), []byte("server:\n  port: ${SERVER_PORT:8081}\n"), 0644)
+
+	got := []string{}
+	for _, p := range collectPorts(dir) {
+		got = append(got, fmt.Sprintf("%d: %s", p.Port, strings.Join(p.Sources, "; ")))
+	}
+	want := []string{
+		"3000: PORT in .env; compose.yaml service 'api'; package.json script 'start'",
+		"5000: compose.yaml service 'web'",
+		"5001: compose.yaml service 'web'",
+		"5173: package.json script 'dev'",
+		"6006: package.json script 'storybook'",
+		"8080: WEB_PORT in .env; compose.yaml service 'web'",
+		"8081: server.port in " + filepath.Join("src", "main", "resources"),
+		"9000: ADMIN_PORT in .env",
+		"9229: compose.yaml service 'web'",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("collectPorts():\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestParseProcNetTCP(t *testing.T) {
+	data := `  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode
+   0: 0100007F:1F90 00000000:0000 0A 00000000:00000000 00:00000000 00000000  1000        0 4242 1 0000000000000000 100 0 0 10 0
+   1: 0100007F:E3F2 0100007F:1F90 01 00000000:00000000 02:0000111B 00000000  1000        0 21612 2 0000000000000000 20 4 0 16 8
+`
+	listening := parseProcNetTCP(data)
+	if len(listening) != 1 || listening[8080] != "4242" {
+		t.Errorf("parseProcNetTCP() = %v, want port 8080 with inode 4242", listening)
+	}
+}
+
+func TestCheckPortConflicts(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skip("cannot listen on localhost:", err)
+	}
+	defer l.Close()
+	port := l.Addr().(*net.TCPAddr).Port
+
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, ".env"), []byte(fmt.Sprintf("PORT=%d\n", port)), 0644)
+
+	issues := checkPortConflicts(dir)
+	if len(issues) != 1 {
+		t.Fatalf("checkPortConflicts() returned %d issues, want 1: %v", len(issues), issues)
+	}
+	prefix := fmt.Sprintf("Port %d (PORT in .env) is already in use", port)
+	if !strings.HasPrefix(issues[0].Message, prefix) {
+		t.Errorf("message = %q, want prefix %q", issues[0].Message, prefix)
+	}
+	if runtime.GOOS == "linux" && !strings.Contains(issues[0].Message, fmt.Sprintf("(pid %d)", os.Getpid())) {
+		t.Errorf("message = %q, want the test process as the owner", issues[0].Message)
+	}
+}
@@ -0,0 +1,364 @@
+package checker
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+
+	"github.com/Sw3bbl3/devdoctor/internal/detector"
+	"github.com/Sw3bbl3/devdoctor/internal/pep440"
+	"github.com/Sw3bbl3/devdoctor/internal/semver"
+	"github.com/Sw3bbl3/devdoctor/internal/toml"
+)
+
+var (
+	// Characters replaced by "_" when Poetry and Pipenv derive venv names
+	poetryNameRe = regexp.MustCompile("[ $`!*@\"\\\\\r\n\t]")
+	pipenvNameRe = regexp.MustCompile("[ &$`!*@\"()\\[\\]\\\\\r\n\t]")
+)
+
+// localVenvDirs are the in-project virtualenv locations. ".env" is not one
+// of them: it is almost always a dotenv file.
+var localVenvDirs = []string{".venv", "venv", "env"}
+
+// pythonEnvSetup returns the command that creates the environment for tc
+func pythonEnvSetup(tc detector.PythonToolchain) string {
+	switch tc.Manager {
+	case "poetry":
+		return "poetry install"
+	case "pipenv":
+		return "pipenv install --dev"
+	case "uv":
+		return "uv sync"
+	case "hatch":
+		return "hatch env create"
+	case "conda":
+		return fmt.Sprintf("conda env create -f %s", tc.Source)
+	}
+	return "python -m venv venv"
+}
+
+// findPythonEnv returns the directory of the environment the toolchain uses
+// for the project at path, or "" if it does not exist
+func findPythonEnv(path string, tc detector.PythonToolchain) string {
+	switch tc.Manager {
+	case "poetry":
+		if env := findLocalVenv(path, ".venv"); env != "" {
+			return env
+		}
+		name := pyprojectName(path)
+		if name == "" {
+			return ""
+		}
+		dir := os.Getenv("POETRY_VIRTUALENVS_PATH")
+		if dir == "" {
+			dir = filepath.Join(poetryCacheDir(), "virtualenvs")
+		}
+		// {name}-{hash}-py{major.minor}
+		prefix := poetryNameRe.ReplaceAllString(strings.ToLower(name), "_")
+		if len(prefix) > 42 {
+			prefix = prefix[:42]
+		}
+		return firstVenvMatch(filepath.Join(dir, prefix+"-*-py*"))
+	case "pipenv":
+		if env := findLocalVenv(path, ".venv"); env != "" {
+			return env
+		}
+		dir := os.Getenv("WORKON_HOME")
+		if dir == "" {
+			dir = filepath.Join(userDataDir(), "virtualenvs")
+		}
+		abs, err := filepath.Abs(path)
+		if err != nil {
+			return ""
+		}
+		// {dirname}-{hash}
+		prefix := pipenvNameRe.ReplaceAllString(filepath.Base(abs), "_")
+		if len(prefix) > 42 {
+			prefix = prefix[:42]
+		}
+		return firstVenvMatch(filepath.Join(dir, prefix+"-*"))
+	case "uv":
+		if env := os.Getenv("UV_PROJECT_ENVIRONMENT"); env != "" {
+			if !filepath.IsAbs(env) {
+				env = filepath.Join(path, env)
+			}
+			return findLocalVenv(filepath.Dir(env), filepath.Base(env))
+		}
+		return findLocalVenv(path, ".venv")
+	case "hatch":
+		if env := findLocalVenv(path, ".venv"); env != "" {
+			return env
+		}
+		name := pyprojectName(path)
+		if name == "" {
+			return ""
+		}
+		dir := os.Getenv("HATCH_DATA_DIR")
+		if dir == "" {
+			dir = filepath.Join(userDataDir(), "hatch")
+		}
+		envDir := filepath.Join(dir, "env", "virtual", name)
+		if _, err := os.Stat(envDir); err == nil {
+			return envDir
+		}
+		return ""
+	case "conda":
+		return findCondaEnv(tc.CondaEnv)
+	}
+	return findLocalVenv(path, localVenvDirs...)
+}
+
+// checkPythonEnv verifies that the environment's interpreter still works and
+// that its Python version satisfies the project's requirement
+func checkPythonEnv(path string, tc detector.PythonToolchain, env string) []Issue {
+	issues := []Issue{}
+	name := displayPath(path, env)
+	recreate := fmt.Sprintf("Delete %s and recreate it with '%s'", name, pythonEnvSetup(tc))
+	if tc.Manager == "pip" {
+		recreate = fmt.Sprintf("Delete %s and recreate it with 'python -m venv %s'", name, name)
+	}
+
+	interpreter := pythonInterpreter(env)
+	if _, err := os.Lstat(interpreter); err != nil {
+		issues = append(issues, Issue{
+			Severity:    SeverityError,
+			ProjectType: "Python",
+			Message:     fmt.Sprintf("Python interpreter not found in environment %s", name),
+			Suggestion:  recreate,
+		})
+	} else if _, err := os.Stat(interpreter); err != nil {
+		// The venv links to a Python that was upgraded or removed
+		target, _ := os.Readlink(interpreter)
+		issues = append(issues, Issue{
+			Severity:    SeverityError,
+			ProjectType: "Python",
+			Message:     fmt.Sprintf("Environment %s links to a Python interpreter that no longer exists (%s)", name, target),
+			Suggestion:  recreate,
+		})
+	}
+
+	version := pythonEnvVersion(env)
+	constraint, source := requiredPython(path)
+	if version == "" || constraint == "" {
+		return issues
+	}
+	ok, err := pythonSatisfies(version, constraint)
+	if err != nil {
+		issues = append(issues, Issue{
+			Severity:    SeverityWarning,
+			ProjectType: "Python",
+			Message:     fmt.Sprintf("Cannot evaluate %s '%s': %v", source, constraint, err),
+			Suggestion:  "Use a PEP 440 version specifier such as '>=3.9'",
+		})
+	} else if !ok {
+		issues = append(issues, Issue{
+			Severity:    SeverityError,
+			ProjectType: "Python",
+			Message:     fmt.Sprintf("Environment %s uses Python %s but %s is '%s'", name, version, source, constraint),
+			Suggestion:  recreate + " using a matching Python version",
+		})
+	}
+
+	return issues
+}
+
+// pythonInterpreter returns the path of the interpreter inside env
+func pythonInterpreter(env string) string {
+	if runtime.GOOS != "windows" {
+		return filepath.Join(env, "bin", "python")
+	}
+	// Conda environments keep python.exe at the top level
+	if _, err := os.Stat(filepath.Join(env, "conda-meta")); err == nil {
+		return filepath.Join(env, "python.exe")
+	}
+	return filepath.Join(env, "Scripts", "python.exe")
+}
+
+var pythonVersionRe = regexp.MustCompile(`^\d+(\.\d+){0,2}`)
+
+// pythonEnvVersion returns the Python version of an environment from
+// pyvenv.cfg, or from conda-meta for Conda environments
+func pythonEnvVersion(env string) string {
+	if data, err := os.ReadFile(filepath.Join(env, "pyvenv.cfg")); err == nil {
+		values := map[string]string{}
+		for _, line := range strings.Split(string(data), "\n") {
+			if key, value, ok := strings.Cut(line, "="); ok {
+				values[strings.TrimSpace(key)] = strings.TrimSpace(value)
+			}
+		}
+		// virtualenv and uv write version_info, the venv module version
+		for _, key := range []string{"version_info", "version"} {
+			if v := pythonVersionRe.FindString(values[key]); v != "" {
+				return v
+			}
+		}
+	}
+
+	matches, _ := filepath.Glob(filepath.Join(env, "conda-meta", "python-[0-9]*.json"))
+	for _, match := range matches {
+		if v := pythonVersionRe.FindString(strings.TrimPrefix(filepath.Base(match), "python-")); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// requiredPython returns the Python constraint declared in pyproject.toml
+// and the setting it came from
+func requiredPython(path string) (string, string) {
+	data, err := os.ReadFile(filepath.Join(path, "pyproject.toml"))
+	if err != nil {
+		return "", ""
+	}
+	doc, err := toml.Decode(data)
+	if err != nil {
+		return "", ""
+	}
+	if constraint := toml.String(doc, "project", "requires-python"); constraint != "" {
+		return constraint, "requires-python"
+	}
+	if constraint := toml.String(doc, "tool", "poetry", "dependencies", "python"); constraint != "" {
+		return constraint, "tool.poetry.dependencies.python"
+	}
+	return "", ""
+}
+
+// pythonSatisfies evaluates a PEP 440 specifier, falling back to the
+// caret and tilde syntax Poetry also accepts
+func pythonSatisfies(version, constraint string) (bool, error) {
+	ok, err := pep440.Satisfies(version, constraint)
+	if err == nil {
+		return ok, nil
+	}
+	if ok, semverErr := semver.Satisfies(version, strings.ReplaceAll(constraint, ",", " ")); semverErr == nil {
+		return ok, nil
+	}
+	return false, err
+}
+
+// displayPath returns target relative to path when it lies inside it
+func displayPath(path, target string) string {
+	rel, err := filepath.Rel(path, target)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return target
+	}
+	return rel
+}
+
+// findLocalVenv returns the first of dirs below path that holds a virtualenv
+func findLocalVenv(path string, dirs ...string) string {
+	for _, dir := range dirs {
+		env := filepath.Join(path, dir)
+		if _, err := os.Stat(filepath.Join(env, "pyvenv.cfg")); err == nil {
+			return env
+		}
+	}
+	return ""
+}
+
+func firstVenvMatch(pattern string) string {
+	matches, _ := filepath.Glob(pattern)
+	for _, match := range matches {
+		if _, err := os.Stat(filepath.Join(match, "pyvenv.cfg")); err == nil {
+			return match
+		}
+	}
+	return ""
+}
+
+// findCondaEnv looks for a named Conda environment in the usual envs directories
+func findCondaEnv(name string) string {
+	if name == "" {
+		return ""
+	}
+	if name == "base" {
+		return os.Getenv("CONDA_PREFIX")
+	}
+
+	dirs := filepath.SplitList(os.Getenv("CONDA_ENVS_PATH"))
+	if prefix := os.Getenv("CONDA_PREFIX"); prefix != "" {
+		if filepath.Base(filepath.Dir(prefix)) == "envs" {
+			dirs = append(dirs, filepath.Dir(prefix))
+		} else {
+			dirs = append(dirs, filepath.Join(prefix, "envs"))
+		}
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		for _, install := range []string{".conda", "miniconda3", "anaconda3", "miniforge3", "mambaforge"} {
+			dirs = append(dirs, filepath.Join(home, install, "envs"))
+		}
+	}
+	dirs = append(dirs, "/opt/conda/envs")
+
+	for _, dir := range dirs {
+		env := filepath.Join(dir, name)
+		if _, err := os.Stat(filepath.Join(env, "conda-meta")); err == nil {
+			return env
+		}
+	}
+	return ""
+}
+
+// pyprojectName returns the project name from the [project] or
+// [tool.poetry] table of pyproject.toml
+func pyprojectName(path string) string {
+	file, err := os.Open(filepath.Join(path, "pyproject.toml"))
+	if err != nil {
+		return ""
+	}
+	defer file.Close()
+
+	section := ""
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "[") {
+			section = strings.Trim(line, "[] ")
+			continue
+		}
+		if section != "project" && section != "tool.poetry" {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if ok && strings.TrimSpace(key) == "name" {
+			return strings.Trim(strings.TrimSpace(value), `"'`)
+		}
+	}
+	return ""
+}
+
+func poetryCacheDir() string {
+	if dir := os.Getenv("POETRY_CACHE_DIR"); dir != "" {
+		return dir
+	}
+	cache, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	if runtime.GOOS == "windows" {
+		return filepath.Join(cache, "pypoetry", "Cache")
+	}
+	return filepath.Join(cache, "pypoetry")
+}
+
+// userDataDir returns the per-user application data directory
+// (~/.local/share on Linux)
+func userDataDir() string {
+	switch runtime.GOOS {
+	case "windows":
+		return os.Getenv("LOCALAPPDATA")
+	case "darwin":
+		home, _ := os.UserHomeDir()
+		return filepath.Join(home, "Library", "Application Support")
+	}
+	if dir := os.Getenv("XDG_DATA_HOME"); dir != "" {
+		return dir
+	}
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".local", "share")
+}
@@ -0,0 +1,151 @@
+package checker
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/Sw3bbl3/devdoctor/internal/detector"
+)
+
+func makeVenv(t *testing.T, dir string) {
+	t.Helper()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "pyvenv.cfg"), []byte("version = 3.11.4\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	interpreter := pythonInterpreter(dir)
+	if err := os.MkdirAll(filepath.Dir(interpreter), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(interpreter, nil, 0755); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFindPythonEnvPoetry(t *testing.T) {
+	tmpDir := t.TempDir()
+	cacheDir := t.TempDir()
+	t.Setenv("POETRY_VIRTUALENVS_PATH", cacheDir)
+	if err := os.WriteFile(filepath.Join(tmpDir, "pyproject.toml"), []byte("[tool.poetry]\nname = \"My App\"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tc := detector.PythonToolchain{Manager: "poetry"}
+	if env := findPythonEnv(tmpDir, tc); env != "" {
+		t.Errorf("Expected no environment, got %s", env)
+	}
+
+	makeVenv(t, filepath.Join(cacheDir, "my_app-AbCd1234-py3.11"))
+	if env := findPythonEnv(tmpDir, tc); env == "" {
+		t.Error("Expected Poetry environment in the cache directory to be found")
+	}
+}
+
+func TestFindPythonEnvConda(t *testing.T) {
+	envsDir := t.TempDir()
+	t.Setenv("CONDA_ENVS_PATH", envsDir)
+	t.Setenv("CONDA_PREFIX", "")
+
+	tc := detector.PythonToolchain{Manager: "conda", CondaEnv: "analysis"}
+	if env := findPythonEnv(t.TempDir(), tc); env != "" {
+		t.Errorf("Expected no environment, got %s", env)
+	}
+
+	if err := os.MkdirAll(filepath.Join(envsDir, "analysis", "conda-meta"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if env := findPythonEnv(t.TempDir(), tc); env == "" {
+		t.Error("Expected named Conda environment to be found")
+	}
+}
+
+func TestCheckPythonUv(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmpDir, "pyproject.toml"), []byte("[project]\nname = \"app\"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "uv.lock"), []byte(""), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	issues := checkPython(tmpDir)
+	if len(issues) != 1 || issues[0].Suggestion != "Create it with 'uv sync'" {
+		t.Fatalf("Expected a uv environment warning, got %v", issues)
+	}
+
+	makeVenv(t, filepath.Join(tmpDir, ".venv"))
+	if issues := checkPython(tmpDir); len(issues) != 0 {
+		t.Errorf("Expected no issues once .venv exists, got %v", issues)
+	}
+}
+
+func TestCheckPythonEnvBrokenInterpreter(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("symlinks need extra privileges on Windows")
+	}
+	tmpDir := t.TempDir()
+	env := filepath.Join(tmpDir, ".venv")
+	makeVenv(t, env)
+	interpreter := pythonInterpreter(env)
+	os.Remove(interpreter)
+	if err := os.Symlink(filepath.Join(tmpDir, "python3.9-removed"), interpreter); err != nil {
+		t.Fatal(err)
+	}
+
+	issues := checkPythonEnv(tmpDir, detector.PythonToolchain{Manager: "pip"}, env)
+	if len(issues) != 1 || issues[0].Severity != SeverityError || !strings.Contains(issues[0].Message, "no longer exists") {
+		t.Errorf("Expected broken interpreter error, got %v", issues)
+	}
+}
+
+func TestCheckPythonEnvRequiresPython(t *testing.T) {
+	tests := []struct {
+		pyproject string
+		wantError bool
+	}{
+		{"[project]\nrequires-python = \">=3.12\"\n", true},
+		{"[project]\nrequires-python = \">=3.9,<4\"\n", false},
+		{"[tool.poetry.dependencies]\npython = \"^3.12\"\n", true},
+		{"[tool.poetry.dependencies]\npython = \"^3.10\"\n", false},
+	}
+
+	for _, tt := range tests {
+		tmpDir := t.TempDir()
+		env := filepath.Join(tmpDir, ".venv")
+		makeVenv(t, env)
+		if err := os.WriteFile(filepath.Join(tmpDir, "pyproject.toml"), []byte(tt.pyproject), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		issues := checkPythonEnv(tmpDir, detector.PythonToolchain{Manager: "pip"}, env)
+		if gotError := len(issues) == 1 && strings.Contains(issues[0].Message, "uses Python 3.11.4"); gotError != tt.wantError {
+			t.Errorf("%q: got %v", tt.pyproject, issues)
+		}
+	}
+}
+
+func TestPythonEnvVersion(t *testing.T) {
+	env := t.TempDir()
+	if err := os.WriteFile(filepath.Join(env, "pyvenv.cfg"), []byte("home = /usr/bin\nversion_info = 3.12.1.final.0\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if v := pythonEnvVersion(env); v != "3.12.1" {
+		t.Errorf("pythonEnvVersion() = %q, want 3.12.1", v)
+	}
+
+	conda := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(conda, "conda-meta"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(conda, "conda-meta", "python-3.10.13-h955ad1f_0.json"), []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if v := pythonEnvVersion(conda); v != "3.10.13" {
+		t.Errorf("pythonEnvVersion() = %q, want 3.10.13", v)
+	}
+}
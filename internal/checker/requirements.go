@@ -0,0 +1,406 @@
+package checker
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/Sw3bbl3/devdoctor/internal/detector"
+	"github.com/Sw3bbl3/devdoctor/internal/pep440"
+)
+
+// pyRequirement is one line of a requirements file
+type pyRequirement struct {
+	Name      string
+	Specifier string
+	Marker    string
+}
+
+var (
+	requirementRe  = regexp.MustCompile(`^([A-Za-z0-9][A-Za-z0-9._-]*)\s*(\[[^\]]*\])?\s*(.*)$`)
+	pyNameRe       = regexp.MustCompile(`[-_.]+`)
+	metadataNameRe = regexp.MustCompile(`^(.+?)-(\d[^-]*)(?:-.*)?\.(?:dist|egg)-info$`)
+)
+
+// normalizePyName normalizes a distribution name as described in PEP 503
+func normalizePyName(name string) string {
+	return pyNameRe.ReplaceAllString(strings.ToLower(name), "-")
+}
+
+// readRequirements parses a requirements file, following -r includes.
+// Editable installs, URLs, local paths and other pip options are skipped.
+func readRequirements(file string, seen map[string]bool) ([]pyRequirement, error) {
+	if seen[file] {
+		return nil, nil
+	}
+	seen[file] = true
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	data = []byte(strings.ReplaceAll(strings.ReplaceAll(string(data), "\r\n", "\n"), "\\\n", ""))
+
+	reqs := []pyRequirement{}
+	scanner := bufio.NewScanner(strings.NewReader(string(data)))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.Index(line, "#"); i == 0 || i > 0 && strings.ContainsAny(line[i-1:i], " \t") {
+			line = line[:i]
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		if include, ok := requirementsInclude(line); ok {
+			if !filepath.IsAbs(include) {
+				include = filepath.Join(filepath.Dir(file), include)
+			}
+			included, err := readRequirements(include, seen)
+			if err != nil {
+				return nil, err
+			}
+			reqs = append(reqs, included...)
+			continue
+		}
+		if strings.HasPrefix(line, "-") {
+			continue
+		}
+
+		m := requirementRe.FindStringSubmatch(line)
+		if m == nil || strings.HasSuffix(m[1], ".whl") || strings.HasSuffix(m[1], ".zip") || strings.HasSuffix(m[1], ".tar.gz") {
+			continue
+		}
+		rest, _, _ := strings.Cut(m[3], " --")
+		spec, marker, _ := strings.Cut(rest, ";")
+		spec = strings.TrimSpace(spec)
+		if strings.HasPrefix(spec, "@") {
+			spec = "" // name @ url
+		} else if strings.ContainsAny(spec, ":/") {
+			continue // a URL or path that happens to start like a name
+		}
+		reqs = append(reqs, pyRequirement{
+			Name:      m[1],
+			Specifier: strings.Trim(spec, "()"),
+			Marker:    strings.TrimSpace(marker),
+		})
+	}
+	return reqs, nil
+}
+
+// requirementsInclude returns the file named by a -r or --requirement line
+func requirementsInclude(line string) (string, bool) {
+	for _, opt := range []string{"--requirement", "-r"} {
+		if !strings.HasPrefix(line, opt) {
+			continue
+		}
+		rest := line[len(opt):]
+		if strings.HasPrefix(rest, "=") {
+			rest = rest[1:]
+		} else if opt == "--requirement" && !strings.HasPrefix(rest, " ") {
+			continue
+		}
+		return strings.TrimSpace(rest), true
+	}
+	return "", false
+}
+
+// installedDistributions maps the normalized names of the distributions in
+// the site-packages of env to their versions, read from the *.dist-info and
+// *.egg-info metadata. It returns nil if env has no site-packages.
+func installedDistributions(env string) map[string]string {
+	var dirs []string
+	for _, pattern := range []string{"lib/python*/site-packages", "lib64/python*/site-packages", "Lib/site-packages"} {
+		matches, _ := filepath.Glob(filepath.Join(env, filepath.FromSlash(pattern)))
+		dirs = append(dirs, matches...)
+	}
+	if len(dirs) == 0 {
+		return nil
+	}
+
+	installed := map[string]string{}
+	for _, dir := range dirs {
+		entries, _ := os.ReadDir(dir)
+		for _, entry := range entries {
+			if !strings.HasSuffix(entry.Name(), ".dist-info") && !strings.HasSuffix(entry.Name(), ".egg-info") {
+				continue
+			}
+			name, version := distributionMetadata(filepath.Join(dir, entry.Name()), entry.IsDir())
+			if name == "" {
+				if m := metadataNameRe.FindStringSubmatch(entry.Name()); m != nil {
+					name, version = m[1], m[2]
+				}
+			}
+			if name != "" {
+				installed[normalizePyName(name)] = version
+			}
+		}
+	}
+	return installed
+}
+
+// distributionMetadata reads the Name and Version headers of an installed
+// distribution
+func distributionMetadata(path string, isDir bool) (string, string) {
+	file := path
+	if isDir {
+		file = filepath.Join(path, "METADATA")
+		if strings.HasSuffix(path, ".egg-info") {
+			file = filepath.Join(path, "PKG-INFO")
+		}
+	}
+	f, err := os.Open(file)
+	if err != nil {
+		return "", ""
+	}
+	defer f.Close()
+
+	var name, version string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() && scanner.Text() != "" {
+		key, value, ok := strings.Cut(scanner.Text(), ":")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "Name":
+			name = strings.TrimSpace(value)
+		case "Version":
+			version = strings.TrimSpace(value)
+		}
+	}
+	return name, version
+}
+
+// checkRequirements compares requirements.txt with the distributions
+// installed in env without running pip
+func checkRequirements(path string, tc detector.PythonToolchain, env string) []Issue {
+	issues := []Issue{}
+	install := fmt.Sprintf("Run '%s -m pip install -r requirements.txt'", displayPath(path, pythonInterpreter(env)))
+	if tc.Manager == "uv" {
+		install = "Run 'uv pip install -r requirements.txt'"
+	}
+
+	reqs, err := readRequirements(filepath.Join(path, "requirements.txt"), map[string]bool{})
+	if err != nil {
+		return append(issues, Issue{
+			Severity:    SeverityWarning,
+			ProjectType: "Python",
+			Message:     fmt.Sprintf("Cannot read requirements: %v", err),
+			Suggestion:  "Check the -r includes in requirements.txt",
+		})
+	}
+	installed := installedDistributions(env)
+	if installed == nil {
+		return issues
+	}
+
+	markerEnv := pythonMarkerEnv(pythonEnvVersion(env))
+	missing, mismatched := []string{}, []string{}
+	for _, req := range reqs {
+		if req.Marker != "" {
+			if ok, err := evaluateMarker(req.Marker, markerEnv); err == nil && !ok {
+				continue
+			}
+		}
+		version, ok := installed[normalizePyName(req.Name)]
+		if !ok {
+			missing = append(missing, req.Name)
+			continue
+		}
+		spec, err := pep440.ParseSpecifier(req.Specifier)
+		if err != nil {
+			continue
+		}
+		if v, err := pep440.Parse(version); err == nil && !spec.Contains(v) {
+			mismatched = append(mismatched, fmt.Sprintf("%s %s (requires %s)", req.Name, version, req.Specifier))
+		}
+	}
+
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		issues = append(issues, Issue{
+			Severity:    SeverityError,
+			ProjectType: "Python",
+			Message:     fmt.Sprintf("%d requirements are not installed in %s: %s", len(missing), displayPath(path, env), strings.Join(missing, ", ")),
+			Suggestion:  install,
+		})
+	}
+	if len(mismatched) > 0 {
+		sort.Strings(mismatched)
+		issues = append(issues, Issue{
+			Severity:    SeverityError,
+			ProjectType: "Python",
+			Message:     fmt.Sprintf("%d installed packages do not match requirements.txt: %s", len(mismatched), strings.Join(mismatched, ", ")),
+			Suggestion:  install,
+		})
+	}
+
+	return issues
+}
+
+// pythonMarkerEnv returns the PEP 508 marker variables for this machine and
+// the given Python version
+func pythonMarkerEnv(pythonVersion string) map[string]string {
+	env := map[string]string{
+		"python_full_version":            pythonVersion,
+		"implementation_name":            "cpython",
+		"platform_python_implementation": "CPython",
+		"os_name":                        "posix",
+		"sys_platform":                   runtime.GOOS,
+		"platform_system":                strings.ToUpper(runtime.GOOS[:1]) + runtime.GOOS[1:],
+		"platform_machine":               runtime.GOARCH,
+		"extra":                          "",
+	}
+	if parts := strings.SplitN(pythonVersion, ".", 3); len(parts) >= 2 {
+		env["python_version"] = parts[0] + "." + parts[1]
+	}
+	if runtime.GOOS == "windows" {
+		env["os_name"] = "nt"
+		env["sys_platform"] = "win32"
+	}
+	switch runtime.GOARCH {
+	case "amd64":
+		env["platform_machine"] = "x86_64"
+		if runtime.GOOS == "windows" {
+			env["platform_machine"] = "AMD64"
+		}
+	case "arm64":
+		if runtime.GOOS == "linux" {
+			env["platform_machine"] = "aarch64"
+		}
+	}
+	return env
+}
+
+var markerTokenRe = regexp.MustCompile(`^\s*(\(|\)|'[^']*'|"[^"]*"|===|==|!=|~=|<=|>=|<|>|not\s+in\b|in\b|and\b|or\b|[A-Za-z_][A-Za-z0-9_.]*)`)
+
+// evaluateMarker evaluates an environment marker such as
+// python_version < "3.11" and sys_platform != "win32"
+func evaluateMarker(marker string, env map[string]string) (bool, error) {
+	tokens := []string{}
+	for rest := marker; strings.TrimSpace(rest) != ""; {
+		m := markerTokenRe.FindStringSubmatch(rest)
+		if m == nil {
+			return false, fmt.Errorf("invalid marker %q", marker)
+		}
+		tokens = append(tokens, strings.Join(strings.Fields(m[1]), " "))
+		rest = rest[len(m[0]):]
+	}
+	p := &markerParser{tokens: tokens, env: env}
+	result, err := p.or()
+	if err == nil && p.pos < len(tokens) {
+		err = fmt.Errorf("invalid marker %q", marker)
+	}
+	return result, err
+}
+
+type markerParser struct {
+	tokens []string
+	pos    int
+	env    map[string]string
+}
+
+func (p *markerParser) next() string {
+	if p.pos >= len(p.tokens) {
+		return ""
+	}
+	p.pos++
+	return p.tokens[p.pos-1]
+}
+
+func (p *markerParser) peek() string {
+	if p.pos >= len(p.tokens) {
+		return ""
+	}
+	return p.tokens[p.pos]
+}
+
+func (p *markerParser) or() (bool, error) {
+	result, err := p.and()
+	for err == nil && p.peek() == "or" {
+		p.next()
+		var rhs bool
+		rhs, err = p.and()
+		result = result || rhs
+	}
+	return result, err
+}
+
+func (p *markerParser) and() (bool, error) {
+	result, err := p.atom()
+	for err == nil && p.peek() == "and" {
+		p.next()
+		var rhs bool
+		rhs, err = p.atom()
+		result = result && rhs
+	}
+	return result, err
+}
+
+func (p *markerParser) atom() (bool, error) {
+	if p.peek() == "(" {
+		p.next()
+		result, err := p.or()
+		if err == nil && p.next() != ")" {
+			err = fmt.Errorf("missing ')' in marker")
+		}
+		return result, err
+	}
+
+	lhs, lhsVar, err := p.value()
+	if err != nil {
+		return false, err
+	}
+	op := p.next()
+	rhs, rhsVar, err := p.value()
+	if err != nil {
+		return false, err
+	}
+
+	switch op {
+	case "in":
+		return strings.Contains(rhs, lhs), nil
+	case "not in":
+		return !strings.Contains(rhs, lhs), nil
+	case "===", "==", "!=", "~=", "<=", ">=", "<", ">":
+	default:
+		return false, fmt.Errorf("invalid marker operator %q", op)
+	}
+	if isVersionMarker(lhsVar) || isVersionMarker(rhsVar) {
+		if ok, err := pep440.Satisfies(lhs, op+rhs); err == nil {
+			return ok, nil
+		}
+	}
+	switch op {
+	case "==", "===":
+		return lhs == rhs, nil
+	case "!=":
+		return lhs != rhs, nil
+	}
+	return false, fmt.Errorf("cannot compare %q %s %q", lhs, op, rhs)
+}
+
+// value returns the value of a quoted literal or marker variable, and the
+// variable name
+func (p *markerParser) value() (string, string, error) {
+	token := p.next()
+	if len(token) >= 2 && (token[0] == '\'' || token[0] == '"') {
+		return token[1 : len(token)-1], "", nil
+	}
+	value, ok := p.env[token]
+	if !ok {
+		return "", "", fmt.Errorf("unknown marker variable %q", token)
+	}
+	return value, token, nil
+}
+
+func isVersionMarker(name string) bool {
+	return name == "python_version" || name == "python_full_version" || name == "implementation_version"
+}
@@ -0,0 +1,110 @@
+package checker
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestReadRequirements(t *testing.T) {
+	tmpDir := t.TempDir()
+	os.WriteFile(filepath.Join(tmpDir, "requirements.txt"), []byte(`# app
+-r requirements/base.txt
+--index-url https://pypi.org/simple
+-e .
+Django==4.2.1  # pinned
+requests[socks] >=2.31, <3 ; python_version >= "3.8"
+git+https://github.com/org/repo.git#egg=repo
+mypkg @ https://example.com/mypkg-1.0.tar.gz
+numpy==1.26.0 \
+    --hash=sha256:abc
+`), 0644)
+	os.MkdirAll(filepath.Join(tmpDir, "requirements"), 0755)
+	os.WriteFile(filepath.Join(tmpDir, "requirements", "base.txt"), []byte("six\n-r ../requirements.txt\n"), 0644)
+
+	reqs, err := readRequirements(filepath.Join(tmpDir, "requirements.txt"), map[string]bool{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []pyRequirement{
+		{Name: "six"},
+		{Name: "Django", Specifier: "==4.2.1"},
+		{Name: "requests", Specifier: ">=2.31, <3", Marker: `python_version >= "3.8"`},
+		{Name: "mypkg"},
+		{Name: "numpy", Specifier: "==1.26.0"},
+	}
+	if !reflect.DeepEqual(reqs, want) {
+		t.Errorf("readRequirements() = %+v, want %+v", reqs, want)
+	}
+
+	os.WriteFile(filepath.Join(tmpDir, "broken.txt"), []byte("-r missing.txt\n"), 0644)
+	if _, err := readRequirements(filepath.Join(tmpDir, "broken.txt"), map[string]bool{}); err == nil {
+		t.Error("Expected an error for a missing include")
+	}
+}
+
+func TestEvaluateMarker(t *testing.T) {
+	env := map[string]string{"python_version": "3.11", "python_full_version": "3.11.4", "sys_platform": "linux", "extra": ""}
+	tests := []struct {
+		marker string
+		want   bool
+	}{
+		{`python_version >= "3.8"`, true},
+		{`python_version < '3.10'`, false},
+		{`sys_platform == "win32" or python_full_version >= "3.11.2"`, true},
+		{`(sys_platform == "linux" and python_version < "3.11") or extra == "test"`, false},
+		{`"linux" in sys_platform`, true},
+		{`sys_platform not in "win32 cygwin"`, true},
+	}
+	for _, tt := range tests {
+		got, err := evaluateMarker(tt.marker, env)
+		if err != nil || got != tt.want {
+			t.Errorf("evaluateMarker(%q) = %v, %v; want %v", tt.marker, got, err, tt.want)
+		}
+	}
+	if _, err := evaluateMarker(`platform_version == "1"`, env); err == nil {
+		t.Error("Expected an error for an unknown variable")
+	}
+}
+
+func TestCheckRequirements(t *testing.T) {
+	tmpDir := t.TempDir()
+	env := filepath.Join(tmpDir, ".venv")
+	makeVenv(t, env)
+	site := filepath.Join(env, "lib", "python3.11", "site-packages")
+	for dir, metadata := range map[string]string{
+		"Django-4.1.0.dist-info":            "Metadata-Version: 2.1\nName: Django\nVersion: 4.1.0\n\nDescription",
+		"typing_extensions-4.8.0.dist-info": "",
+	} {
+		os.MkdirAll(filepath.Join(site, dir), 0755)
+		if metadata != "" {
+			os.WriteFile(filepath.Join(site, dir, "METADATA"), []byte(metadata), 0644)
+		}
+	}
+	os.WriteFile(filepath.Join(tmpDir, "requirements.txt"), []byte(`django==4.2.1
+typing-extensions>=4.0
+requests
+pywin32; sys_platform == "win32"
+`), 0644)
+
+	issues := checkPython(tmpDir)
+	var messages []string
+	for _, issue := range issues {
+		messages = append(messages, issue.Message)
+	}
+	got := strings.Join(messages, "\n")
+	for _, want := range []string{
+		"1 requirements are not installed in .venv: requests",
+		"1 installed packages do not match requirements.txt: django 4.1.0 (requires ==4.2.1)",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Expected %q, got:\n%s", want, got)
+		}
+	}
+	if runtime.GOOS != "windows" && strings.Contains(got, "pywin32") {
+		t.Errorf("Unexpected issue for a requirement excluded by its marker:\n%s", got)
+	}
+}
@@ -0,0 +1,96 @@
+package checker
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Sw3bbl3/devdoctor/internal/envcheck"
+	"github.com/Sw3bbl3/devdoctor/internal/versionpin"
+)
+
+// pinnedTools maps the tools of version pins to the command envcheck runs
+// and the name shown in issues
+var pinnedTools = map[string]struct {
+	Commands []string
+	Label    string
+}{
+	"node":   {[]string{"node"}, "Node.js"},
+	"python": {[]string{"python", "python3"}, "Python"},
+	"ruby":   {[]string{"ruby"}, "Ruby"},
+	"go":     {[]string{"go"}, "Go"},
+	"java":   {[]string{"java"}, "Java"},
+	"rust":   {[]string{"rustc"}, "Rust"},
+	"dotnet": {[]string{"dotnet"}, ".NET"},
+	"maven":  {[]string{"mvn"}, "Maven"},
+	"gradle": {[]string{"gradle"}, "Gradle"},
+}
+
+// pinSuggestions maps pin files to the command that installs the pinned
+// version; {version} is replaced with the pinned version
+var pinSuggestions = map[string]string{
+	".nvmrc":              "Run 'nvm install' (or 'fnm use') to switch to Node.js {version}",
+	".node-version":       "Run 'fnm use' or 'nodenv install' to switch to Node.js {version}",
+	".python-version":     "Run 'pyenv install {version}' to install the pinned Python",
+	".ruby-version":       "Run 'rbenv install {version}' (or 'rvm install {version}') to install the pinned Ruby",
+	".go-version":         "Run 'goenv install {version}' or install Go {version} from https://go.dev/dl/",
+	".java-version":       "Run 'jenv add' for a JDK {version} installation and 'jenv local {version}'",
+	".sdkmanrc":           "Run 'sdk env install' to install the versions pinned in .sdkmanrc",
+	".tool-versions":      "Run 'asdf install' to install the versions pinned in .tool-versions",
+	"mise.toml":           "Run 'mise install' to install the versions pinned in mise.toml",
+	".mise.toml":          "Run 'mise install' to install the versions pinned in .mise.toml",
+	"rust-toolchain":      "Run 'rustup toolchain install {version}' or use rustup instead of a system rustc",
+	"rust-toolchain.toml": "Run 'rustup toolchain install {version}' or use rustup instead of a system rustc",
+}
+
+// CheckRoot runs the checks that apply to a project directory as a whole
+// rather than to a single project type
+func CheckRoot(path, root string) []Issue {
+	issues := []Issue{}
+
+	issues = append(issues, checkVersionPins(path)...)
+	issues = append(issues, checkEnvFile(path)...)
+	issues = append(issues, checkPortConflicts(path)...)
+
+	for i := range issues {
+		issues[i].Root = root
+	}
+	return issues
+}
+
+// checkVersionPins compares the versions pinned by version manager files
+// with the versions envcheck finds
+func checkVersionPins(path string) []Issue {
+	issues := []Issue{}
+
+	for _, pin := range versionpin.Find(path) {
+		tool, ok := pinnedTools[pin.Tool]
+		// checkDotNet applies the rollForward policy of global.json
+		if !ok || pin.File == "global.json" {
+			continue
+		}
+		// Run from the project so shims resolve the toolchain it pins
+		var status envcheck.ToolStatus
+		for _, command := range tool.Commands {
+			if status = envcheck.LookupIn(path, command); status.Found {
+				break
+			}
+		}
+		// Missing tools are reported by the project checks
+		if !status.Found || status.Version == "" || versionpin.Matches(pin, status.Version) {
+			continue
+		}
+
+		suggestion := fmt.Sprintf("Install %s %s", tool.Label, pin.Version)
+		if template, ok := pinSuggestions[pin.File]; ok {
+			suggestion = strings.ReplaceAll(template, "{version}", pin.Version)
+		}
+		issues = append(issues, Issue{
+			Severity:    SeverityError,
+			ProjectType: tool.Label,
+			Message:     fmt.Sprintf("%s pins %s %s but %s is installed", pin.File, tool.Label, pin.Version, status.Version),
+			Suggestion:  suggestion,
+		})
+	}
+
+	return issues
+}
@@ -0,0 +1,73 @@
+package checker
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestCheckVersionPins(t *testing.T) {
+	if !isCommandAvailable("go") {
+		t.Skip("go is not in PATH")
+	}
+	tmpDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmpDir, ".go-version"), []byte("1.0.0\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	issues := CheckRoot(tmpDir, "services/api")
+	if len(issues) != 1 {
+		t.Fatalf("Expected 1 issue, got %v", issues)
+	}
+	issue := issues[0]
+	if issue.Severity != SeverityError || !strings.HasPrefix(issue.Message, ".go-version pins Go 1.0.0 but") {
+		t.Errorf("Unexpected issue %+v", issue)
+	}
+	if issue.Root != "services/api" {
+		t.Errorf("Expected root to be set, got %q", issue.Root)
+	}
+}
+
+// installDirShims replaces PATH with fake commands that, like the shims of
+// rustup, asdf or fnm, report the version the current directory selects:
+// the content of its .shim-version file, or defaultVersion. Each output
+// refers to the version as $v.
+func installDirShims(t *testing.T, defaultVersion string, outputs map[string]string) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake shims are shell scripts")
+	}
+	shims := t.TempDir()
+	for command, output := range outputs {
+		script := "#!/bin/sh\nv='" + defaultVersion + "'\n[ -f .shim-version ] && read v < .shim-version\necho \"" + output + "\"\n"
+		if err := os.WriteFile(filepath.Join(shims, command), []byte(script), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv("PATH", shims)
+}
+
+func TestCheckVersionPinsRunsFromProject(t *testing.T) {
+	installDirShims(t, "1.70.0", map[string]string{"rustc": "rustc $v (82e1608df 2023-12-21)"})
+
+	pinned := t.TempDir()
+	for name, content := range map[string]string{"rust-toolchain": "1.75.0\n", ".shim-version": "1.75.0\n"} {
+		if err := os.WriteFile(filepath.Join(pinned, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if issues := checkVersionPins(pinned); len(issues) != 0 {
+		t.Errorf("checkVersionPins() with the pinned toolchain selected = %v, want none", issues)
+	}
+
+	other := t.TempDir()
+	if err := os.WriteFile(filepath.Join(other, "rust-toolchain"), []byte("1.75.0\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	issues := checkVersionPins(other)
+	if len(issues) != 1 || issues[0].Message != "rust-toolchain pins Rust 1.75.0 but 1.70.0 is installed" {
+		t.Errorf("checkVersionPins() = %v", issues)
+	}
+}
@@ -0,0 +1,398 @@
+package checker
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Sw3bbl3/devdoctor/internal/envcheck"
+)
+
+// gemLockSpec is a gem locked in Gemfile.lock
+type gemLockSpec struct {
+	Name, Version string
+	Platforms     []string // "" for pure-Ruby gems
+	Revision      string   // for gems from git sources
+}
+
+// gemLockfile holds the parts of Gemfile.lock the checks need
+type gemLockfile struct {
+	Specs       []*gemLockSpec
+	RubyVersion string
+	BundledWith string
+}
+
+var gemSpecRe = regexp.MustCompile(`^    (\S+) \(([^)]+)\)$`)
+
+// parseGemLockfile parses the GEM and GIT sources and the RUBY VERSION and
+// BUNDLED WITH sections of Gemfile.lock. PATH gems live in the repository.
+func parseGemLockfile(data string) *gemLockfile {
+	lock := &gemLockfile{}
+	index := map[string]*gemLockSpec{}
+	section, revision := "", ""
+	scanner := bufio.NewScanner(strings.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if line == "" {
+			continue
+		}
+		if !strings.HasPrefix(line, " ") {
+			section, revision = line, ""
+			continue
+		}
+		switch section {
+		case "RUBY VERSION":
+			lock.RubyVersion = strings.TrimPrefix(strings.TrimSpace(line), "ruby ")
+		case "BUNDLED WITH":
+			lock.BundledWith = strings.TrimSpace(line)
+		case "GEM", "GIT":
+			if value, ok := strings.CutPrefix(line, "  revision: "); ok {
+				revision = value
+				continue
+			}
+			m := gemSpecRe.FindStringSubmatch(line)
+			if m == nil {
+				continue
+			}
+			// Native gems carry a platform: nokogiri (1.15.4-x86_64-linux)
+			version, platform := m[2], ""
+			if i := strings.Index(version, "-"); i >= 0 {
+				version, platform = version[:i], version[i+1:]
+			}
+			key := m[1] + " " + version
+			spec, ok := index[key]
+			if !ok {
+				spec = &gemLockSpec{Name: m[1], Version: version}
+				if section == "GIT" {
+					spec.Revision = revision
+				}
+				index[key] = spec
+				lock.Specs = append(lock.Specs, spec)
+			}
+			spec.Platforms = append(spec.Platforms, platform)
+		}
+	}
+	return lock
+}
+
+var gemfileRubyRe = regexp.MustCompile(`(?m)^\s*ruby[\s(]+(.+)$`)
+var quotedRe = regexp.MustCompile(`["']([^"']+)["']`)
+
+// gemfileRubyRequirement returns the requirements of the Gemfile's ruby
+// directive. A "file:" argument reads the exact version from that file.
+func gemfileRubyRequirement(path string) []string {
+	data, err := os.ReadFile(filepath.Join(path, "Gemfile"))
+	if err != nil {
+		return nil
+	}
+	m := gemfileRubyRe.FindStringSubmatch(string(data))
+	if m == nil {
+		return nil
+	}
+	args, _, _ := strings.Cut(m[1], "#")
+
+	if strings.Contains(args, "file:") || strings.Contains(args, ":file") {
+		file := quotedRe.FindStringSubmatch(args)
+		if file == nil {
+			return nil
+		}
+		content, err := os.ReadFile(filepath.Join(path, file[1]))
+		if err != nil {
+			return nil
+		}
+		version := strings.TrimPrefix(strings.TrimSpace(strings.SplitN(string(content), "\n", 2)[0]), "ruby-")
+		return []string{"= " + version}
+	}
+
+	// Requirements come before options such as engine: "jruby"
+	if i := strings.Index(args, ":"); i >= 0 {
+		args = args[:i]
+	}
+	reqs := []string{}
+	for _, q := range quotedRe.FindAllStringSubmatch(args, -1) {
+		reqs = append(reqs, q[1])
+	}
+	return reqs
+}
+
+var gemRequirementRe = regexp.MustCompile(`^\s*(=|!=|>=|<=|>|<|~>)?\s*(\d[0-9A-Za-z.]*)\s*$`)
+
+// gemSatisfies evaluates RubyGems requirements such as "~> 3.2", ">= 3.0"
+// and "3.2.2" against a version
+func gemSatisfies(version string, reqs []string) (bool, error) {
+	for _, req := range reqs {
+		m := gemRequirementRe.FindStringSubmatch(req)
+		if m == nil {
+			return false, fmt.Errorf("invalid requirement %q", req)
+		}
+		c := compareGemVersions(version, m[2])
+		ok := false
+		switch m[1] {
+		case "", "=":
+			ok = c == 0
+		case "!=":
+			ok = c != 0
+		case ">=":
+			ok = c >= 0
+		case "<=":
+			ok = c <= 0
+		case ">":
+			ok = c > 0
+		case "<":
+			ok = c < 0
+		case "~>":
+			// ~> 3.2 := >= 3.2, < 4; ~> 3.2.1 := >= 3.2.1, < 3.3
+			ok = c >= 0 && compareGemVersions(version, gemBump(m[2])) < 0
+		}
+		if !ok {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// gemBump drops the last segment of a version and increments the one before
+func gemBump(v string) string {
+	segments := strings.Split(v, ".")
+	for len(segments) > 1 {
+		if _, err := strconv.Atoi(segments[len(segments)-1]); err == nil {
+			break
+		}
+		segments = segments[:len(segments)-1] // prerelease segments
+	}
+	if len(segments) > 1 {
+		segments = segments[:len(segments)-1]
+	}
+	n, _ := strconv.Atoi(segments[len(segments)-1])
+	segments[len(segments)-1] = strconv.Itoa(n + 1)
+	return strings.Join(segments, ".")
+}
+
+// compareGemVersions compares versions the way Gem::Version does: numeric
+// segments by value, and a letter segment marks a prerelease
+func compareGemVersions(a, b string) int {
+	sa, sb := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(sa) || i < len(sb); i++ {
+		x, y := "0", "0"
+		if i < len(sa) {
+			x = sa[i]
+		}
+		if i < len(sb) {
+			y = sb[i]
+		}
+		nx, errX := strconv.Atoi(x)
+		ny, errY := strconv.Atoi(y)
+		switch {
+		case errX == nil && errY == nil:
+			if nx != ny {
+				if nx < ny {
+					return -1
+				}
+				return 1
+			}
+		case errX != nil && errY != nil:
+			if x != y {
+				return strings.Compare(x, y)
+			}
+		case errX != nil:
+			return -1
+		default:
+			return 1
+		}
+	}
+	return 0
+}
+
+var rubyVersionRe = regexp.MustCompile(`^\d+(\.\d+)*`)
+
+// installedRubyVersion returns the version of the ruby that rbenv or chruby
+// select for path, without its patch level (3.2.2 for 3.2.2p53)
+func installedRubyVersion(path string) string {
+	status := envcheck.LookupIn(path, "ruby")
+	if !status.Found {
+		return ""
+	}
+	return rubyVersionRe.FindString(status.Version)
+}
+
+// bundlePath returns BUNDLE_PATH from the environment or .bundle/config
+func bundlePath(path string) string {
+	if dir := os.Getenv("BUNDLE_PATH"); dir != "" {
+		return dir
+	}
+	data, err := os.ReadFile(filepath.Join(path, ".bundle", "config"))
+	if err != nil {
+		return ""
+	}
+	settings := map[string]string{}
+	for _, line := range strings.Split(string(data), "\n") {
+		if key, value, ok := strings.Cut(line, ":"); ok {
+			settings[strings.TrimSpace(key)] = strings.Trim(strings.TrimSpace(value), `"'`)
+		}
+	}
+	if dir := settings["BUNDLE_PATH"]; dir != "" {
+		return dir
+	}
+	if settings["BUNDLE_DEPLOYMENT"] == "true" {
+		return "vendor/bundle"
+	}
+	return ""
+}
+
+// gemDirs returns the directories Bundler installs the project's gems into:
+// <BUNDLE_PATH>/ruby/<abi> when a bundle path is configured, otherwise
+// GEM_HOME and GEM_PATH, otherwise the paths the project's ruby reports
+func gemDirs(path string) []string {
+	if dir := bundlePath(path); dir != "" {
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(path, dir)
+		}
+		matches, _ := filepath.Glob(filepath.Join(dir, "ruby", "*"))
+		return matches
+	}
+
+	dirs := []string{}
+	if home := os.Getenv("GEM_HOME"); home != "" {
+		dirs = append(dirs, home)
+	}
+	dirs = append(dirs, filepath.SplitList(os.Getenv("GEM_PATH"))...)
+	if len(dirs) > 0 {
+		return dirs
+	}
+	if !isCommandAvailable("ruby") {
+		return nil
+	}
+	cmd := exec.Command("ruby", "-e", "puts Gem.path")
+	cmd.Dir = path
+	out, err := cmd.Output()
+	if err != nil {
+		return nil
+	}
+	return strings.Fields(string(out))
+}
+
+// gemInstalled reports whether a locked gem is installed in one of dirs.
+// Default gems that ship with Ruby are found in specifications/default.
+func gemInstalled(spec *gemLockSpec, dirs []string) bool {
+	for _, dir := range dirs {
+		if spec.Revision != "" {
+			rev := spec.Revision
+			if len(rev) > 12 {
+				rev = rev[:12]
+			}
+			if pathExists(filepath.Join(dir, "bundler", "gems", spec.Name+"-"+rev)) {
+				return true
+			}
+			continue
+		}
+		for _, platform := range spec.Platforms {
+			name := spec.Name + "-" + spec.Version
+			if platform != "" {
+				name += "-" + platform
+			}
+			if pathExists(filepath.Join(dir, "specifications", name+".gemspec")) ||
+				pathExists(filepath.Join(dir, "specifications", "default", name+".gemspec")) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// checkRubyVersions compares the Gemfile ruby directive and Gemfile.lock
+// with the installed ruby and bundler
+func checkRubyVersions(path string, lock *gemLockfile) []Issue {
+	issues := []Issue{}
+	installed := installedRubyVersion(path)
+
+	if reqs := gemfileRubyRequirement(path); len(reqs) > 0 && installed != "" {
+		ok, err := gemSatisfies(installed, reqs)
+		if err != nil {
+			issues = append(issues, Issue{
+				Severity:    SeverityWarning,
+				ProjectType: "Ruby",
+				Message:     fmt.Sprintf("Cannot evaluate the Gemfile ruby requirement: %v", err),
+				Suggestion:  "Use a RubyGems requirement such as '~> 3.2'",
+			})
+		} else if !ok {
+			issues = append(issues, Issue{
+				Severity:    SeverityError,
+				ProjectType: "Ruby",
+				Message:     fmt.Sprintf("Gemfile requires Ruby %s but %s is installed", strings.Join(reqs, ", "), installed),
+				Suggestion:  "Install a matching Ruby, e.g. with 'rbenv install' or 'rvm install'",
+			})
+		}
+	} else if lock != nil && lock.RubyVersion != "" && installed != "" {
+		// Without a ruby directive the locked version is only informational
+		if locked := rubyVersionRe.FindString(lock.RubyVersion); locked != "" && locked != installed {
+			issues = append(issues, Issue{
+				Severity:    SeverityWarning,
+				ProjectType: "Ruby",
+				Message:     fmt.Sprintf("Gemfile.lock was resolved with Ruby %s but %s is installed", locked, installed),
+				Suggestion:  fmt.Sprintf("Install Ruby %s, or run 'bundle update --ruby' to record the new version", locked),
+			})
+		}
+	}
+
+	if lock == nil || lock.BundledWith == "" || !isCommandAvailable("bundle") {
+		return issues
+	}
+	bundler := envcheck.LookupIn(path, "bundle").Version
+	if bundler == "" || bundler == lock.BundledWith {
+		return issues
+	}
+	lockedMajor, _, _ := strings.Cut(lock.BundledWith, ".")
+	installedMajor, _, _ := strings.Cut(bundler, ".")
+	if lockedMajor != installedMajor {
+		issues = append(issues, Issue{
+			Severity:    SeverityError,
+			ProjectType: "Ruby",
+			Message:     fmt.Sprintf("Gemfile.lock was bundled with Bundler %s but Bundler %s is installed", lock.BundledWith, bundler),
+			Suggestion:  fmt.Sprintf("Run 'gem install bundler -v %s'", lock.BundledWith),
+		})
+		return issues
+	}
+	// Bundler 2.3+ switches to the locked version itself when it is installed
+	if !gemInstalled(&gemLockSpec{Name: "bundler", Version: lock.BundledWith, Platforms: []string{""}}, gemDirs(path)) {
+		issues = append(issues, Issue{
+			Severity:    SeverityWarning,
+			ProjectType: "Ruby",
+			Message:     fmt.Sprintf("Gemfile.lock was bundled with Bundler %s but %s is installed", lock.BundledWith, bundler),
+			Suggestion:  fmt.Sprintf("Run 'gem install bundler -v %s'", lock.BundledWith),
+		})
+	}
+	return issues
+}
+
+// checkGemsInstalled reports locked gems missing from the gem path, like
+// 'bundle check' does
+func checkGemsInstalled(path string, lock *gemLockfile) []Issue {
+	issues := []Issue{}
+	dirs := gemDirs(path)
+	if len(dirs) == 0 {
+		return issues
+	}
+
+	missing := []string{}
+	for _, spec := range lock.Specs {
+		if spec.Name != "bundler" && !gemInstalled(spec, dirs) {
+			missing = append(missing, spec.Name+" "+spec.Version)
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		issues = append(issues, Issue{
+			Severity:    SeverityError,
+			ProjectType: "Ruby",
+			Message:     fmt.Sprintf("%d gems from Gemfile.lock are not installed: %s", len(missing), strings.Join(missing, ", ")),
+			Suggestion:  "Run 'bundle install' to install them",
+		})
+	}
+	return issues
+}
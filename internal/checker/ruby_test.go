@@ -0,0 +1,155 @@
+package checker
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testGemfileLock = `GIT
+  remote: https://github.com/rails/rails.git
+  revision: 0123456789abcdef0123456789abcdef01234567
+  specs:
+    rails (7.1.0.alpha)
+
+PATH
+  remote: engines/billing
+  specs:
+    billing (0.1.0)
+
+GEM
+  remote: https://rubygems.org/
+  specs:
+    nokogiri (1.15.4-arm64-darwin)
+      racc (~> 1.4)
+    nokogiri (1.15.4-x86_64-linux)
+      racc (~> 1.4)
+    racc (1.7.1)
+    rake (13.0.6)
+
+PLATFORMS
+  arm64-darwin
+  x86_64-linux
+
+DEPENDENCIES
+  nokogiri
+  rails!
+
+RUBY VERSION
+   ruby 3.2.2p53
+
+BUNDLED WITH
+   2.4.19
+`
+
+func TestParseGemLockfile(t *testing.T) {
+	lock := parseGemLockfile(testGemfileLock)
+	if lock.RubyVersion != "3.2.2p53" || lock.BundledWith != "2.4.19" {
+		t.Errorf("RubyVersion = %q, BundledWith = %q", lock.RubyVersion, lock.BundledWith)
+	}
+
+	got := []string{}
+	for _, spec := range lock.Specs {
+		got = append(got, spec.Name+" "+spec.Version+" "+strings.Join(spec.Platforms, ","))
+	}
+	want := []string{
+		"rails 7.1.0.alpha ",
+		"nokogiri 1.15.4 arm64-darwin,x86_64-linux",
+		"racc 1.7.1 ",
+		"rake 13.0.6 ",
+	}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("Specs = %q, want %q", got, want)
+	}
+	if lock.Specs[0].Revision != "0123456789abcdef0123456789abcdef01234567" {
+		t.Errorf("Revision = %q", lock.Specs[0].Revision)
+	}
+}
+
+func TestGemSatisfies(t *testing.T) {
+	tests := []struct {
+		version string
+		reqs    []string
+		want    bool
+	}{
+		{"3.2.2", []string{"3.2.2"}, true},
+		{"3.2.2", []string{"= 3.2.1"}, false},
+		{"3.2.2", []string{"~> 3.2"}, true},
+		{"4.0.0", []string{"~> 3.2"}, false},
+		{"3.2.9", []string{"~> 3.2.1"}, true},
+		{"3.3.0", []string{"~> 3.2.1"}, false},
+		{"3.1.4", []string{">= 3.0", "< 3.2"}, true},
+		{"3.2.0", []string{">= 3.0", "< 3.2"}, false},
+		{"3.3.0.preview1", []string{">= 3.3"}, false},
+		{"3.3.0.preview1", []string{">= 3.3.0.a"}, true},
+	}
+	for _, tt := range tests {
+		got, err := gemSatisfies(tt.version, tt.reqs)
+		if err != nil {
+			t.Errorf("gemSatisfies(%q, %q) error: %v", tt.version, tt.reqs, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("gemSatisfies(%q, %q) = %v, want %v", tt.version, tt.reqs, got, tt.want)
+		}
+	}
+	if _, err := gemSatisfies("3.2.2", []string{"latest"}); err == nil {
+		t.Error("gemSatisfies() with an invalid requirement should fail")
+	}
+}
+
+func TestGemfileRubyRequirement(t *testing.T) {
+	tests := []struct {
+		gemfile string
+		want    string
+	}{
+		{"source \"https://rubygems.org\"\nruby \"3.2.2\"\n", "3.2.2"},
+		{"ruby '>= 3.1', '< 3.4' # supported range\n", ">= 3.1|< 3.4"},
+		{"ruby \"3.2.2\", engine: \"jruby\", engine_version: \"9.4.3.0\"\n", "3.2.2"},
+		{"ruby file: \".ruby-version\"\n", "= 3.3.0"},
+		{"gem \"rails\"\n", ""},
+	}
+	for _, tt := range tests {
+		dir := t.TempDir()
+		os.WriteFile(filepath.Join(dir, "Gemfile"), []byte(tt.gemfile), 0644)
+		os.WriteFile(filepath.Join(dir, ".ruby-version"), []byte("ruby-3.3.0\n"), 0644)
+		if got := strings.Join(gemfileRubyRequirement(dir), "|"); got != tt.want {
+			t.Errorf("gemfileRubyRequirement(%q) = %q, want %q", tt.gemfile, got, tt.want)
+		}
+	}
+}
+
+func TestCheckGemsInstalled(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, ".bundle"), 0755)
+	os.WriteFile(filepath.Join(dir, ".bundle", "config"), []byte("---\nBUNDLE_PATH: \"vendor/bundle\"\n"), 0644)
+
+	gems := filepath.Join(dir, "vendor", "bundle", "ruby", "3.2.0")
+	os.MkdirAll(filepath.Join(gems, "specifications"), 0755)
+	os.MkdirAll(filepath.Join(gems, "bundler", "gems", "rails-0123456789ab"), 0755)
+	for _, name := range []string{"nokogiri-1.15.4-x86_64-linux", "racc-1.7.1"} {
+		os.WriteFile(filepath.Join(gems, "specifications", name+".gemspec"), nil, 0644)
+	}
+	t.Setenv("BUNDLE_PATH", "")
+
+	issues := checkGemsInstalled(dir, parseGemLockfile(testGemfileLock))
+	if len(issues) != 1 {
+		t.Fatalf("checkGemsInstalled() returned %d issues, want 1: %v", len(issues), issues)
+	}
+	want := "1 gems from Gemfile.lock are not installed: rake 13.0.6"
+	if issues[0].Severity != SeverityError || issues[0].Message != want {
+		t.Errorf("issue = %v %q, want %q", issues[0].Severity, issues[0].Message, want)
+	}
+}
+
+func TestInstalledRubyVersionRunsFromProject(t *testing.T) {
+	installDirShims(t, "3.3.0", map[string]string{"ruby": "ruby $v (2023-03-30 revision e51014f9c0) [x86_64-linux]"})
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, ".shim-version"), []byte("3.2.2p53\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got := installedRubyVersion(dir); got != "3.2.2" {
+		t.Errorf("installedRubyVersion() = %q, want the ruby the project selects", got)
+	}
+}
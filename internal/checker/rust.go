@@ -0,0 +1,322 @@
+package checker
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/Sw3bbl3/devdoctor/internal/envcheck"
+	"github.com/Sw3bbl3/devdoctor/internal/semver"
+	"github.com/Sw3bbl3/devdoctor/internal/toml"
+)
+
+// rustToolchainFile is the toolchain requested by rust-toolchain.toml or
+// the legacy rust-toolchain file
+type rustToolchainFile struct {
+	File       string
+	Channel    string
+	Components []string
+	Targets    []string
+}
+
+// readRustToolchain reads rust-toolchain.toml, or rust-toolchain which is
+// either TOML or just the channel name
+func readRustToolchain(path string) *rustToolchainFile {
+	for _, name := range []string{"rust-toolchain.toml", "rust-toolchain"} {
+		data, err := os.ReadFile(filepath.Join(path, name))
+		if err != nil {
+			continue
+		}
+		tc := &rustToolchainFile{File: name}
+		if doc, err := toml.Decode(data); err == nil && toml.Table(doc, "toolchain") != nil {
+			tc.Channel = toml.String(doc, "toolchain", "channel")
+			tc.Components = toml.Strings(doc, "toolchain", "components")
+			tc.Targets = toml.Strings(doc, "toolchain", "targets")
+		} else {
+			tc.Channel = strings.TrimSpace(firstLine(string(data)))
+		}
+		return tc
+	}
+	return nil
+}
+
+// firstLine returns s up to the first newline
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+	return line
+}
+
+// rustupHome returns RUSTUP_HOME or ~/.rustup
+func rustupHome() string {
+	if home := os.Getenv("RUSTUP_HOME"); home != "" {
+		return home
+	}
+	return userHomePath(".rustup")
+}
+
+// rustHostTriple returns rustup's default host triple, falling back to the
+// triple of the platform devdoctor runs on
+func rustHostTriple(home string) string {
+	if data, err := os.ReadFile(filepath.Join(home, "settings.toml")); err == nil {
+		if doc, err := toml.Decode(data); err == nil {
+			if host := toml.String(doc, "default_host_triple"); host != "" {
+				return host
+			}
+		}
+	}
+	arch := map[string]string{"amd64": "x86_64", "arm64": "aarch64", "386": "i686"}[runtime.GOARCH]
+	if arch == "" {
+		arch = runtime.GOARCH
+	}
+	switch runtime.GOOS {
+	case "darwin":
+		return arch + "-apple-darwin"
+	case "windows":
+		return arch + "-pc-windows-msvc"
+	}
+	return arch + "-unknown-" + runtime.GOOS + "-gnu"
+}
+
+// rustupToolchainDir returns the directory of an installed toolchain, or ""
+// if it is not installed. Linked custom toolchains have no host suffix.
+func rustupToolchainDir(home, channel, host string) string {
+	for _, name := range []string{channel + "-" + host, channel} {
+		dir := filepath.Join(home, "toolchains", name)
+		if pathExists(dir) {
+			return dir
+		}
+	}
+	return ""
+}
+
+// rustupComponents returns the components and targets installed in a
+// toolchain, read from lib/rustlib/multirust-config.toml
+func rustupComponents(toolchain, host string) (components, targets map[string]bool) {
+	components, targets = map[string]bool{}, map[string]bool{host: true}
+	data, err := os.ReadFile(filepath.Join(toolchain, "lib", "rustlib", "multirust-config.toml"))
+	if err != nil {
+		return components, targets
+	}
+	doc, err := toml.Decode(data)
+	if err != nil {
+		return components, targets
+	}
+	for _, c := range toml.Tables(doc, "components") {
+		pkg, _ := c["pkg"].(string)
+		target, _ := c["target"].(string)
+		pkg = strings.TrimSuffix(pkg, "-preview")
+		components[pkg] = true
+		if pkg == "rust-std" && target != "" {
+			targets[target] = true
+		}
+	}
+	return components, targets
+}
+
+// rustupToolchainVersion returns the rustc version of an installed toolchain
+// from its channel manifest, without running rustc
+func rustupToolchainVersion(toolchain string) string {
+	f, err := os.Open(filepath.Join(toolchain, "lib", "rustlib", "multirust-channel-manifest.toml"))
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	inRustc := false
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "[") {
+			if inRustc {
+				return ""
+			}
+			inRustc = line == "[pkg.rustc]"
+			continue
+		}
+		if value, ok := strings.CutPrefix(line, "version = "); ok && inRustc {
+			if fields := strings.Fields(strings.Trim(value, `"`)); len(fields) > 0 {
+				return fields[0]
+			}
+			return ""
+		}
+	}
+	return ""
+}
+
+// checkRustToolchain checks that the toolchain, components and targets
+// requested by the toolchain file are installed. It returns the rustc
+// version that builds the project, or "" if it is unknown, and whether the
+// requested toolchain is missing.
+func checkRustToolchain(tc *rustToolchainFile, home string) ([]Issue, string, bool) {
+	issues := []Issue{}
+	host := rustHostTriple(home)
+
+	// A system rustc without rustup ignores the toolchain file; the version
+	// pin check, which runs rustc from the project directory, covers that
+	// case
+	if tc.Channel == "" || !pathExists(filepath.Join(home, "toolchains")) {
+		return issues, "", false
+	}
+	dir := rustupToolchainDir(home, tc.Channel, host)
+	if dir == "" {
+		issues = append(issues, Issue{
+			Severity:    SeverityWarning,
+			ProjectType: "Rust",
+			Message:     fmt.Sprintf("Toolchain %s from %s is not installed", tc.Channel, tc.File),
+			Suggestion:  fmt.Sprintf("Run 'rustup toolchain install %s' (rustup also installs it on the first cargo command)", tc.Channel),
+		})
+		return issues, "", true
+	}
+
+	components, targets := rustupComponents(dir, host)
+	missingComponents := []string{}
+	for _, c := range tc.Components {
+		if !components[strings.TrimSuffix(c, "-preview")] {
+			missingComponents = append(missingComponents, c)
+		}
+	}
+	if len(missingComponents) > 0 {
+		issues = append(issues, Issue{
+			Severity:    SeverityError,
+			ProjectType: "Rust",
+			Message:     fmt.Sprintf("Toolchain %s is missing components from %s: %s", tc.Channel, tc.File, strings.Join(missingComponents, ", ")),
+			Suggestion:  fmt.Sprintf("Run 'rustup component add --toolchain %s %s'", tc.Channel, strings.Join(missingComponents, " ")),
+		})
+	}
+	missingTargets := []string{}
+	for _, t := range tc.Targets {
+		if !targets[t] {
+			missingTargets = append(missingTargets, t)
+		}
+	}
+	if len(missingTargets) > 0 {
+		issues = append(issues, Issue{
+			Severity:    SeverityError,
+			ProjectType: "Rust",
+			Message:     fmt.Sprintf("Toolchain %s is missing targets from %s: %s", tc.Channel, tc.File, strings.Join(missingTargets, ", ")),
+			Suggestion:  fmt.Sprintf("Run 'rustup target add --toolchain %s %s'", tc.Channel, strings.Join(missingTargets, " ")),
+		})
+	}
+
+	return issues, rustupToolchainVersion(dir), false
+}
+
+// rustCrate is a package of a Cargo workspace
+type rustCrate struct {
+	Name        string
+	RustVersion string
+}
+
+// cargoCrates returns the package in Cargo.toml and the members of its
+// workspace. rust-version.workspace = true inherits [workspace.package].
+func cargoCrates(path string) []rustCrate {
+	data, err := os.ReadFile(filepath.Join(path, "Cargo.toml"))
+	if err != nil {
+		return nil
+	}
+	root, err := toml.Decode(data)
+	if err != nil {
+		return nil
+	}
+	inherited := toml.String(root, "workspace", "package", "rust-version")
+
+	crates := []rustCrate{}
+	add := func(doc map[string]interface{}) {
+		name := toml.String(doc, "package", "name")
+		if name == "" {
+			return
+		}
+		crate := rustCrate{Name: name, RustVersion: toml.String(doc, "package", "rust-version")}
+		if inherit, ok := toml.Lookup(doc, "package", "rust-version", "workspace").(bool); ok && inherit {
+			crate.RustVersion = inherited
+		}
+		crates = append(crates, crate)
+	}
+	add(root)
+
+	excluded := map[string]bool{}
+	for _, pattern := range toml.Strings(root, "workspace", "exclude") {
+		excluded[filepath.Clean(filepath.Join(path, pattern))] = true
+	}
+	for _, pattern := range toml.Strings(root, "workspace", "members") {
+		matches, _ := filepath.Glob(filepath.Join(path, pattern))
+		for _, dir := range matches {
+			if excluded[filepath.Clean(dir)] || filepath.Clean(dir) == filepath.Clean(path) {
+				continue
+			}
+			data, err := os.ReadFile(filepath.Join(dir, "Cargo.toml"))
+			if err != nil {
+				continue
+			}
+			if doc, err := toml.Decode(data); err == nil {
+				add(doc)
+			}
+		}
+	}
+	return crates
+}
+
+// checkRustVersion reports crates whose rust-version (MSRV) is newer than
+// the rustc that builds them
+func checkRustVersion(crates []rustCrate, rustc, source string) []Issue {
+	issues := []Issue{}
+	installed, err := semver.Parse(rustc)
+	if err != nil {
+		return issues
+	}
+	// Nightlies and betas of a release have its features
+	installed.Prerelease = ""
+
+	newer := []string{}
+	for _, crate := range crates {
+		if crate.RustVersion == "" {
+			continue
+		}
+		msrv, err := semver.Parse(crate.RustVersion)
+		if err == nil && semver.Compare(installed, msrv) < 0 {
+			newer = append(newer, fmt.Sprintf("%s (%s)", crate.Name, crate.RustVersion))
+		}
+	}
+	if len(newer) > 0 {
+		sort.Strings(newer)
+		issues = append(issues, Issue{
+			Severity:    SeverityError,
+			ProjectType: "Rust",
+			Message:     fmt.Sprintf("%d crates need a newer Rust than %s (%s): %s", len(newer), rustc, source, strings.Join(newer, ", ")),
+			Suggestion:  "Run 'rustup update', or raise the channel in rust-toolchain.toml",
+		})
+	}
+	return issues
+}
+
+// checkRustSetup runs the toolchain and MSRV checks for a Cargo project
+func checkRustSetup(path string) []Issue {
+	issues := []Issue{}
+
+	rustc, source := "", "rustc in the project directory"
+	if tc := readRustToolchain(path); tc != nil {
+		tcIssues, version, missing := checkRustToolchain(tc, rustupHome())
+		issues = append(issues, tcIssues...)
+		// Whatever rustc runs now is not the one that will build the
+		// project once the toolchain is installed
+		if missing {
+			return issues
+		}
+		if version != "" {
+			rustc, source = version, "toolchain "+tc.Channel
+		}
+	}
+	if rustc == "" {
+		status := envcheck.LookupIn(path, "rustc")
+		if !status.Found {
+			return issues
+		}
+		rustc = status.Version
+	}
+
+	return append(issues, checkRustVersion(cargoCrates(path), rustc, source)...)
+}
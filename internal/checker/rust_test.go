@@ -0,0 +1,158 @@
+package checker
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// makeRustup creates a rustup home with one toolchain holding the given
+// components, targets and rustc version
+func makeRustup(t *testing.T, toolchain, version string, components, targets []string) string {
+	t.Helper()
+	home := t.TempDir()
+	os.WriteFile(filepath.Join(home, "settings.toml"), []byte("default_host_triple = \"x86_64-unknown-linux-gnu\"\n"), 0644)
+
+	rustlib := filepath.Join(home, "toolchains", toolchain+"-x86_64-unknown-linux-gnu", "lib", "rustlib")
+	os.MkdirAll(rustlib, 0755)
+	config := "config_version = \"1\"\n"
+	for _, c := range components {
+		config += "\n[[components]]\npkg = \"" + c + "\"\ntarget = \"x86_64-unknown-linux-gnu\"\n"
+	}
+	for _, target := range targets {
+		config += "\n[[components]]\npkg = \"rust-std\"\ntarget = \"" + target + "\"\n"
+	}
+	os.WriteFile(filepath.Join(rustlib, "multirust-config.toml"), []byte(config), 0644)
+	manifest := "manifest-version = \"2\"\n\n[pkg.cargo]\nversion = \"0.1.0\"\n\n[pkg.rustc]\nversion = \"" + version + " (1159e78c4 2025-09-14)\"\n\n[pkg.rustc.target.x86_64-unknown-linux-gnu]\navailable = true\n"
+	os.WriteFile(filepath.Join(rustlib, "multirust-channel-manifest.toml"), []byte(manifest), 0644)
+	return home
+}
+
+func TestCheckRustToolchain(t *testing.T) {
+	home := makeRustup(t, "1.75.0", "1.75.0", []string{"cargo", "rustc", "clippy-preview"}, []string{"x86_64-unknown-linux-gnu"})
+
+	tc := &rustToolchainFile{
+		File:       "rust-toolchain.toml",
+		Channel:    "1.75.0",
+		Components: []string{"clippy", "rustfmt"},
+		Targets:    []string{"x86_64-unknown-linux-gnu", "wasm32-unknown-unknown"},
+	}
+	issues, version, missing := checkRustToolchain(tc, home)
+	if version != "1.75.0" || missing {
+		t.Errorf("version = %q, want 1.75.0", version)
+	}
+	messages := []string{}
+	for _, issue := range issues {
+		messages = append(messages, issue.Message)
+	}
+	want := []string{
+		"Toolchain 1.75.0 is missing components from rust-toolchain.toml: rustfmt",
+		"Toolchain 1.75.0 is missing targets from rust-toolchain.toml: wasm32-unknown-unknown",
+	}
+	if strings.Join(messages, "|") != strings.Join(want, "|") {
+		t.Errorf("issues = %q, want %q", messages, want)
+	}
+
+	tc.Channel = "nightly-2024-01-01"
+	issues, _, missing = checkRustToolchain(tc, home)
+	if !missing || len(issues) != 1 || issues[0].Severity != SeverityWarning || !strings.Contains(issues[0].Message, "is not installed") {
+		t.Errorf("expected a not installed warning, got %v", issues)
+	}
+}
+
+func TestReadRustToolchainLegacy(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "rust-toolchain"), []byte("nightly-2024-01-01\n"), 0644)
+	tc := readRustToolchain(dir)
+	if tc == nil || tc.Channel != "nightly-2024-01-01" || tc.File != "rust-toolchain" {
+		t.Errorf("readRustToolchain() = %+v", tc)
+	}
+}
+
+func TestCargoCratesWorkspace(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "Cargo.toml"), []byte(`[workspace]
+members = ["crates/*"]
+exclude = ["crates/experimental"]
+
+[workspace.package]
+rust-version = "1.74"
+`), 0644)
+	for name, manifest := range map[string]string{
+		"core":         "[package]\nname = \"core\"\nrust-version.workspace = true\n",
+		"cli":          "[package]\nname = \"cli\"\nrust-version = \"1.80\"\n",
+		"experimental": "[package]\nname = \"experimental\"\nrust-version = \"1.90\"\n",
+	} {
+		os.MkdirAll(filepath.Join(dir, "crates", name), 0755)
+		os.WriteFile(filepath.Join(dir, "crates", name, "Cargo.toml"), []byte(manifest), 0644)
+	}
+
+	crates := cargoCrates(dir)
+	if len(crates) != 2 {
+		t.Fatalf("cargoCrates() = %v, want cli and core", crates)
+	}
+
+	issues := checkRustVersion(crates, "1.76.0", "rustc in the project directory")
+	if len(issues) != 1 {
+		t.Fatalf("checkRustVersion() returned %d issues, want 1: %v", len(issues), issues)
+	}
+	want := "1 crates need a newer Rust than 1.76.0 (rustc in the project directory): cli (1.80)"
+	if issues[0].Message != want {
+		t.Errorf("message = %q, want %q", issues[0].Message, want)
+	}
+	if issues := checkRustVersion(crates, "1.80.0-nightly", "toolchain nightly"); len(issues) != 0 {
+		t.Errorf("a 1.80 nightly should satisfy rust-version 1.80, got %v", issues)
+	}
+}
+
+func TestRustupToolchainVersionEmpty(t *testing.T) {
+	toolchain := t.TempDir()
+	rustlib := filepath.Join(toolchain, "lib", "rustlib")
+	if err := os.MkdirAll(rustlib, 0755); err != nil {
+		t.Fatal(err)
+	}
+	manifest := "manifest-version = \"2\"\n\n[pkg.rustc]\nversion = \"\"\n"
+	if err := os.WriteFile(filepath.Join(rustlib, "multirust-channel-manifest.toml"), []byte(manifest), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got := rustupToolchainVersion(toolchain); got != "" {
+		t.Errorf("rustupToolchainVersion() with an empty version = %q", got)
+	}
+}
+
+func TestCheckRustSetupRunsFromProject(t *testing.T) {
+	installDirShims(t, "1.82.0", map[string]string{"rustc": "rustc $v (f6e511eec 2024-10-15)"})
+	t.Setenv("RUSTUP_HOME", t.TempDir())
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"Cargo.toml":    "[package]\nname = \"app\"\nrust-version = \"1.80\"\n",
+		".shim-version": "1.75.0\n",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	issues := checkRustSetup(dir)
+	if len(issues) != 1 || !strings.Contains(issues[0].Message, "newer Rust than 1.75.0") {
+		t.Errorf("checkRustSetup() = %v, want the rustc the project selects", issues)
+	}
+}
+
+func TestCheckRustSetupMissingToolchain(t *testing.T) {
+	installDirShims(t, "1.70.0", map[string]string{"rustc": "rustc $v (90b35a623 2023-05-31)"})
+	t.Setenv("RUSTUP_HOME", makeRustup(t, "stable", "1.82.0", nil, nil))
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"Cargo.toml":     "[package]\nname = \"app\"\nrust-version = \"1.80\"\n",
+		"rust-toolchain": "1.81.0\n",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	issues := checkRustSetup(dir)
+	if len(issues) != 1 || !strings.Contains(issues[0].Message, "is not installed") {
+		t.Errorf("checkRustSetup() = %v, want only the missing toolchain", issues)
+	}
+}
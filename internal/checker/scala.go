@@ -0,0 +1,139 @@
+package checker
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/Sw3bbl3/devdoctor/internal/semver"
+)
+
+// scalaJDKSupport lists, for each LTS JDK, the first sbt release and the
+// first release of each Scala series that run on it
+// (https://docs.scala-lang.org/overviews/jdk-compatibility/overview.html)
+var scalaJDKSupport = []struct {
+	JDK   int
+	Sbt   string
+	Scala map[string]string
+}{
+	{11, "1.1.0", map[string]string{"2.11": "2.11.12", "2.12": "2.12.4", "2.13": "2.13.0", "3": "3.0.0"}},
+	{17, "1.5.5", map[string]string{"2.12": "2.12.15", "2.13": "2.13.6", "3": "3.0.0"}},
+	{21, "1.9.0", map[string]string{"2.12": "2.12.18", "2.13": "2.13.11", "3": "3.3.1"}},
+}
+
+var (
+	sbtScalaVersionRe = regexp.MustCompile(`\bscalaVersion\s*:=\s*(?:"([^"]+)"|([A-Za-z_][A-Za-z0-9_]*))`)
+	sbtValRe          = regexp.MustCompile(`\bval\s+([A-Za-z_][A-Za-z0-9_]*)\s*=\s*"([^"]+)"`)
+)
+
+// sbtVersion returns sbt.version from project/build.properties
+func sbtVersion(path string) string {
+	data, err := os.ReadFile(filepath.Join(path, "project", "build.properties"))
+	if err != nil {
+		return ""
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		key, value, ok := strings.Cut(line, "=")
+		if ok && strings.TrimSpace(key) == "sbt.version" {
+			return strings.TrimSpace(value)
+		}
+	}
+	return ""
+}
+
+// buildScalaVersion returns the scalaVersion set in build.sbt, resolving a
+// val such as scala3Version defined in the same file
+func buildScalaVersion(path string) string {
+	data, err := os.ReadFile(filepath.Join(path, "build.sbt"))
+	if err != nil {
+		return ""
+	}
+	m := sbtScalaVersionRe.FindStringSubmatch(string(data))
+	if m == nil {
+		return ""
+	}
+	if m[1] != "" {
+		return m[1]
+	}
+	for _, val := range sbtValRe.FindAllStringSubmatch(string(data), -1) {
+		if val[1] == m[2] {
+			return val[2]
+		}
+	}
+	return ""
+}
+
+// scalaSeries returns the series a Scala version belongs to: "2.13" for
+// 2.13.12 and "3" for any Scala 3 release
+func scalaSeries(version string) string {
+	if strings.HasPrefix(version, "3.") {
+		return "3"
+	}
+	parts := strings.SplitN(version, ".", 3)
+	if len(parts) < 2 {
+		return ""
+	}
+	return parts[0] + "." + parts[1]
+}
+
+// checkScalaJDK checks that sbt and the Scala compiler of the build can run
+// on the JDK
+func checkScalaJDK(path string, jdk int, jdkSource string) []Issue {
+	issues := []Issue{}
+	if jdk == 0 {
+		return issues
+	}
+	// The newest LTS JDK that is not newer than the installed one
+	support := -1
+	for i, entry := range scalaJDKSupport {
+		if entry.JDK <= jdk {
+			support = i
+		}
+	}
+	if support < 0 {
+		return issues
+	}
+	entry := scalaJDKSupport[support]
+
+	if sbt := sbtVersion(path); sbt != "" {
+		v, err1 := semver.Parse(sbt)
+		min, err2 := semver.Parse(entry.Sbt)
+		if err1 == nil && err2 == nil && semver.Compare(v, min) < 0 {
+			issues = append(issues, Issue{
+				Severity:    SeverityError,
+				ProjectType: "Scala",
+				Message:     fmt.Sprintf("sbt %s (project/build.properties) cannot run on JDK %d (from %s); JDK %d needs sbt %s or later", sbt, jdk, jdkSource, entry.JDK, entry.Sbt),
+				Suggestion:  fmt.Sprintf("Set sbt.version=%s or later in project/build.properties, or point JAVA_HOME at an older JDK", entry.Sbt),
+			})
+		}
+	}
+
+	scala := buildScalaVersion(path)
+	series := scalaSeries(scala)
+	if series == "" {
+		return issues
+	}
+	required, known := entry.Scala[series]
+	if !known {
+		issues = append(issues, Issue{
+			Severity:    SeverityError,
+			ProjectType: "Scala",
+			Message:     fmt.Sprintf("Scala %s (build.sbt) does not support JDK %d (from %s)", scala, jdk, jdkSource),
+			Suggestion:  "Upgrade to a newer Scala series, or point JAVA_HOME at an older JDK",
+		})
+		return issues
+	}
+	v, err1 := semver.Parse(scala)
+	min, err2 := semver.Parse(required)
+	if err1 == nil && err2 == nil && semver.Compare(v, min) < 0 {
+		issues = append(issues, Issue{
+			Severity:    SeverityError,
+			ProjectType: "Scala",
+			Message:     fmt.Sprintf("Scala %s (build.sbt) does not support JDK %d (from %s); JDK %d needs Scala %s or later", scala, jdk, jdkSource, entry.JDK, required),
+			Suggestion:  fmt.Sprintf("Set scalaVersion to %s or later, or point JAVA_HOME at an older JDK", required),
+		})
+	}
+	return issues
+}
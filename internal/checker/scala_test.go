@@ -0,0 +1,63 @@
+package checker
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBuildScalaVersion(t *testing.T) {
+	tests := []struct {
+		build string
+		want  string
+	}{
+		{`ThisBuild / scalaVersion := "2.13.12"`, "2.13.12"},
+		{"val scala3Version = \"3.3.1\"\n\nlazy val root = project\n  .settings(\n    scalaVersion := scala3Version\n  )\n", "3.3.1"},
+		{`name := "app"`, ""},
+	}
+	for _, tt := range tests {
+		dir := t.TempDir()
+		os.WriteFile(filepath.Join(dir, "build.sbt"), []byte(tt.build), 0644)
+		if got := buildScalaVersion(dir); got != tt.want {
+			t.Errorf("buildScalaVersion(%q) = %q, want %q", tt.build, got, tt.want)
+		}
+	}
+}
+
+func TestCheckScalaJDK(t *testing.T) {
+	tests := []struct {
+		name  string
+		sbt   string
+		scala string
+		jdk   int
+		want  []string
+	}{
+		{"supported", "1.9.7", "2.13.12", 21, nil},
+		{"old JDK", "1.3.13", "2.12.10", 8, nil},
+		{"between LTS releases", "1.5.5", "2.13.6", 19, nil},
+		{"sbt and Scala too old", "1.4.9", "2.13.5", 17, []string{
+			"sbt 1.4.9 (project/build.properties) cannot run on JDK 17 (from JAVA_HOME); JDK 17 needs sbt 1.5.5 or later",
+			"Scala 2.13.5 (build.sbt) does not support JDK 17 (from JAVA_HOME); JDK 17 needs Scala 2.13.6 or later",
+		}},
+		{"unsupported series", "1.9.7", "2.11.12", 21, []string{
+			"Scala 2.11.12 (build.sbt) does not support JDK 21 (from JAVA_HOME)",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			os.MkdirAll(filepath.Join(dir, "project"), 0755)
+			os.WriteFile(filepath.Join(dir, "project", "build.properties"), []byte("sbt.version="+tt.sbt+"\n"), 0644)
+			os.WriteFile(filepath.Join(dir, "build.sbt"), []byte(`scalaVersion := "`+tt.scala+`"`), 0644)
+
+			got := []string{}
+			for _, issue := range checkScalaJDK(dir, tt.jdk, "JAVA_HOME") {
+				got = append(got, issue.Message)
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("checkScalaJDK() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
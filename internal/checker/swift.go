@@ -0,0 +1,175 @@
+package checker
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/Sw3bbl3/devdoctor/internal/semver"
+)
+
+// swiftPin is a dependency pinned in Package.resolved
+type swiftPin struct {
+	Identity string
+	Version  string
+	Revision string
+}
+
+// swiftIdentity derives a package identity from its URL the way SwiftPM
+// does: the last path component, lower-cased, without .git
+func swiftIdentity(location string) string {
+	location = strings.TrimSuffix(strings.TrimSuffix(location, "/"), ".git")
+	if i := strings.LastIndexAny(location, "/:"); i >= 0 {
+		location = location[i+1:]
+	}
+	return strings.ToLower(location)
+}
+
+// parsePackageResolved parses Package.resolved in the version 1 format of
+// Swift 5.5 and earlier and the version 2 and 3 formats that followed
+func parsePackageResolved(data []byte) ([]swiftPin, error) {
+	type state struct {
+		Version  string `json:"version"`
+		Revision string `json:"revision"`
+	}
+	var resolved struct {
+		Version int `json:"version"`
+		Object  struct {
+			Pins []struct {
+				RepositoryURL string `json:"repositoryURL"`
+				State         state  `json:"state"`
+			} `json:"pins"`
+		} `json:"object"`
+		Pins []struct {
+			Identity string `json:"identity"`
+			State    state  `json:"state"`
+		} `json:"pins"`
+	}
+	if err := json.Unmarshal(data, &resolved); err != nil {
+		return nil, err
+	}
+	pins := []swiftPin{}
+	if resolved.Version == 1 {
+		for _, pin := range resolved.Object.Pins {
+			pins = append(pins, swiftPin{Identity: swiftIdentity(pin.RepositoryURL), Version: pin.State.Version, Revision: pin.State.Revision})
+		}
+		return pins, nil
+	}
+	for _, pin := range resolved.Pins {
+		pins = append(pins, swiftPin{Identity: strings.ToLower(pin.Identity), Version: pin.State.Version, Revision: pin.State.Revision})
+	}
+	return pins, nil
+}
+
+// swiftCheckouts reads the revision SwiftPM has checked out for each
+// dependency from .build/workspace-state.json
+func swiftCheckouts(path string) (map[string]string, bool) {
+	data, err := os.ReadFile(filepath.Join(path, ".build", "workspace-state.json"))
+	if err != nil {
+		return nil, false
+	}
+	var state struct {
+		Object struct {
+			Dependencies []struct {
+				PackageRef struct {
+					Identity string `json:"identity"`
+				} `json:"packageRef"`
+				State struct {
+					CheckoutState struct {
+						Revision string `json:"revision"`
+					} `json:"checkoutState"`
+				} `json:"state"`
+			} `json:"dependencies"`
+		} `json:"object"`
+	}
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, false
+	}
+	checkouts := map[string]string{}
+	for _, dep := range state.Object.Dependencies {
+		checkouts[strings.ToLower(dep.PackageRef.Identity)] = dep.State.CheckoutState.Revision
+	}
+	return checkouts, true
+}
+
+var (
+	swiftToolsVersionRe = regexp.MustCompile(`^//\s*swift-tools-version\s*:\s*(\d+(?:\.\d+){0,2})`)
+	swiftVersionRe      = regexp.MustCompile(`Swift version (\d+(?:\.\d+)+)`)
+)
+
+// installedSwiftVersion returns the version of the swift compiler the
+// project directory selects. Apple's toolchains print a swift-driver version
+// first, so envcheck's generic parser does not apply.
+func installedSwiftVersion(path string) string {
+	cmd := exec.Command("swift", "--version")
+	cmd.Dir = path
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return ""
+	}
+	if m := swiftVersionRe.FindStringSubmatch(string(out)); m != nil {
+		return m[1]
+	}
+	return ""
+}
+
+// checkSwiftToolsVersion compares the swift-tools-version of Package.swift
+// with the installed compiler
+func checkSwiftToolsVersion(path, swift string) []Issue {
+	issues := []Issue{}
+	data, err := os.ReadFile(filepath.Join(path, "Package.swift"))
+	if err != nil || swift == "" {
+		return issues
+	}
+	m := swiftToolsVersionRe.FindStringSubmatch(firstLine(string(data)))
+	if m == nil {
+		return issues
+	}
+	required, err1 := semver.Parse(m[1])
+	installed, err2 := semver.Parse(swift)
+	if err1 == nil && err2 == nil && semver.Compare(installed, required) < 0 {
+		issues = append(issues, Issue{
+			Severity:    SeverityError,
+			ProjectType: "Swift",
+			Message:     fmt.Sprintf("Package.swift needs swift-tools-version %s but Swift %s is installed", m[1], swift),
+			Suggestion:  fmt.Sprintf("Install Swift %s or later (e.g. with swiftly or a newer Xcode)", m[1]),
+		})
+	}
+	return issues
+}
+
+// checkSwiftPins compares Package.resolved with the checkouts in .build
+func checkSwiftPins(path string, pins []swiftPin) []Issue {
+	issues := []Issue{}
+	checkouts, ok := swiftCheckouts(path)
+	if !ok || len(pins) == 0 {
+		return issues
+	}
+	stale := []string{}
+	for _, pin := range pins {
+		revision, found := checkouts[pin.Identity]
+		switch {
+		case !found:
+			stale = append(stale, pin.Identity)
+		case pin.Revision != "" && revision != pin.Revision:
+			label := pin.Revision
+			if pin.Version != "" {
+				label = pin.Version
+			}
+			stale = append(stale, fmt.Sprintf("%s (pinned at %s)", pin.Identity, label))
+		}
+	}
+	if len(stale) > 0 {
+		issues = append(issues, Issue{
+			Severity:    SeverityWarning,
+			ProjectType: "Swift",
+			Message:     fmt.Sprintf("%d packages in Package.resolved are not checked out at the pinned revision: %s", len(stale), strings.Join(stale, ", ")),
+			Suggestion:  "Run 'swift package resolve' to check out the pinned versions",
+		})
+	}
+	return issues
+}
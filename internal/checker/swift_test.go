@@ -0,0 +1,108 @@
+package checker
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParsePackageResolved(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want []swiftPin
+	}{
+		{"version 1", `{
+  "object": {
+    "pins": [
+      {"package": "Alamofire", "repositoryURL": "https://github.com/Alamofire/Alamofire.git", "state": {"branch": null, "revision": "3dc6a42", "version": "5.8.1"}}
+    ]
+  },
+  "version": 1
+}`, []swiftPin{{Identity: "alamofire", Version: "5.8.1", Revision: "3dc6a42"}}},
+		{"version 2", `{
+  "pins": [
+    {"identity": "swift-argument-parser", "kind": "remoteSourceControl", "location": "https://github.com/apple/swift-argument-parser", "state": {"revision": "c8ed701", "version": "1.3.0"}},
+    {"identity": "swift-nio", "kind": "remoteSourceControl", "location": "https://github.com/apple/swift-nio.git", "state": {"branch": "main", "revision": "a1b2c3d"}}
+  ],
+  "version": 2
+}`, []swiftPin{
+			{Identity: "swift-argument-parser", Version: "1.3.0", Revision: "c8ed701"},
+			{Identity: "swift-nio", Revision: "a1b2c3d"},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pins, err := parsePackageResolved([]byte(tt.data))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(pins) != len(tt.want) {
+				t.Fatalf("parsePackageResolved() = %v, want %v", pins, tt.want)
+			}
+			for i := range tt.want {
+				if pins[i] != tt.want[i] {
+					t.Errorf("parsePackageResolved()[%d] = %v, want %v", i, pins[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestCheckSwiftToolsVersion(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "Package.swift"), []byte("// swift-tools-version:5.9\nimport PackageDescription\n"), 0644)
+
+	if issues := checkSwiftToolsVersion(dir, "5.10"); len(issues) != 0 {
+		t.Errorf("checkSwiftToolsVersion() with Swift 5.10 = %v, want none", issues)
+	}
+	issues := checkSwiftToolsVersion(dir, "5.8.1")
+	want := "Package.swift needs swift-tools-version 5.9 but Swift 5.8.1 is installed"
+	if len(issues) != 1 || issues[0].Message != want {
+		t.Errorf("checkSwiftToolsVersion() = %v, want %q", issues, want)
+	}
+}
+
+func TestCheckSwiftPins(t *testing.T) {
+	dir := t.TempDir()
+	pins := []swiftPin{
+		{Identity: "alamofire", Version: "5.8.1", Revision: "3dc6a42"},
+		{Identity: "swift-log", Version: "1.5.3", Revision: "532d8b5"},
+		{Identity: "swift-nio", Revision: "a1b2c3d"},
+	}
+	if issues := checkSwiftPins(dir, pins); len(issues) != 0 {
+		t.Errorf("checkSwiftPins() without .build = %v, want none", issues)
+	}
+
+	os.MkdirAll(filepath.Join(dir, ".build"), 0755)
+	os.WriteFile(filepath.Join(dir, ".build", "workspace-state.json"), []byte(`{
+  "object": {
+    "dependencies": [
+      {"packageRef": {"identity": "alamofire", "kind": "remoteSourceControl"}, "state": {"checkoutState": {"revision": "3dc6a42", "version": "5.8.1"}, "name": "sourceControlCheckout"}, "subpath": "Alamofire"},
+      {"packageRef": {"identity": "swift-log", "kind": "remoteSourceControl"}, "state": {"checkoutState": {"revision": "e97a6fc", "version": "1.5.2"}, "name": "sourceControlCheckout"}, "subpath": "swift-log"}
+    ]
+  },
+  "version": 6
+}`), 0644)
+
+	got := []string{}
+	for _, issue := range checkSwiftPins(dir, pins) {
+		got = append(got, issue.Message)
+	}
+	want := "2 packages in Package.resolved are not checked out at the pinned revision: swift-log (pinned at 1.5.3), swift-nio"
+	if strings.Join(got, "\n") != want {
+		t.Errorf("checkSwiftPins() = %q, want %q", got, want)
+	}
+}
+
+func TestInstalledSwiftVersionRunsFromProject(t *testing.T) {
+	installDirShims(t, "5.10", map[string]string{"swift": "Swift version $v (swift-$v-RELEASE)"})
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, ".shim-version"), []byte("5.9.2\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got := installedSwiftVersion(dir); got != "5.9.2" {
+		t.Errorf("installedSwiftVersion() = %q, want the swift the project selects", got)
+	}
+}
@@ -0,0 +1,457 @@
+package checker
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/Sw3bbl3/devdoctor/internal/detector"
+	"github.com/Sw3bbl3/devdoctor/internal/toml"
+)
+
+// syslibsJSON is the catalogue of packages that compile against system
+// libraries, the libraries and tools they need and the distribution
+// packages that provide them
+//
+//go:embed syslibs.json
+var syslibsJSON []byte
+
+// sysLibrary is a system library and how to find its development files
+type sysLibrary struct {
+	Name      string            `json:"name"`
+	PkgConfig []string          `json:"pkgconfig"`
+	Headers   []string          `json:"headers"`
+	Packages  map[string]string `json:"packages"` // package manager -> package
+}
+
+// sysTool is a program a native build runs
+type sysTool struct {
+	Name     string            `json:"name"`
+	Commands []string          `json:"commands"`
+	Packages map[string]string `json:"packages"`
+	Hint     string            `json:"hint"` // for package managers without a package
+}
+
+// sysPackage is a dependency that builds native code
+type sysPackage struct {
+	Libraries []string `json:"libraries"`
+	Tools     []string `json:"tools"`
+	// Prebuilt marks packages that usually install a prebuilt binary, so
+	// the libraries are only needed when none exists for the platform
+	Prebuilt bool `json:"prebuilt"`
+	// Bundled lists locked dependencies of a crate that mean it builds the
+	// library from source
+	Bundled []string `json:"bundled"`
+	// OS lists the GOOS values the package builds on, for packages that
+	// lockfiles list for every platform; empty means all
+	OS []string `json:"os"`
+}
+
+// buildsOn reports whether the package is built on the operating system
+func (p sysPackage) buildsOn(goos string) bool {
+	return len(p.OS) == 0 || containsString(p.OS, goos)
+}
+
+type sysCatalogue struct {
+	Libraries map[string]sysLibrary `json:"libraries"`
+	Tools     map[string]sysTool    `json:"tools"`
+	// Packages maps cargo, pypi, npm and rubygems to their native packages
+	Packages map[string]map[string]sysPackage `json:"packages"`
+}
+
+var syslibs = func() *sysCatalogue {
+	catalogue := &sysCatalogue{}
+	if err := json.Unmarshal(syslibsJSON, catalogue); err != nil {
+		panic(fmt.Sprintf("syslibs.json: %v", err))
+	}
+	return catalogue
+}()
+
+// sysEcosystems maps project types to the catalogue's package registries
+var sysEcosystems = map[string]string{
+	"Rust":    "cargo",
+	"Python":  "pypi",
+	"Node.js": "npm",
+	"Ruby":    "rubygems",
+}
+
+// installCommands are the commands that install packages with each
+// package manager
+var installCommands = map[string]string{
+	"apt":    "sudo apt install",
+	"dnf":    "sudo dnf install",
+	"apk":    "sudo apk add",
+	"pacman": "sudo pacman -S",
+	"zypper": "sudo zypper install",
+	"brew":   "brew install",
+}
+
+// lockedPackage is a package of a lockfile that is in the catalogue
+type lockedPackage struct {
+	Name string
+	File string
+	// Deps are the locked dependencies of Cargo packages
+	Deps []string
+}
+
+// cargoLockPackages returns the catalogued crates of Cargo.lock that build
+// on this platform. Cargo.lock also lists the target-specific dependencies
+// of every other platform.
+func cargoLockPackages(path string, catalogue map[string]sysPackage) []lockedPackage {
+	data, err := os.ReadFile(filepath.Join(path, "Cargo.lock"))
+	if err != nil {
+		return nil
+	}
+	doc, err := toml.Decode(data)
+	if err != nil {
+		return nil
+	}
+	packages := []lockedPackage{}
+	for _, pkg := range toml.Tables(doc, "package") {
+		name := toml.String(pkg, "name")
+		if entry, ok := catalogue[name]; !ok || !entry.buildsOn(runtime.GOOS) {
+			continue
+		}
+		locked := lockedPackage{Name: name, File: "Cargo.lock"}
+		// Dependencies are "name", "name version" or "name version (source)"
+		for _, dep := range toml.Strings(pkg, "dependencies") {
+			if fields := strings.Fields(dep); len(fields) > 0 {
+				locked.Deps = append(locked.Deps, fields[0])
+			}
+		}
+		packages = append(packages, locked)
+	}
+	return packages
+}
+
+// pythonLockPackages returns the catalogued distributions of poetry.lock,
+// uv.lock and requirements.txt that are not installed in the project's
+// environment yet
+func pythonLockPackages(path string, catalogue map[string]sysPackage) []lockedPackage {
+	var installed map[string]string
+	if env := findPythonEnv(path, detector.DetectPythonToolchain(path)); env != "" {
+		installed = installedDistributions(env)
+	}
+
+	packages := []lockedPackage{}
+	seen := map[string]bool{}
+	add := func(name, file string) {
+		name = normalizePyName(name)
+		if _, ok := catalogue[name]; !ok || seen[name] {
+			return
+		}
+		if _, ok := installed[name]; ok {
+			return
+		}
+		seen[name] = true
+		packages = append(packages, lockedPackage{Name: name, File: file})
+	}
+
+	for _, file := range []string{"poetry.lock", "uv.lock"} {
+		data, err := os.ReadFile(filepath.Join(path, file))
+		if err != nil {
+			continue
+		}
+		if doc, err := toml.Decode(data); err == nil {
+			for _, pkg := range toml.Tables(doc, "package") {
+				add(toml.String(pkg, "name"), file)
+			}
+		}
+	}
+	if reqs, err := readRequirements(filepath.Join(path, "requirements.txt"), map[string]bool{}); err == nil {
+		for _, req := range reqs {
+			add(req.Name, "requirements.txt")
+		}
+	}
+	return packages
+}
+
+// npmLockPackages returns the catalogued packages of package-lock.json or
+// npm-shrinkwrap.json that are not installed in node_modules yet
+func npmLockPackages(path string, catalogue map[string]sysPackage) []lockedPackage {
+	packages := []lockedPackage{}
+	for _, file := range nodeLockfiles["npm"] {
+		data, err := os.ReadFile(filepath.Join(path, file))
+		if err != nil {
+			continue
+		}
+		lock, err := parsePackageLock(file, data)
+		if err != nil {
+			continue
+		}
+		seen := map[string]bool{}
+		for installPath := range lock.Installed {
+			name := installPath[strings.LastIndex(installPath, "node_modules/")+len("node_modules/"):]
+			if _, ok := catalogue[name]; !ok || seen[name] || pathExists(filepath.Join(path, filepath.FromSlash(installPath))) {
+				continue
+			}
+			seen[name] = true
+			packages = append(packages, lockedPackage{Name: name, File: file})
+		}
+		sort.Slice(packages, func(i, j int) bool { return packages[i].Name < packages[j].Name })
+		break
+	}
+	return packages
+}
+
+// gemLockPackages returns the catalogued gems of Gemfile.lock that are not
+// installed yet and are not locked as a precompiled gem for this platform
+func gemLockPackages(path string, catalogue map[string]sysPackage) []lockedPackage {
+	data, err := os.ReadFile(filepath.Join(path, "Gemfile.lock"))
+	if err != nil {
+		return nil
+	}
+	specs := []*gemLockSpec{}
+	for _, spec := range parseGemLockfile(string(data)).Specs {
+		if _, ok := catalogue[spec.Name]; ok && !gemPrecompiled(spec) {
+			specs = append(specs, spec)
+		}
+	}
+	if len(specs) == 0 {
+		return nil
+	}
+
+	dirs := gemDirs(path)
+	packages := []lockedPackage{}
+	for _, spec := range specs {
+		if !gemInstalled(spec, dirs) {
+			packages = append(packages, lockedPackage{Name: spec.Name, File: "Gemfile.lock"})
+		}
+	}
+	return packages
+}
+
+// gemPrecompiled reports whether Gemfile.lock locks a precompiled variant
+// of a gem for this platform, such as nokogiri (1.15.4-x86_64-linux)
+func gemPrecompiled(spec *gemLockSpec) bool {
+	arch := map[string]string{"amd64": "x86_64", "arm64": "aarch64"}[runtime.GOARCH]
+	if runtime.GOOS == "darwin" && runtime.GOARCH == "arm64" {
+		arch = "arm64"
+	}
+	for _, platform := range spec.Platforms {
+		if arch != "" && strings.HasPrefix(platform, arch+"-") && strings.Contains(platform, runtime.GOOS) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseOSRelease returns the package manager of the distribution that
+// /etc/os-release describes, from its ID and ID_LIKE fields
+func parseOSRelease(data string) string {
+	ids := []string{}
+	for _, line := range strings.Split(data, "\n") {
+		key, value, ok := strings.Cut(strings.TrimSpace(line), "=")
+		if ok && (key == "ID" || key == "ID_LIKE") {
+			ids = append(ids, strings.Fields(strings.Trim(value, `"'`))...)
+		}
+	}
+	for _, id := range ids {
+		switch id {
+		case "debian", "ubuntu":
+			return "apt"
+		case "fedora", "rhel", "centos":
+			return "dnf"
+		case "alpine":
+			return "apk"
+		case "arch":
+			return "pacman"
+		case "suse", "opensuse":
+			return "zypper"
+		}
+	}
+	return ""
+}
+
+// systemPackageManager returns the package manager install hints are given
+// for, or "" if it is unknown
+func systemPackageManager() string {
+	switch runtime.GOOS {
+	case "darwin":
+		return "brew"
+	case "linux":
+		data, err := os.ReadFile("/etc/os-release")
+		if err != nil {
+			return ""
+		}
+		return parseOSRelease(string(data))
+	}
+	return ""
+}
+
+// sysMissing is a missing library or tool and the packages that need it
+type sysMissing struct {
+	Name     string
+	Packages []string
+	Install  string // distribution package, or "" if there is none
+	Hint     string
+	// Prebuilt is true when every package that needs it is usually
+	// installed from a prebuilt binary
+	Prebuilt bool
+}
+
+// checkSystemDeps reports the libraries and tools the catalogued packages
+// need that are missing. hasCommand looks up programs in PATH and manager
+// is the system package manager install hints are given for.
+func checkSystemDeps(projectType string, packages []lockedPackage, catalogue map[string]sysPackage, env *nativeEnv, hasCommand func(string) bool, manager string) []Issue {
+	issues := []Issue{}
+	missing := map[string]*sysMissing{}
+	order := []string{}
+	files := []string{}
+
+	record := func(key, name string, distro map[string]string, hint, pkg string, prebuilt bool) {
+		m, ok := missing[key]
+		if !ok {
+			m = &sysMissing{Name: name, Install: distro[manager], Hint: hint, Prebuilt: true}
+			missing[key] = m
+			order = append(order, key)
+		}
+		if !containsString(m.Packages, pkg) {
+			m.Packages = append(m.Packages, pkg)
+		}
+		m.Prebuilt = m.Prebuilt && prebuilt
+	}
+
+	for _, pkg := range packages {
+		entry := catalogue[pkg.Name]
+		bundled := false
+		for _, dep := range entry.Bundled {
+			bundled = bundled || containsString(pkg.Deps, dep)
+		}
+		if bundled {
+			continue
+		}
+		if !containsString(files, pkg.File) {
+			files = append(files, pkg.File)
+		}
+
+		for _, key := range entry.Libraries {
+			lib, ok := syslibs.Libraries[key]
+			if !ok || sysLibraryFound(lib, env) {
+				continue
+			}
+			record("library "+key, lib.Name, lib.Packages, "", pkg.Name, entry.Prebuilt)
+		}
+		for _, key := range entry.Tools {
+			tool, ok := syslibs.Tools[key]
+			if !ok {
+				continue
+			}
+			found := false
+			for _, command := range tool.Commands {
+				found = found || hasCommand(command)
+			}
+			if !found {
+				record("tool "+key, tool.Name, tool.Packages, tool.Hint, pkg.Name, entry.Prebuilt)
+			}
+		}
+	}
+
+	required, prebuilt := []*sysMissing{}, []*sysMissing{}
+	for _, key := range order {
+		if missing[key].Prebuilt {
+			prebuilt = append(prebuilt, missing[key])
+		} else {
+			required = append(required, missing[key])
+		}
+	}
+
+	if len(required) > 0 {
+		issues = append(issues, Issue{
+			Severity:    SeverityError,
+			ProjectType: projectType,
+			Message:     fmt.Sprintf("%d system libraries or tools needed to build native dependencies in %s are missing: %s", len(required), strings.Join(files, ", "), describeSysMissing(required)),
+			Suggestion:  sysInstallHint(required, manager),
+		})
+	}
+	if len(prebuilt) > 0 {
+		issues = append(issues, Issue{
+			Severity:    SeverityWarning,
+			ProjectType: projectType,
+			Message:     fmt.Sprintf("%d system libraries or tools are missing that dependencies in %s need if no prebuilt binary exists for this platform: %s", len(prebuilt), strings.Join(files, ", "), describeSysMissing(prebuilt)),
+			Suggestion:  sysInstallHint(prebuilt, manager),
+		})
+	}
+	return issues
+}
+
+// sysLibraryFound looks a library up with pkg-config, then by its headers
+func sysLibraryFound(lib sysLibrary, env *nativeEnv) bool {
+	for _, module := range lib.PkgConfig {
+		if env.hasModule(module, "", "") {
+			return true
+		}
+	}
+	for _, header := range lib.Headers {
+		if env.hasHeader(header) {
+			return true
+		}
+	}
+	return false
+}
+
+func describeSysMissing(missing []*sysMissing) string {
+	parts := []string{}
+	for _, m := range missing {
+		parts = append(parts, fmt.Sprintf("%s (for %s)", m.Name, strings.Join(m.Packages, ", ")))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// sysInstallHint returns the command that installs the missing libraries
+// and tools with the system package manager
+func sysInstallHint(missing []*sysMissing, manager string) string {
+	install, hints := []string{}, []string{}
+	for _, m := range missing {
+		switch {
+		case m.Install != "":
+			if !containsString(install, m.Install) {
+				install = append(install, m.Install)
+			}
+		case m.Hint != "" && manager != "":
+			if !containsString(hints, m.Hint) {
+				hints = append(hints, m.Hint)
+			}
+		}
+	}
+	if command, ok := installCommands[manager]; ok && len(install) > 0 {
+		hints = append([]string{fmt.Sprintf("%s %s", command, strings.Join(install, " "))}, hints...)
+	}
+	if len(hints) == 0 {
+		return "Install the development packages of these libraries (usually lib<name>-dev on Debian/Ubuntu, <name>-devel on Fedora) and the build tools with your system package manager"
+	}
+	return fmt.Sprintf("Install them with: %s", strings.Join(hints, "; "))
+}
+
+// checkSystemLibraries checks that the system libraries and tools the native
+// dependencies of a Rust, Python, Node.js or Ruby project build against are
+// installed
+func checkSystemLibraries(path string, project *detector.ProjectType) []Issue {
+	ecosystem, ok := sysEcosystems[project.Name]
+	// The catalogue describes Unix packages; Windows builds use MSVC
+	if !ok || runtime.GOOS == "windows" {
+		return []Issue{}
+	}
+	catalogue := syslibs.Packages[ecosystem]
+
+	var packages []lockedPackage
+	switch ecosystem {
+	case "cargo":
+		packages = cargoLockPackages(path, catalogue)
+	case "pypi":
+		packages = pythonLockPackages(path, catalogue)
+	case "npm":
+		packages = npmLockPackages(path, catalogue)
+	case "rubygems":
+		packages = gemLockPackages(path, catalogue)
+	}
+	if len(packages) == 0 {
+		return []Issue{}
+	}
+	return checkSystemDeps(project.Name, packages, catalogue, defaultNativeEnv(), isCommandAvailable, systemPackageManager())
+}
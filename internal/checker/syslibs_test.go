@@ -0,0 +1,167 @@
+package checker
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestSyslibsCatalogue(t *testing.T) {
+	for key, lib := range syslibs.Libraries {
+		if lib.Name == "" || len(lib.PkgConfig)+len(lib.Headers) == 0 {
+			t.Errorf("library %s has no name or no way to find it", key)
+		}
+		for manager := range lib.Packages {
+			if _, ok := installCommands[manager]; !ok {
+				t.Errorf("library %s has a package for unknown package manager %s", key, manager)
+			}
+		}
+	}
+	for key, tool := range syslibs.Tools {
+		if tool.Name == "" || len(tool.Commands) == 0 {
+			t.Errorf("tool %s has no name or commands", key)
+		}
+	}
+	for ecosystem, packages := range syslibs.Packages {
+		for name, pkg := range packages {
+			for _, lib := range pkg.Libraries {
+				if _, ok := syslibs.Libraries[lib]; !ok {
+					t.Errorf("%s package %s needs unknown library %s", ecosystem, name, lib)
+				}
+			}
+			for _, tool := range pkg.Tools {
+				if _, ok := syslibs.Tools[tool]; !ok {
+					t.Errorf("%s package %s needs unknown tool %s", ecosystem, name, tool)
+				}
+			}
+		}
+	}
+}
+
+func TestParseOSRelease(t *testing.T) {
+	tests := []struct {
+		data string
+		want string
+	}{
+		{"NAME=\"Ubuntu\"\nID=ubuntu\nID_LIKE=debian\n", "apt"},
+		{"ID=\"rocky\"\nID_LIKE=\"rhel centos fedora\"\n", "dnf"},
+		{"ID=alpine\n", "apk"},
+		{"ID=opensuse-tumbleweed\nID_LIKE=\"opensuse suse\"\n", "zypper"},
+		{"ID=nixos\n", ""},
+	}
+	for _, tt := range tests {
+		if got := parseOSRelease(tt.data); got != tt.want {
+			t.Errorf("parseOSRelease(%q) = %q, want %q", tt.data, got, tt.want)
+		}
+	}
+}
+
+func TestCargoLockPackages(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "Cargo.lock"), []byte(`version = 3
+
+[[package]]
+name = "openssl-sys"
+version = "0.9.99"
+dependencies = [
+ "cc",
+ "libc",
+ "openssl-src",
+ "pkg-config",
+]
+
+[[package]]
+name = "libsqlite3-sys"
+version = "0.27.0"
+dependencies = [
+ "pkg-config",
+ "vcpkg",
+]
+
+[[package]]
+name = "serde"
+version = "1.0.195"
+`), 0644)
+
+	catalogue := syslibs.Packages["cargo"]
+	packages := cargoLockPackages(dir, catalogue)
+	if len(packages) != 2 || packages[0].Name != "openssl-sys" || packages[1].Name != "libsqlite3-sys" {
+		t.Fatalf("cargoLockPackages() = %+v", packages)
+	}
+
+	// openssl-sys is vendored through openssl-src, so only SQLite is needed
+	env := &nativeEnv{}
+	hasCommand := func(string) bool { return true }
+	issues := checkSystemDeps("Rust", packages, catalogue, env, hasCommand, "apt")
+	want := "1 system libraries or tools needed to build native dependencies in Cargo.lock are missing: SQLite (for libsqlite3-sys)"
+	if len(issues) != 1 || issues[0].Message != want || issues[0].Suggestion != "Install them with: sudo apt install libsqlite3-dev" {
+		t.Errorf("checkSystemDeps() = %v, want %q", issues, want)
+	}
+}
+
+func TestNpmLockPackages(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "package-lock.json"), []byte(`{
+  "lockfileVersion": 3,
+  "packages": {
+    "": {"dependencies": {"bcrypt": "^5.1.1", "sharp": "^0.33.2", "express": "^4.18.2"}},
+    "node_modules/bcrypt": {"version": "5.1.1", "hasInstallScript": true},
+    "node_modules/sharp": {"version": "0.33.2", "hasInstallScript": true},
+    "node_modules/express": {"version": "4.18.2"}
+  }
+}`), 0644)
+	os.MkdirAll(filepath.Join(dir, "node_modules", "bcrypt"), 0755)
+
+	packages := npmLockPackages(dir, syslibs.Packages["npm"])
+	if len(packages) != 1 || packages[0].Name != "sharp" || packages[0].File != "package-lock.json" {
+		t.Errorf("npmLockPackages() = %+v, want only sharp", packages)
+	}
+}
+
+func TestCheckSystemDeps(t *testing.T) {
+	env := &nativeEnv{PkgConfig: func(spec string) bool { return spec == "libpq" }}
+	hasCommand := func(command string) bool { return command == "gcc" }
+	packages := []lockedPackage{
+		{Name: "psycopg2", File: "poetry.lock"},
+		{Name: "lxml", File: "poetry.lock"},
+		{Name: "cryptography", File: "poetry.lock"},
+	}
+
+	got := []string{}
+	for _, issue := range checkSystemDeps("Python", packages, syslibs.Packages["pypi"], env, hasCommand, "dnf") {
+		got = append(got, string(issue.Severity)+" "+issue.Message+"\n  "+issue.Suggestion)
+	}
+	want := []string{
+		"ERROR 2 system libraries or tools needed to build native dependencies in poetry.lock are missing: Python headers (for psycopg2), pg_config (for psycopg2)\n" +
+			"  Install them with: sudo dnf install python3-devel libpq-devel",
+		"WARNING 4 system libraries or tools are missing that dependencies in poetry.lock need if no prebuilt binary exists for this platform: libxml2 (for lxml), libxslt (for lxml), OpenSSL (for cryptography), Rust (cargo) (for cryptography)\n" +
+			"  Install them with: sudo dnf install libxml2-devel libxslt-devel openssl-devel; curl --proto '=https' --tlsv1.2 -sSf https://sh.rustup.rs | sh",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("checkSystemDeps():\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	// Without a known package manager the hint stays generic
+	issues := checkSystemDeps("Python", packages[:1], syslibs.Packages["pypi"], env, hasCommand, "")
+	if len(issues) != 1 || !strings.HasPrefix(issues[0].Suggestion, "Install the development packages") {
+		t.Errorf("checkSystemDeps() without a package manager = %v", issues)
+	}
+}
+
+func TestCargoLockPackagesOtherPlatforms(t *testing.T) {
+	dir := t.TempDir()
+	lock := "version = 3\n\n[[package]]\nname = \"alsa-sys\"\nversion = \"0.3.1\"\n\n[[package]]\nname = \"coreaudio-sys\"\nversion = \"0.2.15\"\n"
+	if err := os.WriteFile(filepath.Join(dir, "Cargo.lock"), []byte(lock), 0644); err != nil {
+		t.Fatal(err)
+	}
+	catalogue := map[string]sysPackage{
+		"alsa-sys":      {OS: []string{runtime.GOOS}},
+		"coreaudio-sys": {OS: []string{"ios"}},
+	}
+	packages := cargoLockPackages(dir, catalogue)
+	if len(packages) != 1 || packages[0].Name != "alsa-sys" {
+		t.Errorf("cargoLockPackages() = %+v, want only the crates of this platform", packages)
+	}
+}
@@ -0,0 +1,92 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// FileNames are the config file names looked up in the project root, in order
+var FileNames = []string{"devdoctor.json", ".devdoctor.json"}
+
+// Config is the repo-level DevDoctor configuration
+type Config struct {
+	ProjectTypes []ProjectType `json:"projectTypes"`
+}
+
+// ProjectType declares a custom project type that is detected alongside the
+// built-in ones
+type ProjectType struct {
+	Name          string   `json:"name"`
+	Markers       []string `json:"markers"` // file names or glob patterns relative to the project root
+	RequiredTools []string `json:"requiredTools"`
+	Checks        []Check  `json:"checks"`
+}
+
+// Check is a declarative check run for a custom project type. Exactly one of
+// File or Command must be set: a File check fails when no file matches the
+// path (globs allowed), a Command check fails when the command exits non-zero.
+type Check struct {
+	File       string   `json:"file,omitempty"`
+	Command    []string `json:"command,omitempty"`
+	Severity   string   `json:"severity,omitempty"` // ERROR, WARNING or INFO (default WARNING)
+	Message    string   `json:"message,omitempty"`
+	Suggestion string   `json:"suggestion,omitempty"`
+}
+
+// Load reads the first config file found in dir. It returns a nil Config and
+// an empty file name when dir has no config file.
+func Load(dir string) (*Config, string, error) {
+	for _, name := range FileNames {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, name, err
+		}
+		var cfg Config
+		if err := json.Unmarshal(data, &cfg); err != nil {
+			return nil, name, fmt.Errorf("%s: %v", name, err)
+		}
+		if err := cfg.validate(); err != nil {
+			return nil, name, fmt.Errorf("%s: %v", name, err)
+		}
+		return &cfg, name, nil
+	}
+	return nil, "", nil
+}
+
+func (c *Config) validate() error {
+	for i := range c.ProjectTypes {
+		pt := &c.ProjectTypes[i]
+		if pt.Name == "" {
+			return fmt.Errorf("projectTypes[%d]: name is required", i)
+		}
+		if len(pt.Markers) == 0 {
+			return fmt.Errorf("project type %q: at least one marker is required", pt.Name)
+		}
+		for _, marker := range pt.Markers {
+			if _, err := filepath.Match(marker, ""); err != nil {
+				return fmt.Errorf("project type %q: invalid marker %q: %v", pt.Name, marker, err)
+			}
+		}
+		for j := range pt.Checks {
+			check := &pt.Checks[j]
+			if (check.File == "") == (len(check.Command) == 0) {
+				return fmt.Errorf("project type %q: checks[%d] must set exactly one of file or command", pt.Name, j)
+			}
+			check.Severity = strings.ToUpper(check.Severity)
+			switch check.Severity {
+			case "":
+				check.Severity = "WARNING"
+			case "ERROR", "WARNING", "INFO":
+			default:
+				return fmt.Errorf("project type %q: checks[%d] has unknown severity %q", pt.Name, j, check.Severity)
+			}
+		}
+	}
+	return nil
+}
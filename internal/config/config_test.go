@@ -0,0 +1,74 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoad(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	cfg, name, err := Load(tmpDir)
+	if cfg != nil || name != "" || err != nil {
+		t.Fatalf("Expected no config, got %v %q %v", cfg, name, err)
+	}
+
+	data := `{
+  "projectTypes": [
+    {
+      "name": "Acme",
+      "markers": ["acme.yml"],
+      "requiredTools": ["acme"],
+      "checks": [
+        {"file": "acme.lock", "severity": "error", "message": "acme.lock missing"},
+        {"command": ["acme", "doctor"]}
+      ]
+    }
+  ]
+}`
+	if err := os.WriteFile(filepath.Join(tmpDir, ".devdoctor.json"), []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, name, err = Load(tmpDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name != ".devdoctor.json" {
+		t.Errorf("Expected .devdoctor.json, got %q", name)
+	}
+	if len(cfg.ProjectTypes) != 1 || len(cfg.ProjectTypes[0].Checks) != 2 {
+		t.Fatalf("Unexpected config: %+v", cfg)
+	}
+	checks := cfg.ProjectTypes[0].Checks
+	if checks[0].Severity != "ERROR" || checks[1].Severity != "WARNING" {
+		t.Errorf("Expected normalized severities, got %q and %q", checks[0].Severity, checks[1].Severity)
+	}
+}
+
+func TestLoadInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"bad json", `{"projectTypes": [`},
+		{"missing name", `{"projectTypes": [{"markers": ["a"]}]}`},
+		{"missing markers", `{"projectTypes": [{"name": "A"}]}`},
+		{"bad glob", `{"projectTypes": [{"name": "A", "markers": ["[a"]}]}`},
+		{"file and command", `{"projectTypes": [{"name": "A", "markers": ["a"], "checks": [{"file": "x", "command": ["y"]}]}]}`},
+		{"bad severity", `{"projectTypes": [{"name": "A", "markers": ["a"], "checks": [{"file": "x", "severity": "fatal"}]}]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(tmpDir, "devdoctor.json"), []byte(tt.data), 0644); err != nil {
+				t.Fatal(err)
+			}
+			if _, _, err := Load(tmpDir); err == nil {
+				t.Error("Expected an error")
+			}
+		})
+	}
+}
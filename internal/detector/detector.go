@@ -1,15 +1,44 @@
 package detector
 
 import (
+	"io/fs"
 	"os"
 	"path/filepath"
+	"strings"
+
+	"github.com/Sw3bbl3/devdoctor/internal/config"
 )
 
 // ProjectType represents a detected project type
 type ProjectType struct {
 	Name          string
+	Root          string // directory of the project relative to the scan root ("." for the root itself)
 	ConfigFiles   []string
 	RequiredTools []string
+	Checks        []config.Check // declarative checks of custom project types
+	Confidence    float64        // how certain the detection is, from 0 to 1
+	Sources       *NativeSources // the C and C++ sources, for C and C++ projects
+}
+
+// skipDirs lists directories that never contain sub-projects of their own
+// (dependency caches, build output, VCS metadata) and are not descended into
+// during a recursive scan.
+var skipDirs = map[string]bool{
+	".git":         true,
+	".hg":          true,
+	".svn":         true,
+	"node_modules": true,
+	"vendor":       true,
+	"target":       true,
+	"venv":         true,
+	".venv":        true,
+	"__pycache__":  true,
+}
+
+// SkipDir reports whether a directory is a dependency cache, build output or
+// VCS metadata directory that scans should not descend into
+func SkipDir(name string) bool {
+	return skipDirs[name]
 }
 
 // DetectorRegistry manages project type detection
@@ -25,6 +54,7 @@ func NewDetectorRegistry() *DetectorRegistry {
 }
 
 func (r *DetectorRegistry) registerDetectors() {
+   native := &nativeDetector{}
    r.detectors = []func(string) *ProjectType{
 	   detectNodeJS,
 	   detectPython,
@@ -35,8 +65,8 @@ func (r *DetectorRegistry) registerDetectors() {
 	   detectDotNet,
 	   detectDocker,
 	   detectPHP,
-	   detectC,
-	   detectCpp,
+	   native.detectC,
+	   native.detectCpp,
 	   detectSwift,
 	   detectKotlin,
 	   detectElixir,
@@ -56,26 +86,58 @@ func detectPHP(path string) *ProjectType {
    return nil
 }
 
-func detectC(path string) *ProjectType {
-   if fileExists(path, "Makefile") || fileExists(path, "CMakeLists.txt") {
-	   return &ProjectType{
-		   Name:          "C",
-		   ConfigFiles:   []string{"Makefile", "CMakeLists.txt"},
-		   RequiredTools: []string{"gcc", "make"},
-	   }
-   }
-   return nil
+// nativeDetector runs the C and C++ detectors, which run one after the
+// other on each directory, and inspects the sources of a directory once
+type nativeDetector struct {
+	path    string
+	sources *NativeSources
 }
 
-func detectCpp(path string) *ProjectType {
-   if fileExists(path, "CMakeLists.txt") || fileExists(path, "Makefile") {
-	   return &ProjectType{
-		   Name:          "C++",
-		   ConfigFiles:   []string{"CMakeLists.txt", "Makefile"},
-		   RequiredTools: []string{"g++", "make"},
-	   }
-   }
-   return nil
+func (n *nativeDetector) inspect(path string) *NativeSources {
+	if n.sources == nil || n.path != path {
+		sources := InspectNativeSources(path)
+		n.path, n.sources = path, &sources
+	}
+	return n.sources
+}
+
+func (n *nativeDetector) detectC(path string) *ProjectType {
+	configFiles, tools := nativeBuildFiles(path)
+	if len(configFiles) == 0 {
+		return nil
+	}
+	sources := n.inspect(path)
+	confidence := nativeConfidence(*sources, sources.CFiles, sources.CppFiles, "C")
+	if confidence == 0 {
+		// A Makefile used as a task runner is not a C project
+		return nil
+	}
+	return &ProjectType{
+		Name:          "C",
+		ConfigFiles:   configFiles,
+		RequiredTools: append([]string{"gcc"}, tools...),
+		Confidence:    confidence,
+		Sources:       sources,
+	}
+}
+
+func (n *nativeDetector) detectCpp(path string) *ProjectType {
+	configFiles, tools := nativeBuildFiles(path)
+	if len(configFiles) == 0 {
+		return nil
+	}
+	sources := n.inspect(path)
+	confidence := nativeConfidence(*sources, sources.CppFiles, sources.CFiles, "CXX")
+	if confidence == 0 {
+		return nil
+	}
+	return &ProjectType{
+		Name:          "C++",
+		ConfigFiles:   configFiles,
+		RequiredTools: append([]string{"g++"}, tools...),
+		Confidence:    confidence,
+		Sources:       sources,
+	}
 }
 
 func detectSwift(path string) *ProjectType {
@@ -91,10 +153,14 @@ func detectSwift(path string) *ProjectType {
 
 func detectKotlin(path string) *ProjectType {
    if fileExists(path, "build.gradle.kts") || fileExists(path, "settings.gradle.kts") {
+	   tools := []string{"kotlin"}
+	   if DetectGradleWrapper(path) == nil {
+		   tools = append(tools, "gradle")
+	   }
 	   return &ProjectType{
 		   Name:          "Kotlin",
 		   ConfigFiles:   []string{"build.gradle.kts", "settings.gradle.kts"},
-		   RequiredTools: []string{"kotlin", "gradle"},
+		   RequiredTools: tools,
 	   }
    }
    return nil
@@ -148,11 +214,77 @@ func detectDartFlutter(path string) *ProjectType {
 		return nil
 	}
 
-	// Detect scans the directory and returns all detected project types
-	func (r *DetectorRegistry) Detect(path string) []*ProjectType {
-		var projects []*ProjectType
-		for _, detector := range r.detectors {
-		if project := detector(path); project != nil {
+// AddProjectTypes registers the custom project types declared in a
+// devdoctor config file. They are detected after the built-in types.
+func (r *DetectorRegistry) AddProjectTypes(types []config.ProjectType) {
+	for _, pt := range types {
+		r.detectors = append(r.detectors, customDetector(pt))
+	}
+}
+
+func customDetector(pt config.ProjectType) func(string) *ProjectType {
+	return func(path string) *ProjectType {
+		configFiles := []string{}
+		for _, marker := range pt.Markers {
+			matches, _ := filepath.Glob(filepath.Join(path, marker))
+			for _, match := range matches {
+				if rel, err := filepath.Rel(path, match); err == nil {
+					configFiles = append(configFiles, filepath.ToSlash(rel))
+				}
+			}
+		}
+		if len(configFiles) == 0 {
+			return nil
+		}
+		return &ProjectType{
+			Name:          pt.Name,
+			ConfigFiles:   configFiles,
+			RequiredTools: pt.RequiredTools,
+			Checks:        pt.Checks,
+		}
+	}
+}
+
+// Detect scans the directory and returns all detected project types
+func (r *DetectorRegistry) Detect(path string) []*ProjectType {
+	return r.detectIn(path, ".")
+}
+
+// DetectRecursive walks the tree below path up to maxDepth directory levels
+// and returns the project types detected in every directory it visits. Each
+// ProjectType carries its Root relative to path.
+func (r *DetectorRegistry) DetectRecursive(path string, maxDepth int) []*ProjectType {
+	var projects []*ProjectType
+	filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || !d.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(path, p)
+		if err != nil {
+			return nil
+		}
+		rel = filepath.ToSlash(rel)
+		if rel != "." {
+			if skipDirs[d.Name()] || strings.Count(rel, "/")+1 > maxDepth {
+				return filepath.SkipDir
+			}
+		}
+		projects = append(projects, r.detectIn(p, rel)...)
+		return nil
+	})
+	return projects
+}
+
+// detectIn runs every registered detector against dir and tags the results
+// with root.
+func (r *DetectorRegistry) detectIn(dir, root string) []*ProjectType {
+	var projects []*ProjectType
+	for _, detector := range r.detectors {
+		if project := detector(dir); project != nil {
+			project.Root = root
+			if project.Confidence == 0 {
+				project.Confidence = 1
+			}
 			projects = append(projects, project)
 		}
 	}
@@ -166,10 +298,11 @@ func fileExists(path, filename string) bool {
 
 func detectNodeJS(path string) *ProjectType {
 	if fileExists(path, "package.json") {
+		pm := DetectNodePackageManager(path)
 		return &ProjectType{
 			Name:          "Node.js",
-			ConfigFiles:   []string{"package.json"},
-			RequiredTools: []string{"node", "npm"},
+			ConfigFiles:   append([]string{"package.json"}, pm.Lockfiles...),
+			RequiredTools: []string{"node", pm.Name},
 		}
 	}
 	return nil
@@ -189,35 +322,54 @@ func detectPython(path string) *ProjectType {
 	if fileExists(path, "Pipfile") {
 		configFiles = append(configFiles, "Pipfile")
 	}
+	for _, file := range append([]string{"poetry.lock", "Pipfile.lock", "uv.lock"}, pythonEnvironmentFiles...) {
+		if fileExists(path, file) {
+			configFiles = append(configFiles, file)
+		}
+	}
 
 	if len(configFiles) > 0 {
 		return &ProjectType{
 			Name:          "Python",
 			ConfigFiles:   configFiles,
-			RequiredTools: []string{"python", "pip"},
+			RequiredTools: pythonRequiredTools(DetectPythonToolchain(path)),
 		}
 	}
 	return nil
 }
 
 func detectGo(path string) *ProjectType {
-	if fileExists(path, "go.mod") {
-		return &ProjectType{
-			Name:          "Go",
-			ConfigFiles:   []string{"go.mod"},
-			RequiredTools: []string{"go"},
+	// A go.work file makes a Go workspace root even without a go.mod
+	configFiles := []string{}
+	for _, file := range []string{"go.mod", "go.work"} {
+		if fileExists(path, file) {
+			configFiles = append(configFiles, file)
 		}
 	}
-	return nil
+	if len(configFiles) == 0 {
+		return nil
+	}
+	return &ProjectType{
+		Name:          "Go",
+		ConfigFiles:   configFiles,
+		RequiredTools: []string{"go"},
+	}
 }
 
 func detectJava(path string) *ProjectType {
 	configFiles := []string{}
 	tools := []string{"java"}
 
+	// Projects that ship a wrapper do not need a global Maven or Gradle
 	if fileExists(path, "pom.xml") {
 		configFiles = append(configFiles, "pom.xml")
-		tools = append(tools, "mvn")
+		if wrapper := DetectMavenWrapper(path); wrapper != nil {
+			if wrapper.Properties != "" {
+				configFiles = append(configFiles, wrapper.Properties)
+			}
+		} else {
+			tools = append(tools, "mvn")
+		}
 	}
 	if fileExists(path, "build.gradle") || fileExists(path, "build.gradle.kts") {
 		if fileExists(path, "build.gradle") {
@@ -226,7 +378,13 @@ func detectJava(path string) *ProjectType {
 		if fileExists(path, "build.gradle.kts") {
 			configFiles = append(configFiles, "build.gradle.kts")
 		}
-		tools = append(tools, "gradle")
+		if wrapper := DetectGradleWrapper(path); wrapper != nil {
+			if wrapper.Properties != "" {
+				configFiles = append(configFiles, wrapper.Properties)
+			}
+		} else {
+			tools = append(tools, "gradle")
+		}
 	}
 
 	if len(configFiles) > 0 {
@@ -288,17 +446,13 @@ func detectDotNet(path string) *ProjectType {
 }
 
 func detectDocker(path string) *ProjectType {
-	if fileExists(path, "Dockerfile") || fileExists(path, "docker-compose.yml") || fileExists(path, "docker-compose.yaml") {
-		configFiles := []string{}
-		if fileExists(path, "Dockerfile") {
-			configFiles = append(configFiles, "Dockerfile")
-		}
-		if fileExists(path, "docker-compose.yml") {
-			configFiles = append(configFiles, "docker-compose.yml")
-		}
-		if fileExists(path, "docker-compose.yaml") {
-			configFiles = append(configFiles, "docker-compose.yaml")
+	configFiles := []string{}
+	for _, name := range []string{"Dockerfile", "compose.yaml", "compose.yml", "docker-compose.yml", "docker-compose.yaml"} {
+		if fileExists(path, name) {
+			configFiles = append(configFiles, name)
 		}
+	}
+	if len(configFiles) > 0 {
 		return &ProjectType{
 			Name:          "Docker",
 			ConfigFiles:   configFiles,
@@ -3,7 +3,10 @@ package detector
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+
+	"github.com/Sw3bbl3/devdoctor/internal/config"
 )
 
 func TestDetectNodeJS(t *testing.T) {
@@ -176,6 +179,8 @@ func TestDetectDocker(t *testing.T) {
 		{"Dockerfile only", []string{"Dockerfile"}},
 		{"docker-compose.yml", []string{"docker-compose.yml"}},
 		{"docker-compose.yaml", []string{"docker-compose.yaml"}},
+		{"compose.yaml", []string{"compose.yaml"}},
+		{"compose.yml", []string{"compose.yml"}},
 		{"Both", []string{"Dockerfile", "docker-compose.yml"}},
 	}
 
@@ -233,3 +238,168 @@ func TestDetectorRegistry(t *testing.T) {
 		t.Error("Expected Go to be detected")
 	}
 }
+
+func TestDetectRecursive(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	files := []string{
+		"services/api/go.mod",
+		"web/package.json",
+		"web/node_modules/left-pad/package.json",
+		"tools/requirements.txt",
+		"a/b/c/d/Cargo.toml",
+	}
+	for _, file := range files {
+		filePath := filepath.Join(tmpDir, file)
+		if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filePath, []byte("{}"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	registry := NewDetectorRegistry()
+
+	if projects := registry.Detect(tmpDir); len(projects) != 0 {
+		t.Errorf("Expected no projects at the root, got %d", len(projects))
+	}
+
+	projects := registry.DetectRecursive(tmpDir, 3)
+	got := map[string]string{}
+	for _, p := range projects {
+		got[p.Root] = p.Name
+	}
+
+	want := map[string]string{
+		"services/api": "Go",
+		"web":          "Node.js",
+		"tools":        "Python",
+	}
+	if len(got) != len(want) {
+		t.Errorf("Expected %d sub-projects, got %v", len(want), got)
+	}
+	for root, name := range want {
+		if got[root] != name {
+			t.Errorf("Expected %s at %s, got %q", name, root, got[root])
+		}
+	}
+}
+
+func TestAddProjectTypes(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmpDir, "app.acme.yml"), []byte(""), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	registry := NewDetectorRegistry()
+	registry.AddProjectTypes([]config.ProjectType{
+		{
+			Name:          "Acme",
+			Markers:       []string{"*.acme.yml"},
+			RequiredTools: []string{"acme"},
+			Checks:        []config.Check{{File: "acme.lock", Severity: "WARNING"}},
+		},
+		{
+			Name:    "Other",
+			Markers: []string{"other.toml"},
+		},
+	})
+
+	projects := registry.Detect(tmpDir)
+	if len(projects) != 1 {
+		t.Fatalf("Expected 1 project to be detected, got %d", len(projects))
+	}
+	project := projects[0]
+	if project.Name != "Acme" {
+		t.Errorf("Expected project name to be 'Acme', got %s", project.Name)
+	}
+	if len(project.ConfigFiles) != 1 || project.ConfigFiles[0] != "app.acme.yml" {
+		t.Errorf("Expected config file 'app.acme.yml', got %v", project.ConfigFiles)
+	}
+	if len(project.Checks) != 1 {
+		t.Errorf("Expected checks to be carried over, got %v", project.Checks)
+	}
+}
+
+func TestDetectCAndCpp(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   map[string]string
+		wantC   bool
+		wantCpp bool
+	}{
+		{"task-runner Makefile", map[string]string{"Makefile": "build:\n\tgo build ./...\n"}, false, false},
+		{"C sources", map[string]string{"Makefile": "", "src/main.c": ""}, true, false},
+		{"C++ sources", map[string]string{"Makefile": "", "src/main.cpp": "", "include/app.hpp": ""}, false, true},
+		{"no build file", map[string]string{"main.c": ""}, false, false},
+		{"CMake CXX only", map[string]string{"CMakeLists.txt": "project(app VERSION 1.0 LANGUAGES CXX)\n"}, false, true},
+		{"CMake short form", map[string]string{"CMakeLists.txt": "project(app C)\n"}, true, false},
+		{"CMake mixed", map[string]string{"CMakeLists.txt": "project(app LANGUAGES C CXX)\n", "a.c": "", "b.cc": ""}, true, true},
+		{"Meson", map[string]string{"meson.build": "project('app', 'c')\n", "src/main.c": ""}, true, false},
+		{"Conan with Ninja", map[string]string{"conanfile.txt": "[requires]\nfmt/10.2.1\n", "build.ninja": "", "main.cpp": ""}, false, true},
+		{"vcpkg manifest only", map[string]string{"vcpkg.json": "{}", "main.cpp": ""}, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			for file, content := range tt.files {
+				filePath := filepath.Join(tmpDir, file)
+				if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			native := &nativeDetector{}
+			if got := native.detectC(tmpDir) != nil; got != tt.wantC {
+				t.Errorf("detectC = %v, want %v", got, tt.wantC)
+			}
+			if got := native.detectCpp(tmpDir) != nil; got != tt.wantCpp {
+				t.Errorf("detectCpp = %v, want %v", got, tt.wantCpp)
+			}
+		})
+	}
+}
+
+func TestParseCMakeLanguages(t *testing.T) {
+	tests := []struct {
+		content      string
+		want         []string
+		wantExplicit bool
+	}{
+		{"project(app)", []string{"C", "CXX"}, false},
+		{"project(app VERSION 2.1 DESCRIPTION \"demo\")", []string{"C", "CXX"}, false},
+		{"# project(old C)\nproject(\n  app\n  LANGUAGES CXX\n)", []string{"CXX"}, true},
+		{"project(app NONE)\nenable_language(C)", []string{"C"}, true},
+	}
+
+	for _, tt := range tests {
+		got, explicit := parseCMakeLanguages(tt.content)
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") || explicit != tt.wantExplicit {
+			t.Errorf("parseCMakeLanguages(%q) = %v, %v; want %v, %v", tt.content, got, explicit, tt.want, tt.wantExplicit)
+		}
+	}
+}
+
+func TestDetectNativeSharesSources(t *testing.T) {
+	tmpDir := t.TempDir()
+	for _, file := range []string{"Makefile", "main.c", "util.cpp"} {
+		if err := os.WriteFile(filepath.Join(tmpDir, file), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var sources []*NativeSources
+	for _, project := range NewDetectorRegistry().Detect(tmpDir) {
+		if project.Name == "C" || project.Name == "C++" {
+			sources = append(sources, project.Sources)
+		}
+	}
+	if len(sources) != 2 || sources[0] == nil || sources[0] != sources[1] || sources[0].CFiles != 1 {
+		t.Errorf("Expected C and C++ to share one source scan, got %v", sources)
+	}
+}
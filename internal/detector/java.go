@@ -0,0 +1,71 @@
+package detector
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var (
+	gradleDistRe = regexp.MustCompile(`gradle-([^/]+?)-(bin|all)\.zip$`)
+	mavenDistRe  = regexp.MustCompile(`apache-maven-([^/]+?)-bin\.zip$`)
+)
+
+// BuildWrapper describes a checked-in Maven or Gradle wrapper
+type BuildWrapper struct {
+	Tool            string // the global tool the wrapper replaces: "mvn" or "gradle"
+	Script          string // wrapper script name, e.g. "gradlew"
+	Properties      string // wrapper properties file relative to the project root
+	DistributionURL string // distributionUrl from the properties file
+}
+
+// Version returns the Maven or Gradle version pinned by the wrapper
+func (w BuildWrapper) Version() string {
+	re := gradleDistRe
+	if w.Tool == "mvn" {
+		re = mavenDistRe
+	}
+	if m := re.FindStringSubmatch(w.DistributionURL); m != nil {
+		return m[1]
+	}
+	return ""
+}
+
+// DistributionName returns the name of the pinned distribution archive
+// without its extension, e.g. "gradle-8.5-bin"
+func (w BuildWrapper) DistributionName() string {
+	base := w.DistributionURL[strings.LastIndex(w.DistributionURL, "/")+1:]
+	return strings.TrimSuffix(base, ".zip")
+}
+
+// DetectGradleWrapper returns the Gradle wrapper at path, or nil
+func DetectGradleWrapper(path string) *BuildWrapper {
+	return detectWrapper(path, "gradle", "gradlew", filepath.Join("gradle", "wrapper", "gradle-wrapper.properties"))
+}
+
+// DetectMavenWrapper returns the Maven wrapper at path, or nil
+func DetectMavenWrapper(path string) *BuildWrapper {
+	return detectWrapper(path, "mvn", "mvnw", filepath.Join(".mvn", "wrapper", "maven-wrapper.properties"))
+}
+
+func detectWrapper(path, tool, script, properties string) *BuildWrapper {
+	if !fileExists(path, script) && !fileExists(path, script+".bat") && !fileExists(path, script+".cmd") {
+		return nil
+	}
+	wrapper := &BuildWrapper{Tool: tool, Script: script}
+	if file, err := os.Open(filepath.Join(path, properties)); err == nil {
+		defer file.Close()
+		wrapper.Properties = filepath.ToSlash(properties)
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			key, value, ok := strings.Cut(scanner.Text(), "=")
+			if ok && strings.TrimSpace(key) == "distributionUrl" {
+				// Java properties escape ':' as '\:'
+				wrapper.DistributionURL = strings.ReplaceAll(strings.TrimSpace(value), `\`, "")
+			}
+		}
+	}
+	return wrapper
+}
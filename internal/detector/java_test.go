@@ -0,0 +1,45 @@
+package detector
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDetectJavaWithWrappers(t *testing.T) {
+	tmpDir := t.TempDir()
+	files := map[string]string{
+		"pom.xml":                               "<project></project>",
+		"mvnw":                                  "#!/bin/sh\n",
+		".mvn/wrapper/maven-wrapper.properties": "distributionUrl=https\\://repo.maven.apache.org/maven2/org/apache/maven/apache-maven/3.9.6/apache-maven-3.9.6-bin.zip\n",
+		"build.gradle":                          "",
+		"gradlew":                               "#!/bin/sh\n",
+		"gradle/wrapper/gradle-wrapper.properties": "distributionBase=GRADLE_USER_HOME\ndistributionUrl=https\\://services.gradle.org/distributions/gradle-8.5-bin.zip\n",
+	}
+	for file, content := range files {
+		filePath := filepath.Join(tmpDir, file)
+		if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	project := detectJava(tmpDir)
+	if project == nil {
+		t.Fatal("Expected Java project to be detected")
+	}
+	if len(project.RequiredTools) != 1 || project.RequiredTools[0] != "java" {
+		t.Errorf("Expected only java to be required, got %v", project.RequiredTools)
+	}
+
+	gradle := DetectGradleWrapper(tmpDir)
+	if gradle == nil || gradle.Version() != "8.5" || gradle.DistributionName() != "gradle-8.5-bin" {
+		t.Errorf("Unexpected Gradle wrapper: %+v", gradle)
+	}
+	maven := DetectMavenWrapper(tmpDir)
+	if maven == nil || maven.Version() != "3.9.6" || maven.DistributionName() != "apache-maven-3.9.6-bin" {
+		t.Errorf("Unexpected Maven wrapper: %+v", maven)
+	}
+}
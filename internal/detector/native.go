@@ -0,0 +1,210 @@
+package detector
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// maxNativeScanDepth and maxNativeScanFiles bound the source scan so that
+// detection stays fast on large trees
+const (
+	maxNativeScanDepth = 3
+	maxNativeScanFiles = 5000
+)
+
+var (
+	cExtensions   = map[string]bool{".c": true}
+	cppExtensions = map[string]bool{".cc": true, ".cpp": true, ".cxx": true, ".c++": true, ".hpp": true, ".hh": true, ".hxx": true}
+
+	cmakeProjectRe  = regexp.MustCompile(`(?is)\bproject\s*\(([^)]*)\)`)
+	cmakeEnableRe   = regexp.MustCompile(`(?is)\benable_language\s*\(([^)]*)\)`)
+	cmakeCommentRe  = regexp.MustCompile(`#[^\n]*`)
+	cmakeProjectKws = map[string]bool{"VERSION": true, "DESCRIPTION": true, "HOMEPAGE_URL": true, "LANGUAGES": true}
+)
+
+// NativeSources summarizes the C and C++ sources of a project
+type NativeSources struct {
+	CFiles   int
+	CppFiles int
+	// CMakeLanguages lists the languages declared by project() and
+	// enable_language() in CMakeLists.txt. It is nil when there is no
+	// CMakeLists.txt and defaults to C and CXX when project() names none.
+	CMakeLanguages []string
+	// CMakeExplicit reports whether the languages were declared explicitly
+	CMakeExplicit bool
+}
+
+// InspectNativeSources counts C and C++ source files below path and reads
+// the languages declared in CMakeLists.txt
+func InspectNativeSources(path string) NativeSources {
+	var sources NativeSources
+
+	visited := 0
+	filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		visited++
+		if visited > maxNativeScanFiles {
+			return filepath.SkipAll
+		}
+		if d.IsDir() {
+			rel, _ := filepath.Rel(path, p)
+			if rel != "." && (skipDirs[d.Name()] || strings.Count(filepath.ToSlash(rel), "/")+1 > maxNativeScanDepth) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		ext := strings.ToLower(filepath.Ext(d.Name()))
+		if cExtensions[ext] {
+			sources.CFiles++
+		} else if cppExtensions[ext] {
+			sources.CppFiles++
+		}
+		return nil
+	})
+
+	if data, err := os.ReadFile(filepath.Join(path, "CMakeLists.txt")); err == nil {
+		sources.CMakeLanguages, sources.CMakeExplicit = parseCMakeLanguages(string(data))
+	}
+
+	return sources
+}
+
+// parseCMakeLanguages extracts the languages of project() and
+// enable_language() calls
+func parseCMakeLanguages(content string) ([]string, bool) {
+	content = cmakeCommentRe.ReplaceAllString(content, "")
+	languages := []string{}
+	explicit := false
+
+	if m := cmakeProjectRe.FindStringSubmatch(content); m != nil {
+		args := strings.Fields(m[1])
+		if len(args) > 1 {
+			args = args[1:] // skip the project name
+			if !cmakeProjectKws[strings.ToUpper(args[0])] {
+				// Short form: project(name C CXX)
+				languages = append(languages, args...)
+			} else {
+				inLanguages := false
+				for _, arg := range args {
+					if cmakeProjectKws[strings.ToUpper(arg)] {
+						inLanguages = strings.ToUpper(arg) == "LANGUAGES"
+						continue
+					}
+					if inLanguages {
+						languages = append(languages, arg)
+					}
+				}
+			}
+		}
+	}
+	if len(languages) > 0 {
+		explicit = true
+	} else {
+		// CMake enables C and C++ when project() does not name languages
+		languages = []string{"C", "CXX"}
+	}
+
+	for _, m := range cmakeEnableRe.FindAllStringSubmatch(content, -1) {
+		for _, lang := range strings.Fields(m[1]) {
+			if lang != "OPTIONAL" {
+				languages = append(languages, lang)
+				explicit = true
+			}
+		}
+	}
+
+	normalized := []string{}
+	for _, lang := range languages {
+		if lang = strings.ToUpper(strings.Trim(lang, `"`)); lang != "NONE" {
+			normalized = append(normalized, lang)
+		}
+	}
+	return normalized, explicit
+}
+
+// nativeConfidence scores how likely the project at path is written in the
+// given CMake language ("C" or "CXX") based on source files and the CMake
+// project declaration. It returns 0 when the language is not used.
+func nativeConfidence(sources NativeSources, files, otherFiles int, lang string) float64 {
+	declared := false
+	for _, l := range sources.CMakeLanguages {
+		if l == lang {
+			declared = true
+		}
+	}
+
+	switch {
+	case files > 0 && declared:
+		return 1.0
+	case files > 0:
+		return 0.9
+	case declared && sources.CMakeExplicit:
+		return 0.7
+	case declared && otherFiles == 0:
+		// CMake project with default languages and no sources nearby
+		return 0.4
+	}
+	return 0
+}
+
+// Uses reports whether the project uses the CMake language "C" or "CXX",
+// as the C and C++ detectors decide it
+func (s NativeSources) Uses(lang string) bool {
+	files, otherFiles := s.CFiles, s.CppFiles
+	if lang == "CXX" {
+		files, otherFiles = otherFiles, files
+	}
+	return nativeConfidence(s, files, otherFiles, lang) > 0
+}
+
+// nativeBuildFiles returns the build files and package manager manifests
+// present at path and the tools they need. vcpkg is usually found through
+// VCPKG_ROOT rather than PATH, so it is not listed.
+func nativeBuildFiles(path string) ([]string, []string) {
+	configFiles := []string{}
+	tools := []string{}
+	if fileExists(path, "CMakeLists.txt") {
+		configFiles = append(configFiles, "CMakeLists.txt")
+		tools = append(tools, "cmake")
+	}
+	if fileExists(path, "meson.build") {
+		configFiles = append(configFiles, "meson.build")
+		tools = append(tools, "meson", "ninja")
+	}
+	if fileExists(path, "build.ninja") {
+		configFiles = append(configFiles, "build.ninja")
+		if !containsTool(tools, "ninja") {
+			tools = append(tools, "ninja")
+		}
+	}
+	if fileExists(path, "Makefile") {
+		configFiles = append(configFiles, "Makefile")
+		tools = append(tools, "make")
+	}
+	for _, name := range []string{"conanfile.txt", "conanfile.py"} {
+		if fileExists(path, name) {
+			configFiles = append(configFiles, name)
+			if !containsTool(tools, "conan") {
+				tools = append(tools, "conan")
+			}
+		}
+	}
+	if fileExists(path, "vcpkg.json") {
+		configFiles = append(configFiles, "vcpkg.json")
+	}
+	return configFiles, tools
+}
+
+func containsTool(tools []string, tool string) bool {
+	for _, t := range tools {
+		if t == tool {
+			return true
+		}
+	}
+	return false
+}
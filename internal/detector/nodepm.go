@@ -0,0 +1,96 @@
+package detector
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// nodeLockfiles maps lockfiles to the package manager that writes them, in
+// the order they are preferred when several are present
+var nodeLockfiles = []struct {
+	File    string
+	Manager string
+}{
+	{"pnpm-lock.yaml", "pnpm"},
+	{"yarn.lock", "yarn"},
+	{"bun.lockb", "bun"},
+	{"bun.lock", "bun"},
+	{"package-lock.json", "npm"},
+	{"npm-shrinkwrap.json", "npm"},
+}
+
+var yarnPathRe = regexp.MustCompile(`(?m)^yarnPath:\s*["']?([^"'\s]+)`)
+
+// NodePackageManager describes the package manager a Node.js project uses
+type NodePackageManager struct {
+	Name      string   // npm, yarn, pnpm or bun
+	Version   string   // version pinned by packageManager or yarnPath, if any
+	Source    string   // where the choice came from, e.g. "packageManager" or "yarn.lock"
+	Lockfiles []string // all lockfiles present in the project
+	YarnPath  string   // yarnPath from .yarnrc.yml (Yarn Berry)
+}
+
+// IsYarnBerry reports whether the project uses Yarn 2 or later
+func (pm NodePackageManager) IsYarnBerry() bool {
+	if pm.Name != "yarn" {
+		return false
+	}
+	if pm.YarnPath != "" {
+		return true
+	}
+	return pm.Version != "" && !strings.HasPrefix(pm.Version, "1.")
+}
+
+// DetectNodePackageManager determines the package manager of the Node.js
+// project at path. The Corepack packageManager field in package.json wins,
+// then the lockfiles, then Yarn Berry settings, and npm is the fallback.
+func DetectNodePackageManager(path string) NodePackageManager {
+	pm := NodePackageManager{}
+
+	for _, lock := range nodeLockfiles {
+		if fileExists(path, lock.File) {
+			pm.Lockfiles = append(pm.Lockfiles, lock.File)
+			if pm.Name == "" {
+				pm.Name = lock.Manager
+				pm.Source = lock.File
+			}
+		}
+	}
+
+	if data, err := os.ReadFile(filepath.Join(path, ".yarnrc.yml")); err == nil {
+		if m := yarnPathRe.FindSubmatch(data); m != nil {
+			pm.YarnPath = string(m[1])
+		}
+		if pm.Name == "" {
+			pm.Name = "yarn"
+			pm.Source = ".yarnrc.yml"
+		}
+	}
+
+	// Corepack: "packageManager": "pnpm@8.15.4+sha256.abc..."
+	if data, err := os.ReadFile(filepath.Join(path, "package.json")); err == nil {
+		var packageJSON struct {
+			PackageManager string `json:"packageManager"`
+		}
+		if json.Unmarshal(data, &packageJSON) == nil && packageJSON.PackageManager != "" {
+			name, version, _ := strings.Cut(packageJSON.PackageManager, "@")
+			version, _, _ = strings.Cut(version, "+")
+			pm.Name = name
+			pm.Version = version
+			pm.Source = "packageManager"
+		}
+	}
+
+	if pm.Name == "" {
+		pm.Name = "npm"
+	}
+	if pm.Name == "yarn" && pm.Version == "" && pm.YarnPath != "" {
+		// .yarn/releases/yarn-4.1.0.cjs
+		base := strings.TrimSuffix(filepath.Base(pm.YarnPath), filepath.Ext(pm.YarnPath))
+		pm.Version = strings.TrimPrefix(base, "yarn-")
+	}
+	return pm
+}
@@ -0,0 +1,74 @@
+package detector
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDetectNodePackageManager(t *testing.T) {
+	tests := []struct {
+		name        string
+		files       map[string]string
+		wantName    string
+		wantVersion string
+		wantLocks   int
+		wantBerry   bool
+	}{
+		{"default npm", map[string]string{"package.json": "{}"}, "npm", "", 0, false},
+		{"package-lock", map[string]string{"package.json": "{}", "package-lock.json": "{}"}, "npm", "", 1, false},
+		{"pnpm lockfile", map[string]string{"package.json": "{}", "pnpm-lock.yaml": ""}, "pnpm", "", 1, false},
+		{"bun lockfile", map[string]string{"package.json": "{}", "bun.lockb": ""}, "bun", "", 1, false},
+		{"classic yarn", map[string]string{"package.json": "{}", "yarn.lock": ""}, "yarn", "", 1, false},
+		{
+			"corepack wins",
+			map[string]string{"package.json": `{"packageManager": "pnpm@8.15.4+sha256.abcdef"}`, "package-lock.json": "{}"},
+			"pnpm", "8.15.4", 1, false,
+		},
+		{
+			"yarn berry yarnPath",
+			map[string]string{"package.json": "{}", "yarn.lock": "", ".yarnrc.yml": "nodeLinker: node-modules\nyarnPath: .yarn/releases/yarn-4.1.0.cjs\n"},
+			"yarn", "4.1.0", 1, true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			for file, content := range tt.files {
+				if err := os.WriteFile(filepath.Join(tmpDir, file), []byte(content), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			pm := DetectNodePackageManager(tmpDir)
+			if pm.Name != tt.wantName || pm.Version != tt.wantVersion {
+				t.Errorf("Got %s@%s, want %s@%s", pm.Name, pm.Version, tt.wantName, tt.wantVersion)
+			}
+			if len(pm.Lockfiles) != tt.wantLocks {
+				t.Errorf("Expected %d lockfiles, got %v", tt.wantLocks, pm.Lockfiles)
+			}
+			if pm.IsYarnBerry() != tt.wantBerry {
+				t.Errorf("IsYarnBerry() = %v, want %v", pm.IsYarnBerry(), tt.wantBerry)
+			}
+		})
+	}
+}
+
+func TestDetectNodeJSRequiresPackageManager(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmpDir, "package.json"), []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "pnpm-lock.yaml"), []byte(""), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	project := detectNodeJS(tmpDir)
+	if project == nil {
+		t.Fatal("Expected Node.js project to be detected")
+	}
+	if len(project.RequiredTools) != 2 || project.RequiredTools[1] != "pnpm" {
+		t.Errorf("Expected node and pnpm to be required, got %v", project.RequiredTools)
+	}
+}
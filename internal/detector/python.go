@@ -0,0 +1,77 @@
+package detector
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var (
+	pyprojectPoetryRe = regexp.MustCompile(`(?m)^\[tool\.poetry[.\]]`)
+	pyprojectHatchRe  = regexp.MustCompile(`(?m)^\[tool\.hatch[.\]]`)
+	pyprojectUvRe     = regexp.MustCompile(`(?m)^\[tool\.uv[.\]]`)
+	condaNameRe       = regexp.MustCompile(`(?m)^name:\s*["']?([^"'#\s]+)`)
+)
+
+// PythonToolchain describes how a Python project manages its environment
+type PythonToolchain struct {
+	Manager  string // pip, poetry, pipenv, uv, hatch or conda
+	Source   string // file the choice was based on
+	CondaEnv string // environment name from environment.yml
+}
+
+// pythonEnvironmentFiles are the Conda environment files, in lookup order
+var pythonEnvironmentFiles = []string{"environment.yml", "environment.yaml"}
+
+// DetectPythonToolchain determines the environment manager of the Python
+// project at path from its lockfiles and pyproject.toml tool tables
+func DetectPythonToolchain(path string) PythonToolchain {
+	switch {
+	case fileExists(path, "poetry.lock"):
+		return PythonToolchain{Manager: "poetry", Source: "poetry.lock"}
+	case fileExists(path, "uv.lock"):
+		return PythonToolchain{Manager: "uv", Source: "uv.lock"}
+	case fileExists(path, "Pipfile.lock"):
+		return PythonToolchain{Manager: "pipenv", Source: "Pipfile.lock"}
+	case fileExists(path, "Pipfile"):
+		return PythonToolchain{Manager: "pipenv", Source: "Pipfile"}
+	}
+
+	if data, err := os.ReadFile(filepath.Join(path, "pyproject.toml")); err == nil {
+		switch {
+		case pyprojectPoetryRe.Match(data):
+			return PythonToolchain{Manager: "poetry", Source: "pyproject.toml"}
+		case pyprojectHatchRe.Match(data):
+			return PythonToolchain{Manager: "hatch", Source: "pyproject.toml"}
+		case pyprojectUvRe.Match(data):
+			return PythonToolchain{Manager: "uv", Source: "pyproject.toml"}
+		}
+	}
+
+	for _, file := range pythonEnvironmentFiles {
+		if data, err := os.ReadFile(filepath.Join(path, file)); err == nil {
+			tc := PythonToolchain{Manager: "conda", Source: file}
+			if m := condaNameRe.FindSubmatch(data); m != nil {
+				tc.CondaEnv = strings.TrimSpace(string(m[1]))
+			}
+			return tc
+		}
+	}
+
+	return PythonToolchain{Manager: "pip"}
+}
+
+// pythonRequiredTools returns the tools needed to set up a project managed
+// by the given toolchain
+func pythonRequiredTools(tc PythonToolchain) []string {
+	switch tc.Manager {
+	case "pip":
+		return []string{"python", "pip"}
+	case "conda", "uv":
+		// Both provision the Python interpreter themselves
+		return []string{tc.Manager}
+	default:
+		return []string{"python", tc.Manager}
+	}
+}
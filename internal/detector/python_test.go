@@ -0,0 +1,57 @@
+package detector
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDetectPythonToolchain(t *testing.T) {
+	tests := []struct {
+		name      string
+		files     map[string]string
+		want      string
+		wantConda string
+		wantTools []string
+	}{
+		{"requirements", map[string]string{"requirements.txt": ""}, "pip", "", []string{"python", "pip"}},
+		{"poetry lock", map[string]string{"pyproject.toml": "", "poetry.lock": ""}, "poetry", "", []string{"python", "poetry"}},
+		{"poetry table", map[string]string{"pyproject.toml": "[tool.poetry]\nname = \"app\"\n"}, "poetry", "", []string{"python", "poetry"}},
+		{"pipenv", map[string]string{"Pipfile": "", "Pipfile.lock": ""}, "pipenv", "", []string{"python", "pipenv"}},
+		{"uv", map[string]string{"pyproject.toml": "", "uv.lock": ""}, "uv", "", []string{"uv"}},
+		{"hatch", map[string]string{"pyproject.toml": "[project]\nname = \"app\"\n\n[tool.hatch.envs.default]\n"}, "hatch", "", []string{"python", "hatch"}},
+		{"conda", map[string]string{"environment.yml": "name: data-science\nchannels:\n  - conda-forge\n"}, "conda", "data-science", []string{"conda"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			for file, content := range tt.files {
+				if err := os.WriteFile(filepath.Join(tmpDir, file), []byte(content), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			tc := DetectPythonToolchain(tmpDir)
+			if tc.Manager != tt.want {
+				t.Errorf("Expected manager %s, got %s", tt.want, tc.Manager)
+			}
+			if tc.CondaEnv != tt.wantConda {
+				t.Errorf("Expected conda env %q, got %q", tt.wantConda, tc.CondaEnv)
+			}
+
+			project := detectPython(tmpDir)
+			if project == nil {
+				t.Fatal("Expected Python project to be detected")
+			}
+			if len(project.RequiredTools) != len(tt.wantTools) {
+				t.Fatalf("Expected tools %v, got %v", tt.wantTools, project.RequiredTools)
+			}
+			for i, tool := range tt.wantTools {
+				if project.RequiredTools[i] != tool {
+					t.Errorf("Expected tools %v, got %v", tt.wantTools, project.RequiredTools)
+				}
+			}
+		})
+	}
+}
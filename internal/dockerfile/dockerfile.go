@@ -0,0 +1,208 @@
+// Package dockerfile parses Dockerfiles into instructions and build stages
+// and matches paths against .dockerignore patterns.
+package dockerfile
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+)
+
+// Instruction is a single Dockerfile instruction
+type Instruction struct {
+	Cmd   string            // upper-cased instruction, e.g. "COPY"
+	Flags map[string]string // --from=builder, --chown=app, ...
+	Args  []string          // arguments after the flags
+	Raw   string            // arguments as written, continuation lines joined
+	Line  int               // line the instruction starts on
+}
+
+// Stage is a build stage started by FROM
+type Stage struct {
+	Name         string // AS name, lower-cased; "" if unnamed
+	Index        int
+	From         *Instruction
+	Image        string // image or stage the stage is based on
+	Instructions []*Instruction
+}
+
+// Dockerfile is a parsed Dockerfile. Instructions before the first FROM
+// (global ARGs) are kept in Global.
+type Dockerfile struct {
+	Global []*Instruction
+	Stages []*Stage
+}
+
+var (
+	directiveRe = regexp.MustCompile(`^#\s*([a-zA-Z]+)\s*=\s*(\S+)\s*$`)
+	heredocRe   = regexp.MustCompile(`<<-?\s*["']?([A-Za-z_][A-Za-z0-9_]*)["']?`)
+)
+
+// Parse parses a Dockerfile. It handles the escape parser directive, line
+// continuations, comments, JSON (exec form) arguments and heredocs.
+func Parse(data []byte) *Dockerfile {
+	lines := strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n")
+	escape := byte('\\')
+
+	// Parser directives must come first, before any comment or instruction
+	for _, line := range lines {
+		m := directiveRe.FindStringSubmatch(strings.TrimSpace(line))
+		if m == nil {
+			break
+		}
+		if strings.ToLower(m[1]) == "escape" && len(m[2]) == 1 {
+			escape = m[2][0]
+		}
+	}
+
+	df := &Dockerfile{}
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		start := i
+		// Join continuation lines; comment lines inside are dropped
+		for strings.HasSuffix(line, string(escape)) && i+1 < len(lines) {
+			line = strings.TrimSuffix(line, string(escape))
+			i++
+			next := strings.TrimSpace(lines[i])
+			if strings.HasPrefix(next, "#") {
+				line += string(escape)
+				continue
+			}
+			line += " " + next
+		}
+
+		cmd, rest, _ := strings.Cut(line, " ")
+		inst := &Instruction{Cmd: strings.ToUpper(cmd), Flags: map[string]string{}, Raw: strings.TrimSpace(rest), Line: start + 1}
+
+		// Heredoc bodies are skipped up to their delimiter. A shell
+		// here-string (<<< word) has no body.
+		for _, m := range heredocRe.FindAllStringSubmatchIndex(inst.Raw, -1) {
+			if m[0] > 0 && inst.Raw[m[0]-1] == '<' || strings.HasPrefix(inst.Raw[m[0]:], "<<<") {
+				continue
+			}
+			for i+1 < len(lines) {
+				i++
+				if strings.TrimSpace(lines[i]) == inst.Raw[m[2]:m[3]] {
+					break
+				}
+			}
+		}
+
+		args := inst.Raw
+		for strings.HasPrefix(args, "--") {
+			flag, remaining, _ := strings.Cut(args, " ")
+			name, value, _ := strings.Cut(strings.TrimPrefix(flag, "--"), "=")
+			inst.Flags[strings.ToLower(name)] = value
+			args = strings.TrimSpace(remaining)
+		}
+		var list []string
+		if strings.HasPrefix(args, "[") && json.Unmarshal([]byte(args), &list) == nil {
+			inst.Args = list
+		} else {
+			inst.Args = strings.Fields(args)
+		}
+
+		if inst.Cmd == "FROM" {
+			stage := &Stage{Index: len(df.Stages), From: inst}
+			if len(inst.Args) > 0 {
+				stage.Image = inst.Args[0]
+			}
+			if len(inst.Args) >= 3 && strings.EqualFold(inst.Args[1], "AS") {
+				stage.Name = strings.ToLower(inst.Args[2])
+			}
+			df.Stages = append(df.Stages, stage)
+			continue
+		}
+		if len(df.Stages) == 0 {
+			df.Global = append(df.Global, inst)
+		} else {
+			stage := df.Stages[len(df.Stages)-1]
+			stage.Instructions = append(stage.Instructions, inst)
+		}
+	}
+	return df
+}
+
+// StageByName returns the stage named name, or nil
+func (df *Dockerfile) StageByName(name string) *Stage {
+	for _, stage := range df.Stages {
+		if stage.Name != "" && stage.Name == strings.ToLower(name) {
+			return stage
+		}
+	}
+	return nil
+}
+
+var varRefRe = regexp.MustCompile(`\\?\$(?:\{([A-Za-z_][A-Za-z0-9_]*)|([A-Za-z_][A-Za-z0-9_]*))`)
+
+// Variables returns the names of the variables s refers to as $NAME or
+// ${NAME...}; escaped references (\$NAME) are skipped
+func Variables(s string) []string {
+	names := []string{}
+	for _, m := range varRefRe.FindAllStringSubmatch(s, -1) {
+		if strings.HasPrefix(m[0], `\`) {
+			continue
+		}
+		names = append(names, m[1]+m[2])
+	}
+	return names
+}
+
+var expandRe = regexp.MustCompile(`\$(?:\{([A-Za-z_][A-Za-z0-9_]*)(?::?([-+])([^}]*))?\}|([A-Za-z_][A-Za-z0-9_]*))`)
+
+// Expand substitutes $NAME, ${NAME}, ${NAME:-default} and ${NAME:+alt}
+// with values from vars. It reports false when a variable without a
+// default has no value.
+func Expand(s string, vars map[string]string) (string, bool) {
+	ok := true
+	result := expandRe.ReplaceAllStringFunc(s, func(ref string) string {
+		m := expandRe.FindStringSubmatch(ref)
+		name := m[1] + m[4]
+		value, set := vars[name]
+		switch m[2] {
+		case "-":
+			if !set || value == "" {
+				return m[3]
+			}
+			return value
+		case "+":
+			if set && value != "" {
+				return m[3]
+			}
+			return ""
+		}
+		if !set {
+			ok = false
+		}
+		return value
+	})
+	return result, ok
+}
+
+// Declared returns the variable names an ARG or ENV instruction declares,
+// with their values (empty for an ARG without a default)
+func (inst *Instruction) Declared() map[string]string {
+	vars := map[string]string{}
+	switch inst.Cmd {
+	case "ARG":
+		for _, arg := range inst.Args {
+			name, value, _ := strings.Cut(arg, "=")
+			vars[name] = strings.Trim(value, `"'`)
+		}
+	case "ENV":
+		// ENV NAME value is the legacy form of ENV NAME=value
+		if len(inst.Args) >= 2 && !strings.Contains(inst.Args[0], "=") {
+			vars[inst.Args[0]] = strings.Join(inst.Args[1:], " ")
+			break
+		}
+		for _, arg := range inst.Args {
+			if name, value, ok := strings.Cut(arg, "="); ok {
+				vars[name] = strings.Trim(value, `"'`)
+			}
+		}
+	}
+	return vars
+}
@@ -0,0 +1,120 @@
+package dockerfile
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	df := Parse([]byte(`# syntax=docker/dockerfile:1
+ARG NODE_VERSION=20
+
+FROM node:${NODE_VERSION} AS Build
+WORKDIR /app
+COPY --chown=node:node package.json \
+     package-lock.json ./
+RUN <<EOF
+npm ci
+COPY not-an-instruction .
+EOF
+RUN ["npm", "run", "build"]
+
+FROM nginx:1.25
+COPY --from=build /app/dist /usr/share/nginx/html
+`))
+	if len(df.Global) != 1 || df.Global[0].Cmd != "ARG" {
+		t.Fatalf("Global = %+v", df.Global)
+	}
+	if len(df.Stages) != 2 {
+		t.Fatalf("parsed %d stages, want 2", len(df.Stages))
+	}
+
+	build := df.Stages[0]
+	if build.Name != "build" || build.Image != "node:${NODE_VERSION}" || df.StageByName("BUILD") != build {
+		t.Errorf("stage 0 = %+v", build)
+	}
+	cmds := []string{}
+	for _, inst := range build.Instructions {
+		cmds = append(cmds, inst.Cmd)
+	}
+	if !reflect.DeepEqual(cmds, []string{"WORKDIR", "COPY", "RUN", "RUN"}) {
+		t.Errorf("instructions = %v", cmds)
+	}
+	copyInst := build.Instructions[1]
+	if copyInst.Flags["chown"] != "node:node" || !reflect.DeepEqual(copyInst.Args, []string{"package.json", "package-lock.json", "./"}) || copyInst.Line != 6 {
+		t.Errorf("COPY = %+v", copyInst)
+	}
+	if args := build.Instructions[3].Args; !reflect.DeepEqual(args, []string{"npm", "run", "build"}) {
+		t.Errorf("exec form args = %q", args)
+	}
+	if from := df.Stages[1].Instructions[0].Flags["from"]; from != "build" {
+		t.Errorf("--from = %q", from)
+	}
+}
+
+func TestParseHereString(t *testing.T) {
+	df := Parse([]byte(`FROM alpine:3.19
+RUN cat <<< "x" > /tmp/x && grep -q x <<<$VALUE
+COPY config.yml /etc/app/
+`))
+	insts := df.Stages[0].Instructions
+	if len(insts) != 2 || insts[1].Cmd != "COPY" || insts[1].Line != 3 {
+		t.Errorf("instructions after a here-string = %+v", insts)
+	}
+}
+
+func TestParseEscapeDirective(t *testing.T) {
+	df := Parse([]byte("# escape=`\nFROM mcr.microsoft.com/windows/servercore:ltsc2022\nCOPY app\\ `\n  C:\\app\\\n"))
+	inst := df.Stages[0].Instructions[0]
+	if !reflect.DeepEqual(inst.Args, []string{`app\`, `C:\app\`}) {
+		t.Errorf("COPY args = %q", inst.Args)
+	}
+}
+
+func TestExpand(t *testing.T) {
+	vars := map[string]string{"VERSION": "1.2", "EMPTY": ""}
+	tests := []struct {
+		in, want string
+		ok       bool
+	}{
+		{"app-$VERSION.tar.gz", "app-1.2.tar.gz", true},
+		{"${VERSION:+v}${VERSION}", "v1.2", true},
+		{"${EMPTY:-default}", "default", true},
+		{"${MISSING}/bin", "/bin", false},
+	}
+	for _, tt := range tests {
+		got, ok := Expand(tt.in, vars)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("Expand(%q) = %q, %v; want %q, %v", tt.in, got, ok, tt.want, tt.ok)
+		}
+	}
+	if got := Variables(`$A ${B:-x} \$C`); !reflect.DeepEqual(got, []string{"A", "B"}) {
+		t.Errorf("Variables() = %v", got)
+	}
+}
+
+func TestIgnore(t *testing.T) {
+	ig := ParseIgnore([]byte(`# comment
+node_modules
+/.git
+**/*.log
+dist/*
+!dist/keep.txt
+`))
+	tests := map[string]bool{
+		"node_modules":              true,
+		"node_modules/react/x.js":   true,
+		".git/HEAD":                 true,
+		"src/app.js":                false,
+		"logs/debug.log":            true,
+		"server.log":                true,
+		"dist/bundle.js":            true,
+		"dist/keep.txt":             false,
+		"packages/a/node_modules/b": false,
+	}
+	for name, want := range tests {
+		if got := ig.Excluded(name); got != want {
+			t.Errorf("Excluded(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package dockerfile
+
+import (
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Ignore holds the patterns of a .dockerignore file
+type Ignore struct {
+	patterns []ignorePattern
+}
+
+type ignorePattern struct {
+	pattern string
+	exclude bool // false for "!" exceptions
+}
+
+// ParseIgnore parses a .dockerignore file. Patterns are relative to the
+// build context; a leading "/" is dropped and "!" marks an exception.
+func ParseIgnore(data []byte) *Ignore {
+	ignore := &Ignore{}
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		p := ignorePattern{exclude: true}
+		if strings.HasPrefix(line, "!") {
+			p.exclude = false
+			line = strings.TrimSpace(line[1:])
+		}
+		line = path.Clean(strings.TrimPrefix(filepath.ToSlash(line), "/"))
+		if line == "." {
+			continue
+		}
+		p.pattern = line
+		ignore.patterns = append(ignore.patterns, p)
+	}
+	return ignore
+}
+
+// Excluded reports whether a path relative to the build context is left
+// out of the context. A pattern that matches a directory excludes its
+// contents; the last matching pattern wins.
+func (ig *Ignore) Excluded(rel string) bool {
+	if ig == nil {
+		return false
+	}
+	rel = path.Clean(filepath.ToSlash(rel))
+	excluded := false
+	for _, p := range ig.patterns {
+		if matchesOrParent(p.pattern, rel) {
+			excluded = p.exclude
+		}
+	}
+	return excluded
+}
+
+// matchesOrParent reports whether pattern matches rel or one of its parent
+// directories
+func matchesOrParent(pattern, rel string) bool {
+	for {
+		if match(pattern, rel) {
+			return true
+		}
+		i := strings.LastIndex(rel, "/")
+		if i < 0 {
+			return false
+		}
+		rel = rel[:i]
+	}
+}
+
+// match matches a path against a pattern in which "**" matches any number
+// of directories
+func match(pattern, name string) bool {
+	if !strings.Contains(pattern, "**") {
+		ok, _ := path.Match(pattern, name)
+		return ok
+	}
+	pp, np := strings.Split(pattern, "/"), strings.Split(name, "/")
+	return matchSegments(pp, np)
+}
+
+func matchSegments(pp, np []string) bool {
+	for len(pp) > 0 {
+		if pp[0] == "**" {
+			for i := 0; i <= len(np); i++ {
+				if matchSegments(pp[1:], np[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(np) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pp[0], np[0]); !ok {
+			return false
+		}
+		pp, np = pp[1:], np[1:]
+	}
+	return len(np) == 0
+}
@@ -0,0 +1,120 @@
+// Package dotenv reads .env files the way Docker Compose and the dotenv
+// libraries do: KEY=VALUE lines with an optional "export" prefix, comments,
+// and single- or double-quoted values that may span lines.
+package dotenv
+
+import (
+	"os"
+	"strings"
+)
+
+// Entry is a variable assignment in a .env file
+type Entry struct {
+	Key   string
+	Value string
+	Line  int
+}
+
+// Parse returns the assignments of a .env file in file order. Lines that
+// are not assignments are skipped.
+func Parse(data []byte) []Entry {
+	entries := []Entry{}
+	lines := strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n")
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		if i == 0 {
+			line = strings.TrimPrefix(line, "\ufeff")
+		}
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+		key, value, ok := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" || strings.ContainsAny(key, " \t") {
+			continue
+		}
+		entry := Entry{Key: key, Line: i + 1}
+		value = strings.TrimLeft(value, " \t")
+
+		closed := false
+		if strings.HasPrefix(value, `"`) || strings.HasPrefix(value, "'") {
+			var quoted string
+			var last int
+			if quoted, last, closed = readQuoted(lines, i, value); closed {
+				i = last
+				entry.Value = quoted
+			}
+		}
+		if !closed {
+			// An unquoted value ends at a comment preceded by whitespace.
+			// So does one with an unterminated quote, which is kept as a
+			// literal character.
+			if j := strings.Index(value, " #"); j >= 0 {
+				value = value[:j]
+			}
+			if j := strings.Index(value, "\t#"); j >= 0 {
+				value = value[:j]
+			}
+			entry.Value = strings.TrimSpace(value)
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// readQuoted reads the quoted value that starts line i, which may continue
+// on the following lines. It returns the value, the index of its last line
+// and false if the quote is never closed.
+func readQuoted(lines []string, i int, value string) (string, int, bool) {
+	quote := value[:1]
+	value = value[1:]
+	for !hasClosingQuote(value, quote) {
+		if i+1 >= len(lines) {
+			return "", 0, false
+		}
+		i++
+		value += "\n" + lines[i]
+	}
+	value = value[:closingQuote(value, quote)]
+	if quote == `"` {
+		value = unescape(value)
+	}
+	return value, i, true
+}
+
+func hasClosingQuote(s, quote string) bool {
+	return closingQuote(s, quote) >= 0
+}
+
+// closingQuote returns the index of the quote that ends s, skipping
+// backslash escapes in double-quoted values
+func closingQuote(s, quote string) int {
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '\\' && quote == `"`:
+			i++
+		case s[i] == quote[0]:
+			return i
+		}
+	}
+	return -1
+}
+
+func unescape(s string) string {
+	replacer := strings.NewReplacer(`\n`, "\n", `\r`, "\r", `\t`, "\t", `\"`, `"`, `\\`, `\`)
+	return replacer.Replace(s)
+}
+
+// Read parses the .env file at path into a map; later assignments win
+func Read(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	values := map[string]string{}
+	for _, entry := range Parse(data) {
+		values[entry.Key] = entry.Value
+	}
+	return values, nil
+}
@@ -0,0 +1,71 @@
+package dotenv
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	data := []byte(`# database
+DB_HOST=localhost
+export DB_PORT=5432 # default port
+DB_PASSWORD="p@ss \"word\""
+GREETING='hello # not a comment'
+CERT="-----BEGIN-----
+abc
+-----END-----"
+EMPTY=
+not an assignment
+URL=http://example.com/#anchor
+`)
+	got := map[string]string{}
+	lines := map[string]int{}
+	for _, entry := range Parse(data) {
+		got[entry.Key] = entry.Value
+		lines[entry.Key] = entry.Line
+	}
+	want := map[string]string{
+		"DB_HOST":     "localhost",
+		"DB_PORT":     "5432",
+		"DB_PASSWORD": `p@ss "word"`,
+		"GREETING":    "hello # not a comment",
+		"CERT":        "-----BEGIN-----\nabc\n-----END-----",
+		"EMPTY":       "",
+		"URL":         "http://example.com/#anchor",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Parse() = %q, want %q", got, want)
+	}
+	if lines["EMPTY"] != 9 {
+		t.Errorf("EMPTY is on line %d, want 9", lines["EMPTY"])
+	}
+}
+
+func TestParseUnterminatedQuote(t *testing.T) {
+	entries := Parse([]byte("A=\"foo\nB=2\nC='bar # note\nD=4\n"))
+	got := map[string]string{}
+	for _, entry := range entries {
+		got[entry.Key] = entry.Value
+	}
+	want := map[string]string{"A": `"foo`, "B": "2", "C": "'bar", "D": "4"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Parse() = %q, want %q", got, want)
+	}
+}
+
+func TestRead(t *testing.T) {
+	file := filepath.Join(t.TempDir(), ".env")
+	os.WriteFile(file, []byte("A=1\nA=2\n"), 0644)
+	values, err := Read(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if values["A"] != "2" {
+		t.Errorf("A = %q, want the last assignment", values["A"])
+	}
+	if _, err := Read(filepath.Join(t.TempDir(), ".env")); err == nil {
+		t.Error("Read() of a missing file should fail")
+	}
+}
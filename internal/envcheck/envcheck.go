@@ -2,7 +2,9 @@ package envcheck
 
 import (
 	"fmt"
+	"os"
 	"os/exec"
+	"regexp"
 	"strings"
 )
 
@@ -12,6 +14,7 @@ type Tool struct {
 	Args    []string
 	Parse   func(string) string // parses version output
 	Min     string              // minimum recommended version
+	Env     []string            // variables added to the environment of the command
 }
 
 type ToolStatus struct {
@@ -26,6 +29,9 @@ var tools = []Tool{
 		Name:    "Go",
 		Command: "go",
 		Args:    []string{"version"},
+		// Report the installed toolchain, not one a go.mod toolchain
+		// directive would switch to
+		Env: []string{"GOTOOLCHAIN=local"},
 		Parse: func(out string) string {
 			parts := strings.Fields(out)
 			if len(parts) >= 3 {
@@ -124,24 +130,70 @@ var tools = []Tool{
 func CheckAll() []ToolStatus {
 	var results []ToolStatus
 	for _, t := range tools {
-		cmd := exec.Command(t.Command, t.Args...)
-		out, err := cmd.CombinedOutput()
-		status := ToolStatus{Name: t.Name}
-		if err == nil {
-			status.Found = true
-			status.Version = t.Parse(string(out))
-			if t.Min != "" && status.Version != "" && compareVersion(status.Version, t.Min) < 0 {
-				status.Warn = fmt.Sprintf("Version %s is below recommended %s", status.Version, t.Min)
-			}
-		} else {
-			status.Found = false
-			status.Warn = "Not found"
-		}
-		results = append(results, status)
+		results = append(results, check(t, ""))
 	}
 	return results
 }
 
+var (
+	lookupCache   = map[string]ToolStatus{}
+	genericParser = regexp.MustCompile(`\d+(\.\d+)+`)
+)
+
+// Lookup returns the status of the tool invoked as command. Tools in the
+// built-in table are parsed with their own parser; any other command is run
+// with --version and the first dotted version number in its output is used.
+// Results are cached for the lifetime of the process.
+func Lookup(command string) ToolStatus {
+	return LookupIn("", command)
+}
+
+// LookupIn is Lookup with the command run from dir, so version manager
+// shims (rustup, pyenv, asdf, mise, dotnet) resolve the toolchain that dir
+// pins. Results are cached per directory.
+func LookupIn(dir, command string) ToolStatus {
+	key := dir + "\x00" + command
+	if status, ok := lookupCache[key]; ok {
+		return status
+	}
+	t := Tool{
+		Name:    command,
+		Command: command,
+		Args:    []string{"--version"},
+		Parse:   func(out string) string { return genericParser.FindString(out) },
+	}
+	for _, known := range tools {
+		if known.Command == command {
+			t = known
+			break
+		}
+	}
+	status := check(t, dir)
+	lookupCache[key] = status
+	return status
+}
+
+func check(t Tool, dir string) ToolStatus {
+	cmd := exec.Command(t.Command, t.Args...)
+	cmd.Dir = dir
+	if len(t.Env) > 0 {
+		cmd.Env = append(os.Environ(), t.Env...)
+	}
+	out, err := cmd.CombinedOutput()
+	status := ToolStatus{Name: t.Name}
+	if err == nil {
+		status.Found = true
+		status.Version = t.Parse(string(out))
+		if t.Min != "" && status.Version != "" && compareVersion(status.Version, t.Min) < 0 {
+			status.Warn = fmt.Sprintf("Version %s is below recommended %s", status.Version, t.Min)
+		}
+	} else {
+		status.Found = false
+		status.Warn = "Not found"
+	}
+	return status
+}
+
 // compareVersion returns -1 if a < b, 0 if a == b, 1 if a > b
 func compareVersion(a, b string) int {
 	aParts := strings.Split(a, ".")
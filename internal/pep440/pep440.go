@@ -0,0 +1,257 @@
+// Package pep440 parses Python package versions and evaluates version
+// specifiers such as ">=3.9,<4" and "~=2.1" as defined by PEP 440.
+package pep440
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Version is a PEP 440 version. The epoch and local label are dropped.
+type Version struct {
+	Release []int
+	Pre     string // "a", "b" or "rc"
+	PreNum  int
+	Post    int // -1 if not a post-release
+	Dev     int // -1 if not a development release
+	raw     string
+}
+
+func (v Version) String() string { return v.raw }
+
+var versionRe = regexp.MustCompile(`^v?(?:\d+!)?(\d+(?:\.\d+)*)` +
+	`(?:[-_.]?(a|b|c|rc|alpha|beta|pre|preview)[-_.]?(\d*))?` +
+	`(?:-(\d+)|[-_.]?(post|rev|r)[-_.]?(\d*))?` +
+	`(?:[-_.]?(dev)[-_.]?(\d*))?` +
+	`(?:\+[a-z0-9]+(?:[-_.][a-z0-9]+)*)?$`)
+
+// Parse parses a version such as "3.11.4", "2.0rc1" or "1.0.post2"
+func Parse(s string) (Version, error) {
+	s = strings.TrimSpace(s)
+	m := versionRe.FindStringSubmatch(strings.ToLower(s))
+	if m == nil {
+		return Version{}, fmt.Errorf("invalid version %q", s)
+	}
+
+	v := Version{Post: -1, Dev: -1, raw: s}
+	for _, part := range strings.Split(m[1], ".") {
+		n, _ := strconv.Atoi(part)
+		v.Release = append(v.Release, n)
+	}
+	switch m[2] {
+	case "":
+	case "alpha":
+		v.Pre = "a"
+	case "beta":
+		v.Pre = "b"
+	case "c", "pre", "preview":
+		v.Pre = "rc"
+	default:
+		v.Pre = m[2]
+	}
+	v.PreNum, _ = strconv.Atoi(m[3])
+	switch {
+	case m[4] != "":
+		v.Post, _ = strconv.Atoi(m[4])
+	case m[5] != "":
+		v.Post, _ = strconv.Atoi(m[6])
+	}
+	if m[7] != "" {
+		v.Dev, _ = strconv.Atoi(m[8])
+	}
+	return v, nil
+}
+
+// Compare returns -1, 0 or 1 if a is lower than, equal to or greater than b.
+// Development releases sort before pre-releases, which sort before the
+// final release, which sorts before post-releases.
+func Compare(a, b Version) int {
+	if c := compareRelease(a.Release, b.Release); c != 0 {
+		return c
+	}
+	for _, d := range [][2]int{
+		{a.preRank(), b.preRank()},
+		{a.PreNum, b.PreNum},
+		{a.Post, b.Post},
+		{a.devRank(), b.devRank()},
+	} {
+		if d[0] != d[1] {
+			return sign(d[0] - d[1])
+		}
+	}
+	return 0
+}
+
+func (v Version) preRank() int {
+	switch v.Pre {
+	case "a":
+		return -3
+	case "b":
+		return -2
+	case "rc":
+		return -1
+	}
+	if v.Dev >= 0 && v.Post < 0 {
+		return -4 // 1.0.dev1 < 1.0a1
+	}
+	return 0
+}
+
+func (v Version) devRank() int {
+	if v.Dev < 0 {
+		return int(^uint(0) >> 1)
+	}
+	return v.Dev
+}
+
+// compareRelease compares release segments, padding the shorter with zeros
+func compareRelease(a, b []int) int {
+	for i := 0; i < len(a) || i < len(b); i++ {
+		var x, y int
+		if i < len(a) {
+			x = a[i]
+		}
+		if i < len(b) {
+			y = b[i]
+		}
+		if x != y {
+			return sign(x - y)
+		}
+	}
+	return 0
+}
+
+func sign(n int) int {
+	switch {
+	case n < 0:
+		return -1
+	case n > 0:
+		return 1
+	}
+	return 0
+}
+
+// Specifier is a comma-separated list of clauses that must all match
+type Specifier struct {
+	clauses []clause
+	raw     string
+}
+
+type clause struct {
+	op       string
+	version  Version
+	wildcard bool // ==1.2.* and !=1.2.*
+	literal  string
+}
+
+func (s Specifier) String() string { return s.raw }
+
+var clauseRe = regexp.MustCompile(`^(===|~=|==|!=|<=|>=|<|>)\s*(\S+)$`)
+
+// ParseSpecifier parses a specifier such as ">=3.9, <4" or "==2.*". An empty
+// specifier matches every version.
+func ParseSpecifier(s string) (Specifier, error) {
+	spec := Specifier{raw: strings.TrimSpace(s)}
+	if spec.raw == "" {
+		return spec, nil
+	}
+	for _, part := range strings.Split(spec.raw, ",") {
+		m := clauseRe.FindStringSubmatch(strings.TrimSpace(part))
+		if m == nil {
+			return Specifier{}, fmt.Errorf("invalid version specifier %q", strings.TrimSpace(part))
+		}
+		c := clause{op: m[1], literal: m[2]}
+		if c.op == "===" {
+			spec.clauses = append(spec.clauses, c)
+			continue
+		}
+		text := m[2]
+		if strings.HasSuffix(text, ".*") {
+			if c.op != "==" && c.op != "!=" {
+				return Specifier{}, fmt.Errorf("invalid version specifier %q: wildcards need == or !=", part)
+			}
+			c.wildcard = true
+			text = strings.TrimSuffix(text, ".*")
+		}
+		v, err := Parse(text)
+		if err != nil {
+			return Specifier{}, err
+		}
+		if c.op == "~=" && len(v.Release) < 2 {
+			return Specifier{}, fmt.Errorf("invalid version specifier %q: ~= needs at least two release segments", part)
+		}
+		c.version = v
+		spec.clauses = append(spec.clauses, c)
+	}
+	return spec, nil
+}
+
+// Contains reports whether v satisfies every clause. Pre-releases are
+// accepted like any other version.
+func (s Specifier) Contains(v Version) bool {
+	for _, c := range s.clauses {
+		if !c.matches(v) {
+			return false
+		}
+	}
+	return true
+}
+
+func (c clause) matches(v Version) bool {
+	switch c.op {
+	case "===":
+		return strings.EqualFold(v.raw, c.literal)
+	case "==":
+		if c.wildcard {
+			return hasReleasePrefix(v, c.version.Release)
+		}
+		return Compare(v, c.version) == 0
+	case "!=":
+		if c.wildcard {
+			return !hasReleasePrefix(v, c.version.Release)
+		}
+		return Compare(v, c.version) != 0
+	case "~=":
+		prefix := c.version.Release[:len(c.version.Release)-1]
+		return Compare(v, c.version) >= 0 && hasReleasePrefix(v, prefix)
+	case "<":
+		return Compare(v, c.version) < 0
+	case "<=":
+		return Compare(v, c.version) <= 0
+	case ">":
+		return Compare(v, c.version) > 0
+	case ">=":
+		return Compare(v, c.version) >= 0
+	}
+	return false
+}
+
+// hasReleasePrefix reports whether the release of v starts with prefix,
+// padding v with zeros
+func hasReleasePrefix(v Version, prefix []int) bool {
+	for i, n := range prefix {
+		segment := 0
+		if i < len(v.Release) {
+			segment = v.Release[i]
+		}
+		if segment != n {
+			return false
+		}
+	}
+	return true
+}
+
+// Satisfies reports whether version matches the specifier
+func Satisfies(version, specifier string) (bool, error) {
+	v, err := Parse(version)
+	if err != nil {
+		return false, err
+	}
+	s, err := ParseSpecifier(specifier)
+	if err != nil {
+		return false, err
+	}
+	return s.Contains(v), nil
+}
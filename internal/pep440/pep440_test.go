@@ -0,0 +1,86 @@
+package pep440
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in      string
+		release []int
+		pre     string
+		post    int
+		dev     int
+	}{
+		{"3.11.4", []int{3, 11, 4}, "", -1, -1},
+		{"2.0rc1", []int{2, 0}, "rc", -1, -1},
+		{"1.0.0-beta.2", []int{1, 0, 0}, "b", -1, -1},
+		{"1.0.post2", []int{1, 0}, "", 2, -1},
+		{"1.0-1", []int{1, 0}, "", 1, -1},
+		{"1.1.dev0", []int{1, 1}, "", -1, 0},
+		{"1!2.0+local.7", []int{2, 0}, "", -1, -1},
+	}
+	for _, tt := range tests {
+		v, err := Parse(tt.in)
+		if err != nil {
+			t.Errorf("Parse(%q) failed: %v", tt.in, err)
+			continue
+		}
+		if compareRelease(v.Release, tt.release) != 0 || len(v.Release) != len(tt.release) || v.Pre != tt.pre || v.Post != tt.post || v.Dev != tt.dev {
+			t.Errorf("Parse(%q) = %+v", tt.in, v)
+		}
+	}
+	if _, err := Parse("latest"); err == nil {
+		t.Error("Expected an error for a non-version")
+	}
+}
+
+func TestCompare(t *testing.T) {
+	ordered := []string{"1.0.dev0", "1.0a1", "1.0a2.dev1", "1.0a2", "1.0b1", "1.0rc1", "1.0", "1.0.post1", "1.0.1", "1.1"}
+	for i := 0; i+1 < len(ordered); i++ {
+		a, _ := Parse(ordered[i])
+		b, _ := Parse(ordered[i+1])
+		if Compare(a, b) != -1 || Compare(b, a) != 1 {
+			t.Errorf("Expected %s < %s", ordered[i], ordered[i+1])
+		}
+	}
+	a, _ := Parse("1.0")
+	b, _ := Parse("1.0.0")
+	if Compare(a, b) != 0 {
+		t.Error("Expected 1.0 == 1.0.0")
+	}
+}
+
+func TestSatisfies(t *testing.T) {
+	tests := []struct {
+		version   string
+		specifier string
+		want      bool
+	}{
+		{"3.11.4", ">=3.9", true},
+		{"3.8.10", ">=3.9", false},
+		{"3.11.4", ">=3.9, <3.11", false},
+		{"3.12.0", "==3.12.*", true},
+		{"3.13.0", "==3.12.*", false},
+		{"3.12.0", "!=3.12.*", false},
+		{"2.2.1", "~=2.1", true},
+		{"3.0", "~=2.1", false},
+		{"2.1.9", "~=2.1.3", true},
+		{"2.2.0", "~=2.1.3", false},
+		{"4.2.1", "==4.2.1", true},
+		{"4.2", "==4.2.0", true},
+		{"4.2.2", "==4.2.1", false},
+		{"1.0", "", true},
+		{"1.0+abc", "===1.0+abc", true},
+	}
+	for _, tt := range tests {
+		got, err := Satisfies(tt.version, tt.specifier)
+		if err != nil || got != tt.want {
+			t.Errorf("Satisfies(%q, %q) = %v, %v; want %v", tt.version, tt.specifier, got, err, tt.want)
+		}
+	}
+
+	for _, bad := range []string{"^3.9", "3.9", ">=3.*", "~=3"} {
+		if _, err := ParseSpecifier(bad); err == nil {
+			t.Errorf("ParseSpecifier(%q) succeeded, want error", bad)
+		}
+	}
+}
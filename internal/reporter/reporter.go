@@ -18,16 +18,65 @@ func Report(path string, projects []*detector.ProjectType, issues []checker.Issu
 	fmt.Printf("Scanning: %s\n", path)
 	fmt.Println()
 
+	roots := projectRoots(projects)
+
 	// Show detected project types
 	fmt.Println("📋 Detected Project Types:")
 	for _, project := range projects {
-		fmt.Printf("  ✓ %s\n", project.Name)
+		label := project.Name
+		if len(roots) > 1 {
+			label += fmt.Sprintf(" (%s)", displayRoot(project.Root))
+		}
+		if project.Confidence > 0 && project.Confidence < 1 {
+			label += fmt.Sprintf(" [confidence %.0f%%]", project.Confidence*100)
+		}
+		fmt.Printf("  ✓ %s\n", label)
 		for _, configFile := range project.ConfigFiles {
 			fmt.Printf("    - %s\n", configFile)
 		}
 	}
 	fmt.Println()
 
+	// Group issues by sub-project when more than one root was scanned
+	var errors, warnings, infos int
+	if len(roots) > 1 {
+		groups := groupIssues(roots, issues)
+		if !containsRoot(roots, ".") {
+			roots = append([]string{"."}, roots...)
+		}
+		for _, root := range roots {
+			rootIssues := groups[root]
+			if len(rootIssues) == 0 {
+				continue
+			}
+			fmt.Printf("📁 %s\n", displayRoot(root))
+			fmt.Println(strings.Repeat("═", 65))
+			e, w, i := reportIssues(rootIssues)
+			errors, warnings, infos = errors+e, warnings+w, infos+i
+		}
+	} else {
+		errors, warnings, infos = reportIssues(issues)
+	}
+
+	// Summary
+	fmt.Println(strings.Repeat("═", 65))
+	if len(issues) == 0 {
+		fmt.Println("✅ No issues found! Your project should be ready to run.")
+	} else {
+		fmt.Printf("Summary: %d error(s), %d warning(s), %d info\n",
+			errors, warnings, infos)
+		if errors > 0 {
+			fmt.Println("\n⚠️  Please resolve the errors above before running the project.")
+		} else if warnings > 0 {
+			fmt.Println("\n⚠️  Consider addressing the warnings to ensure smooth operation.")
+		}
+	}
+	fmt.Println(strings.Repeat("═", 65))
+}
+
+// reportIssues prints issues grouped by severity and returns the number of
+// errors, warnings and infos printed.
+func reportIssues(issues []checker.Issue) (int, int, int) {
 	// Group issues by severity
 	errors := []checker.Issue{}
 	warnings := []checker.Issue{}
@@ -77,18 +126,48 @@ func Report(path string, projects []*detector.ProjectType, issues []checker.Issu
 		fmt.Println()
 	}
 
-	// Summary
-	fmt.Println(strings.Repeat("═", 65))
-	if len(issues) == 0 {
-		fmt.Println("✅ No issues found! Your project should be ready to run.")
-	} else {
-		fmt.Printf("Summary: %d error(s), %d warning(s), %d info\n",
-			len(errors), len(warnings), len(infos))
-		if len(errors) > 0 {
-			fmt.Println("\n⚠️  Please resolve the errors above before running the project.")
-		} else if len(warnings) > 0 {
-			fmt.Println("\n⚠️  Consider addressing the warnings to ensure smooth operation.")
+	return len(errors), len(warnings), len(infos)
+}
+
+// groupIssues groups issues by project root. Issues that belong to no
+// project root, such as those about the git repository, go under "."
+func groupIssues(roots []string, issues []checker.Issue) map[string][]checker.Issue {
+	groups := map[string][]checker.Issue{}
+	for _, issue := range issues {
+		root := issue.Root
+		if !containsRoot(roots, root) {
+			root = "."
 		}
+		groups[root] = append(groups[root], issue)
 	}
-	fmt.Println(strings.Repeat("═", 65))
+	return groups
+}
+
+func containsRoot(roots []string, root string) bool {
+	for _, r := range roots {
+		if r == root {
+			return true
+		}
+	}
+	return false
+}
+
+// projectRoots returns the distinct project roots in detection order
+func projectRoots(projects []*detector.ProjectType) []string {
+	seen := map[string]bool{}
+	roots := []string{}
+	for _, project := range projects {
+		if !seen[project.Root] {
+			seen[project.Root] = true
+			roots = append(roots, project.Root)
+		}
+	}
+	return roots
+}
+
+func displayRoot(root string) string {
+	if root == "" || root == "." {
+		return "./"
+	}
+	return "./" + root
 }
@@ -0,0 +1,53 @@
+package reporter
+
+import (
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/Sw3bbl3/devdoctor/internal/checker"
+	"github.com/Sw3bbl3/devdoctor/internal/detector"
+)
+
+// captureReport returns what Report prints
+func captureReport(t *testing.T, projects []*detector.ProjectType, issues []checker.Issue) string {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	Report("/repo", projects, issues)
+	w.Close()
+	out, _ := io.ReadAll(r)
+	return string(out)
+}
+
+func TestReportRepositoryIssuesWithSeveralRoots(t *testing.T) {
+	projects := []*detector.ProjectType{
+		{Name: "Go", Root: "api"},
+		{Name: "Go", Root: "worker"},
+	}
+	issues := []checker.Issue{
+		{Severity: checker.SeverityWarning, ProjectType: "Git", Root: ".", Message: "Git hooks configured in .pre-commit-config.yaml are not installed: pre-commit"},
+		{Severity: checker.SeverityError, ProjectType: "Go", Root: "worker", Message: "Dependencies not downloaded"},
+	}
+
+	out := captureReport(t, projects, issues)
+	for _, want := range []string{
+		"📁 ./\n",
+		"[Git] Git hooks configured in .pre-commit-config.yaml are not installed",
+		"📁 ./worker\n",
+		"Summary: 1 error(s), 1 warning(s), 0 info",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Report() output is missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Index(out, "[Git]") > strings.Index(out, "📁 ./worker") {
+		t.Errorf("Report() printed repository issues after the sub-projects:\n%s", out)
+	}
+}
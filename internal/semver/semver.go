@@ -0,0 +1,345 @@
+// Package semver parses versions and evaluates npm-style version ranges:
+// comparators, ^ and ~ ranges, x-ranges, hyphen ranges and || unions.
+package semver
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Version is a semantic version. Build metadata is dropped.
+type Version struct {
+	Major, Minor, Patch int
+	Prerelease          string
+}
+
+func (v Version) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if v.Prerelease != "" {
+		s += "-" + v.Prerelease
+	}
+	return s
+}
+
+var versionRe = regexp.MustCompile(`^[v=]*\s*(\d+)(?:\.(\d+))?(?:\.(\d+))?(?:-([0-9A-Za-z.-]+))?(?:\+[0-9A-Za-z.-]+)?`)
+
+// Parse parses a version leniently: missing minor and patch numbers are
+// zero and anything after the version (such as "_292" in "1.8.0_292") is
+// ignored, so tool output can be parsed directly.
+func Parse(s string) (Version, error) {
+	m := versionRe.FindStringSubmatch(strings.TrimSpace(s))
+	if m == nil {
+		return Version{}, fmt.Errorf("invalid version %q", s)
+	}
+	v := Version{Prerelease: m[4]}
+	v.Major, _ = strconv.Atoi(m[1])
+	v.Minor, _ = strconv.Atoi(m[2])
+	v.Patch, _ = strconv.Atoi(m[3])
+	return v, nil
+}
+
+// Compare returns -1, 0 or 1 if a is lower than, equal to or greater than b.
+// A prerelease sorts before the release it precedes.
+func Compare(a, b Version) int {
+	for _, d := range []int{a.Major - b.Major, a.Minor - b.Minor, a.Patch - b.Patch} {
+		if d < 0 {
+			return -1
+		}
+		if d > 0 {
+			return 1
+		}
+	}
+	return comparePrerelease(a.Prerelease, b.Prerelease)
+}
+
+func comparePrerelease(a, b string) int {
+	switch {
+	case a == b:
+		return 0
+	case a == "":
+		return 1
+	case b == "":
+		return -1
+	}
+	ap, bp := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(ap) && i < len(bp); i++ {
+		an, aErr := strconv.Atoi(ap[i])
+		bn, bErr := strconv.Atoi(bp[i])
+		switch {
+		case aErr == nil && bErr == nil:
+			if an != bn {
+				return sign(an - bn)
+			}
+		case aErr == nil:
+			return -1 // numeric identifiers sort first
+		case bErr == nil:
+			return 1
+		default:
+			if c := strings.Compare(ap[i], bp[i]); c != 0 {
+				return c
+			}
+		}
+	}
+	return sign(len(ap) - len(bp))
+}
+
+func sign(n int) int {
+	switch {
+	case n < 0:
+		return -1
+	case n > 0:
+		return 1
+	}
+	return 0
+}
+
+// comparator is a single primitive constraint such as ">=1.2.3"
+type comparator struct {
+	op string // <, <=, >, >=, = or !=
+	v  Version
+}
+
+func (c comparator) matches(v Version) bool {
+	cmp := Compare(v, c.v)
+	switch c.op {
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	case "!=":
+		return cmp != 0
+	}
+	return cmp == 0
+}
+
+// Range is a parsed version range: a union of comparator sets that must
+// all match.
+type Range struct {
+	raw  string
+	sets [][]comparator
+}
+
+func (r Range) String() string { return r.raw }
+
+// Contains reports whether v satisfies the range. As in npm, a prerelease
+// only satisfies a range that names a prerelease of the same version.
+func (r Range) Contains(v Version) bool {
+	for _, set := range r.sets {
+		if setContains(set, v) {
+			return true
+		}
+	}
+	return false
+}
+
+func setContains(set []comparator, v Version) bool {
+	for _, c := range set {
+		if !c.matches(v) {
+			return false
+		}
+	}
+	if v.Prerelease == "" {
+		return true
+	}
+	for _, c := range set {
+		if c.v.Prerelease != "" && c.v.Major == v.Major && c.v.Minor == v.Minor && c.v.Patch == v.Patch {
+			return true
+		}
+	}
+	return false
+}
+
+// Satisfies reports whether version satisfies the range constraint
+func Satisfies(version, constraint string) (bool, error) {
+	v, err := Parse(version)
+	if err != nil {
+		return false, err
+	}
+	r, err := ParseRange(constraint)
+	if err != nil {
+		return false, err
+	}
+	return r.Contains(v), nil
+}
+
+var (
+	opSpaceRe = regexp.MustCompile(`(~>|<=|>=|!=|[<>=~^])\s+`)
+	hyphenRe  = regexp.MustCompile(`^(\S+)\s+-\s+(\S+)$`)
+	partialRe = regexp.MustCompile(`^[v=]*(\d+|[xX*])(?:\.(\d+|[xX*]))?(?:\.(\d+|[xX*]))?(?:-([0-9A-Za-z.-]+))?(?:\+[0-9A-Za-z.-]+)?$`)
+)
+
+// ParseRange parses an npm-style range such as "^1.2.3 || >=2.0.0 <3",
+// "~1.2", "1.x", "1.2.3 - 2.3.4" or "*"
+func ParseRange(s string) (Range, error) {
+	r := Range{raw: s}
+	for _, part := range strings.Split(s, "||") {
+		set, err := parseSet(strings.TrimSpace(part))
+		if err != nil {
+			return Range{}, err
+		}
+		r.sets = append(r.sets, set)
+	}
+	return r, nil
+}
+
+func parseSet(s string) ([]comparator, error) {
+	if m := hyphenRe.FindStringSubmatch(s); m != nil {
+		lower, err := parsePartial(m[1])
+		if err != nil {
+			return nil, err
+		}
+		upper, err := parsePartial(m[2])
+		if err != nil {
+			return nil, err
+		}
+		set := []comparator{}
+		if lower.parts > 0 {
+			set = append(set, comparator{">=", lower.floor()})
+		}
+		switch {
+		case upper.parts == 3:
+			set = append(set, comparator{"<=", upper.floor()})
+		case upper.parts > 0:
+			set = append(set, comparator{"<", upper.bump(upper.parts - 1)})
+		}
+		return set, nil
+	}
+
+	set := []comparator{}
+	for _, token := range strings.Fields(opSpaceRe.ReplaceAllString(s, "$1")) {
+		comparators, err := parseComparator(token)
+		if err != nil {
+			return nil, err
+		}
+		set = append(set, comparators...)
+	}
+	return set, nil
+}
+
+// partial is a version whose trailing components may be missing or x
+type partial struct {
+	nums       [3]int
+	parts      int // number of concrete leading components
+	prerelease string
+}
+
+func parsePartial(s string) (partial, error) {
+	m := partialRe.FindStringSubmatch(s)
+	if m == nil {
+		return partial{}, fmt.Errorf("invalid version %q in range", s)
+	}
+	p := partial{prerelease: m[4]}
+	for i := 0; i < 3; i++ {
+		n, err := strconv.Atoi(m[i+1])
+		if err != nil {
+			break // empty or wildcard: the rest is wildcard too
+		}
+		p.nums[i] = n
+		p.parts++
+	}
+	return p, nil
+}
+
+func (p partial) floor() Version {
+	v := Version{Major: p.nums[0], Minor: p.nums[1], Patch: p.nums[2]}
+	if p.parts == 3 {
+		v.Prerelease = p.prerelease
+	}
+	return v
+}
+
+// bump returns the lowest prerelease of the version after p with the
+// component at index incremented, e.g. bump(1) of 1.2 is 1.3.0-0
+func (p partial) bump(index int) Version {
+	nums := p.nums
+	nums[index]++
+	for i := index + 1; i < 3; i++ {
+		nums[i] = 0
+	}
+	return Version{Major: nums[0], Minor: nums[1], Patch: nums[2], Prerelease: "0"}
+}
+
+func parseComparator(token string) ([]comparator, error) {
+	op := ""
+	for _, candidate := range []string{"<=", ">=", "!=", "~>", "<", ">", "=", "~", "^"} {
+		if strings.HasPrefix(token, candidate) {
+			op = candidate
+			break
+		}
+	}
+	p, err := parsePartial(token[len(op):])
+	if err != nil {
+		return nil, err
+	}
+	anyVersion := []comparator{{">=", Version{}}}
+
+	switch op {
+	case "~", "~>":
+		// ~1.2.3 := >=1.2.3 <1.3.0-0, ~1 := >=1.0.0 <2.0.0-0
+		if p.parts == 0 {
+			return anyVersion, nil
+		}
+		index := 1
+		if p.parts == 1 {
+			index = 0
+		}
+		return []comparator{{">=", p.floor()}, {"<", p.bump(index)}}, nil
+	case "^":
+		// Bump the first non-zero component, or the last specified one
+		if p.parts == 0 {
+			return anyVersion, nil
+		}
+		index := p.parts - 1
+		for i := 0; i < p.parts; i++ {
+			if p.nums[i] != 0 {
+				index = i
+				break
+			}
+		}
+		if index == 2 && p.parts < 3 {
+			index = 1
+		}
+		return []comparator{{">=", p.floor()}, {"<", p.bump(index)}}, nil
+	case "", "=":
+		if p.parts == 0 {
+			return anyVersion, nil
+		}
+		if p.parts == 3 {
+			return []comparator{{"=", p.floor()}}, nil
+		}
+		return []comparator{{">=", p.floor()}, {"<", p.bump(p.parts - 1)}}, nil
+	case "!=":
+		if p.parts < 3 {
+			return nil, fmt.Errorf("!= needs a full version, got %q", token)
+		}
+		return []comparator{{"!=", p.floor()}}, nil
+	}
+
+	// <, <=, >, >= with a partial version
+	if p.parts == 0 {
+		if op == "<" || op == ">" {
+			return []comparator{{"<", Version{}}}, nil // matches nothing
+		}
+		return anyVersion, nil
+	}
+	if p.parts == 3 {
+		return []comparator{{op, p.floor()}}, nil
+	}
+	switch op {
+	case ">":
+		return []comparator{{">=", p.bump(p.parts - 1)}}, nil
+	case "<=":
+		return []comparator{{"<", p.bump(p.parts - 1)}}, nil
+	case "<":
+		v := p.floor()
+		v.Prerelease = "0"
+		return []comparator{{"<", v}}, nil
+	}
+	return []comparator{{">=", p.floor()}}, nil
+}
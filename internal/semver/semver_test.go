@@ -0,0 +1,103 @@
+package semver
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Version
+	}{
+		{"1.2.3", Version{1, 2, 3, ""}},
+		{"v18.17.1", Version{18, 17, 1, ""}},
+		{"3.11", Version{3, 11, 0, ""}},
+		{"21", Version{21, 0, 0, ""}},
+		{"1.8.0_292", Version{1, 8, 0, ""}},
+		{"2.0.0-rc.1+build.5", Version{2, 0, 0, "rc.1"}},
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.in)
+		if err != nil || got != tt.want {
+			t.Errorf("Parse(%q) = %v, %v; want %v", tt.in, got, err, tt.want)
+		}
+	}
+	if _, err := Parse("lts/*"); err == nil {
+		t.Error("Expected an error for a non-version")
+	}
+}
+
+func TestCompare(t *testing.T) {
+	ordered := []string{"1.0.0-0", "1.0.0-alpha", "1.0.0-alpha.1", "1.0.0-alpha.beta", "1.0.0-beta.2", "1.0.0-beta.11", "1.0.0-rc.1", "1.0.0", "1.0.1", "1.1.0", "2.0.0"}
+	for i := 0; i+1 < len(ordered); i++ {
+		a, _ := Parse(ordered[i])
+		b, _ := Parse(ordered[i+1])
+		if Compare(a, b) != -1 || Compare(b, a) != 1 {
+			t.Errorf("Expected %s < %s", ordered[i], ordered[i+1])
+		}
+	}
+}
+
+func TestSatisfies(t *testing.T) {
+	tests := []struct {
+		version    string
+		constraint string
+		want       bool
+	}{
+		{"18.17.1", ">=18", true},
+		{"16.20.0", ">=18", false},
+		{"18.17.1", "^18.0.0", true},
+		{"19.0.0", "^18.0.0", false},
+		{"0.2.5", "^0.2.3", true},
+		{"0.3.0", "^0.2.3", false},
+		{"0.0.4", "^0.0.3", false},
+		{"1.9.0", "^1.2.x", true},
+		{"0.9.0", "^0.x", true},
+		{"1.2.9", "~1.2.3", true},
+		{"1.3.0", "~1.2.3", false},
+		{"1.9.0", "~1", true},
+		{"1.2.9", "~> 1.2", true},
+		{"1.5.0", "1.x", true},
+		{"2.0.0", "1.x", false},
+		{"1.2.7", "1.2.*", true},
+		{"5.0.0", "*", true},
+		{"5.0.0", "", true},
+		{"20.10.0", "^18 || ^20", true},
+		{"19.1.0", "^18 || ^20", false},
+		{"18.5.0", ">=16 <19", true},
+		{"19.0.0", ">=16 <19", false},
+		{"18.5.0", ">= 16.0.0 < 19.0.0", true},
+		{"2.3.4", "1.2.3 - 2.3.4", true},
+		{"2.3.5", "1.2.3 - 2.3.4", false},
+		{"2.3.9", "1.2 - 2.3", true},
+		{"2.4.0", "1.2 - 2.3", false},
+		{"1.1.9", "1.2 - 2.3", false},
+		{"1.2.3", "=1.2.3", true},
+		{"1.2.4", "1.2.3", false},
+		{"1.3.0", ">1.2", true},
+		{"1.2.9", ">1.2", false},
+		{"1.2.9", "<=1.2", true},
+		{"1.3.0", "<=1.2", false},
+		{"1.1.9", "<1.2", true},
+		{"1.2.0", "<1.2", false},
+		{"20.0.0-nightly", ">=18", false},
+		{"20.0.0-rc.2", ">=20.0.0-rc.1", true},
+		{"1.2.4", "!=1.2.3", true},
+	}
+	for _, tt := range tests {
+		got, err := Satisfies(tt.version, tt.constraint)
+		if err != nil {
+			t.Errorf("Satisfies(%q, %q) error: %v", tt.version, tt.constraint, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Satisfies(%q, %q) = %v, want %v", tt.version, tt.constraint, got, tt.want)
+		}
+	}
+}
+
+func TestParseRangeErrors(t *testing.T) {
+	for _, constraint := range []string{"latest", ">=abc", "^1.2.3.4", "!=1.2"} {
+		if _, err := ParseRange(constraint); err == nil {
+			t.Errorf("ParseRange(%q) expected an error", constraint)
+		}
+	}
+}
@@ -0,0 +1,524 @@
+// Package toml implements a small TOML decoder covering the subset of the
+// format used by project manifests (Cargo.toml, pyproject.toml, mise.toml,
+// rust-toolchain.toml, lockfiles).
+package toml
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Decode parses a TOML document into nested maps. Tables decode to
+// map[string]interface{}, arrays to []interface{}, strings to string,
+// integers to int64, floats to float64 and booleans to bool. Dates and
+// times are kept as strings.
+func Decode(data []byte) (map[string]interface{}, error) {
+	p := &parser{src: string(data), line: 1}
+	root := map[string]interface{}{}
+	if err := p.parse(root); err != nil {
+		return nil, fmt.Errorf("toml: line %d: %v", p.line, err)
+	}
+	return root, nil
+}
+
+// Lookup returns the value at the given key path, or nil. Arrays of tables
+// resolve to their last element, like a TOML table header does.
+func Lookup(doc map[string]interface{}, path ...string) interface{} {
+	var current interface{} = doc
+	for _, key := range path {
+		if arr, ok := current.([]interface{}); ok && len(arr) > 0 {
+			current = arr[len(arr)-1]
+		}
+		table, ok := current.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		current = table[key]
+	}
+	return current
+}
+
+// String returns the string at the given key path, or "".
+func String(doc map[string]interface{}, path ...string) string {
+	s, _ := Lookup(doc, path...).(string)
+	return s
+}
+
+// Strings returns the strings of the array at the given key path. A single
+// string is returned as a one-element slice.
+func Strings(doc map[string]interface{}, path ...string) []string {
+	switch v := Lookup(doc, path...).(type) {
+	case string:
+		return []string{v}
+	case []interface{}:
+		out := []string{}
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				out = append(out, s)
+			}
+		}
+		return out
+	}
+	return nil
+}
+
+// Table returns the table at the given key path, or nil.
+func Table(doc map[string]interface{}, path ...string) map[string]interface{} {
+	t, _ := Lookup(doc, path...).(map[string]interface{})
+	return t
+}
+
+// Tables returns the array of tables at the given key path, e.g. the
+// [[package]] entries of Cargo.lock.
+func Tables(doc map[string]interface{}, path ...string) []map[string]interface{} {
+	arr, _ := Lookup(doc, path...).([]interface{})
+	out := []map[string]interface{}{}
+	for _, item := range arr {
+		if t, ok := item.(map[string]interface{}); ok {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+type parser struct {
+	src  string
+	pos  int
+	line int
+}
+
+func (p *parser) parse(root map[string]interface{}) error {
+	current := root
+	for {
+		p.skipBlank(true)
+		if p.eof() {
+			return nil
+		}
+
+		if p.peek() == '[' {
+			arrayTable := strings.HasPrefix(p.src[p.pos:], "[[")
+			if arrayTable {
+				p.pos += 2
+			} else {
+				p.pos++
+			}
+			p.skipBlank(false)
+			keys, err := p.parseKey()
+			if err != nil {
+				return err
+			}
+			p.skipBlank(false)
+			closing := "]"
+			if arrayTable {
+				closing = "]]"
+			}
+			if !strings.HasPrefix(p.src[p.pos:], closing) {
+				return fmt.Errorf("expected %q after table name", closing)
+			}
+			p.pos += len(closing)
+
+			if arrayTable {
+				parent, err := tableAt(root, keys[:len(keys)-1])
+				if err != nil {
+					return err
+				}
+				last := keys[len(keys)-1]
+				arr, _ := parent[last].([]interface{})
+				if parent[last] != nil && arr == nil {
+					return fmt.Errorf("key %q is not an array of tables", strings.Join(keys, "."))
+				}
+				current = map[string]interface{}{}
+				parent[last] = append(arr, current)
+			} else {
+				if current, err = tableAt(root, keys); err != nil {
+					return err
+				}
+			}
+		} else {
+			if err := p.parseKeyValue(current); err != nil {
+				return err
+			}
+		}
+
+		p.skipBlank(false)
+		if !p.eof() && p.peek() != '\n' && p.peek() != '\r' {
+			return fmt.Errorf("unexpected %q at end of line", p.peek())
+		}
+	}
+}
+
+// tableAt returns the table at keys below root, creating missing tables
+func tableAt(root map[string]interface{}, keys []string) (map[string]interface{}, error) {
+	current := root
+	for _, key := range keys {
+		switch v := current[key].(type) {
+		case nil:
+			next := map[string]interface{}{}
+			current[key] = next
+			current = next
+		case map[string]interface{}:
+			current = v
+		case []interface{}:
+			if len(v) == 0 {
+				return nil, fmt.Errorf("key %q is an empty array", key)
+			}
+			table, ok := v[len(v)-1].(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("key %q is not a table", key)
+			}
+			current = table
+		default:
+			return nil, fmt.Errorf("key %q is not a table", key)
+		}
+	}
+	return current, nil
+}
+
+func (p *parser) parseKeyValue(table map[string]interface{}) error {
+	keys, err := p.parseKey()
+	if err != nil {
+		return err
+	}
+	p.skipBlank(false)
+	if p.eof() || p.peek() != '=' {
+		return fmt.Errorf("expected '=' after key %q", strings.Join(keys, "."))
+	}
+	p.pos++
+	p.skipBlank(false)
+	value, err := p.parseValue()
+	if err != nil {
+		return err
+	}
+	parent, err := tableAt(table, keys[:len(keys)-1])
+	if err != nil {
+		return err
+	}
+	parent[keys[len(keys)-1]] = value
+	return nil
+}
+
+func (p *parser) parseKey() ([]string, error) {
+	keys := []string{}
+	for {
+		p.skipBlank(false)
+		if p.eof() {
+			return nil, fmt.Errorf("unexpected end of input in key")
+		}
+		var key string
+		var err error
+		switch p.peek() {
+		case '"':
+			key, err = p.parseBasicString()
+		case '\'':
+			key, err = p.parseLiteralString()
+		default:
+			start := p.pos
+			for !p.eof() && isBareKeyChar(p.peek()) {
+				p.pos++
+			}
+			if start == p.pos {
+				return nil, fmt.Errorf("invalid key character %q", p.peek())
+			}
+			key = p.src[start:p.pos]
+		}
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+		p.skipBlank(false)
+		if p.eof() || p.peek() != '.' {
+			return keys, nil
+		}
+		p.pos++
+	}
+}
+
+func (p *parser) parseValue() (interface{}, error) {
+	if p.eof() {
+		return nil, fmt.Errorf("missing value")
+	}
+	rest := p.src[p.pos:]
+	switch {
+	case strings.HasPrefix(rest, `"""`):
+		return p.parseMultilineString(`"""`, true)
+	case strings.HasPrefix(rest, "'''"):
+		return p.parseMultilineString("'''", false)
+	case rest[0] == '"':
+		return p.parseBasicString()
+	case rest[0] == '\'':
+		return p.parseLiteralString()
+	case rest[0] == '[':
+		return p.parseArray()
+	case rest[0] == '{':
+		return p.parseInlineTable()
+	}
+
+	start := p.pos
+	for !p.eof() && !strings.ContainsRune(" \t\r\n,]}#", rune(p.peek())) {
+		p.pos++
+	}
+	// Local date-times may use a space instead of "T"
+	if !p.eof() && p.peek() == ' ' && p.pos+1 < len(p.src) && isDigit(p.src[p.pos+1]) && strings.Count(p.src[start:p.pos], "-") == 2 {
+		p.pos++
+		for !p.eof() && !strings.ContainsRune(" \t\r\n,]}#", rune(p.peek())) {
+			p.pos++
+		}
+	}
+	token := p.src[start:p.pos]
+	switch token {
+	case "":
+		return nil, fmt.Errorf("missing value")
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	case "inf", "+inf", "-inf", "nan", "+nan", "-nan":
+		f, _ := strconv.ParseFloat(strings.TrimPrefix(token, "+"), 64)
+		return f, nil
+	}
+	number := strings.ReplaceAll(token, "_", "")
+	if i, err := parseInteger(number); err == nil {
+		return i, nil
+	}
+	if f, err := strconv.ParseFloat(number, 64); err == nil {
+		return f, nil
+	}
+	if isDigit(token[0]) {
+		// Offset date-time, local date or local time
+		return token, nil
+	}
+	return nil, fmt.Errorf("invalid value %q", token)
+}
+
+// parseInteger parses decimal, hexadecimal, octal and binary integers.
+// Decimal integers may not have leading zeros.
+func parseInteger(s string) (int64, error) {
+	digits := strings.TrimLeft(s, "+-")
+	if len(digits) > 1 && digits[0] == '0' && !strings.ContainsRune("xob", rune(digits[1])) {
+		return 0, fmt.Errorf("leading zero in %q", s)
+	}
+	return strconv.ParseInt(s, 0, 64)
+}
+
+func (p *parser) parseArray() (interface{}, error) {
+	p.pos++ // [
+	arr := []interface{}{}
+	for {
+		p.skipBlank(true)
+		if p.eof() {
+			return nil, fmt.Errorf("unterminated array")
+		}
+		if p.peek() == ']' {
+			p.pos++
+			return arr, nil
+		}
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		arr = append(arr, value)
+		p.skipBlank(true)
+		if p.eof() {
+			return nil, fmt.Errorf("unterminated array")
+		}
+		switch p.peek() {
+		case ',':
+			p.pos++
+		case ']':
+		default:
+			return nil, fmt.Errorf("expected ',' or ']' in array, got %q", p.peek())
+		}
+	}
+}
+
+func (p *parser) parseInlineTable() (interface{}, error) {
+	p.pos++ // {
+	table := map[string]interface{}{}
+	p.skipBlank(false)
+	if !p.eof() && p.peek() == '}' {
+		p.pos++
+		return table, nil
+	}
+	for {
+		p.skipBlank(false)
+		if err := p.parseKeyValue(table); err != nil {
+			return nil, err
+		}
+		p.skipBlank(false)
+		if p.eof() {
+			return nil, fmt.Errorf("unterminated inline table")
+		}
+		switch p.peek() {
+		case ',':
+			p.pos++
+		case '}':
+			p.pos++
+			return table, nil
+		default:
+			return nil, fmt.Errorf("expected ',' or '}' in inline table, got %q", p.peek())
+		}
+	}
+}
+
+func (p *parser) parseBasicString() (string, error) {
+	p.pos++ // "
+	var sb strings.Builder
+	for {
+		if p.eof() || p.peek() == '\n' {
+			return "", fmt.Errorf("unterminated string")
+		}
+		c := p.peek()
+		switch c {
+		case '"':
+			p.pos++
+			return sb.String(), nil
+		case '\\':
+			if err := p.parseEscape(&sb); err != nil {
+				return "", err
+			}
+		default:
+			sb.WriteByte(c)
+			p.pos++
+		}
+	}
+}
+
+func (p *parser) parseLiteralString() (string, error) {
+	p.pos++ // '
+	end := strings.IndexAny(p.src[p.pos:], "'\n")
+	if end < 0 || p.src[p.pos+end] != '\'' {
+		return "", fmt.Errorf("unterminated string")
+	}
+	s := p.src[p.pos : p.pos+end]
+	p.pos += end + 1
+	return s, nil
+}
+
+func (p *parser) parseMultilineString(delim string, escapes bool) (string, error) {
+	p.pos += len(delim)
+	// A newline immediately after the opening delimiter is trimmed
+	if strings.HasPrefix(p.src[p.pos:], "\r\n") {
+		p.pos += 2
+		p.line++
+	} else if strings.HasPrefix(p.src[p.pos:], "\n") {
+		p.pos++
+		p.line++
+	}
+
+	var sb strings.Builder
+	for {
+		if p.eof() {
+			return "", fmt.Errorf("unterminated multi-line string")
+		}
+		if strings.HasPrefix(p.src[p.pos:], delim) {
+			p.pos += len(delim)
+			// Up to two quotes may directly precede the closing delimiter
+			for i := 0; i < 2 && !p.eof() && p.peek() == delim[0]; i++ {
+				sb.WriteByte(delim[0])
+				p.pos++
+			}
+			return sb.String(), nil
+		}
+		c := p.peek()
+		if c == '\n' {
+			p.line++
+		}
+		if escapes && c == '\\' {
+			// A line-ending backslash trims the newline and leading whitespace
+			rest := strings.TrimLeft(p.src[p.pos+1:], " \t")
+			if strings.HasPrefix(rest, "\n") || strings.HasPrefix(rest, "\r\n") {
+				p.pos++
+				for !p.eof() && strings.ContainsRune(" \t\r\n", rune(p.peek())) {
+					if p.peek() == '\n' {
+						p.line++
+					}
+					p.pos++
+				}
+				continue
+			}
+			if err := p.parseEscape(&sb); err != nil {
+				return "", err
+			}
+			continue
+		}
+		sb.WriteByte(c)
+		p.pos++
+	}
+}
+
+func (p *parser) parseEscape(sb *strings.Builder) error {
+	p.pos++ // backslash
+	if p.eof() {
+		return fmt.Errorf("unterminated escape sequence")
+	}
+	c := p.peek()
+	p.pos++
+	switch c {
+	case 'b':
+		sb.WriteByte('\b')
+	case 't':
+		sb.WriteByte('\t')
+	case 'n':
+		sb.WriteByte('\n')
+	case 'f':
+		sb.WriteByte('\f')
+	case 'r':
+		sb.WriteByte('\r')
+	case 'e':
+		sb.WriteByte(0x1b)
+	case '"':
+		sb.WriteByte('"')
+	case '\\':
+		sb.WriteByte('\\')
+	case 'u', 'U':
+		n := 4
+		if c == 'U' {
+			n = 8
+		}
+		if p.pos+n > len(p.src) {
+			return fmt.Errorf("invalid unicode escape")
+		}
+		code, err := strconv.ParseUint(p.src[p.pos:p.pos+n], 16, 32)
+		if err != nil || !utf8.ValidRune(rune(code)) {
+			return fmt.Errorf("invalid unicode escape %q", p.src[p.pos:p.pos+n])
+		}
+		sb.WriteRune(rune(code))
+		p.pos += n
+	default:
+		return fmt.Errorf("invalid escape sequence \\%c", c)
+	}
+	return nil
+}
+
+// skipBlank skips spaces, tabs and comments, and newlines too when
+// newlines is set
+func (p *parser) skipBlank(newlines bool) {
+	for !p.eof() {
+		switch c := p.peek(); {
+		case c == ' ' || c == '\t':
+			p.pos++
+		case c == '#':
+			for !p.eof() && p.peek() != '\n' {
+				p.pos++
+			}
+		case newlines && (c == '\n' || c == '\r'):
+			if c == '\n' {
+				p.line++
+			}
+			p.pos++
+		default:
+			return
+		}
+	}
+}
+
+func (p *parser) eof() bool  { return p.pos >= len(p.src) }
+func (p *parser) peek() byte { return p.src[p.pos] }
+
+func isBareKeyChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || isDigit(c) || c == '_' || c == '-'
+}
+
+func isDigit(c byte) bool { return c >= '0' && c <= '9' }
@@ -0,0 +1,96 @@
+package toml
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDecode(t *testing.T) {
+	doc := `# Cargo manifest
+[package]
+name = "app"            # trailing comment
+version = "0.1.0"
+rust-version = "1.70"
+authors = [
+  "Jane <jane@example.com>",   # first
+  'Joe',
+]
+
+[dependencies]
+serde = { version = "1.0", features = ["derive"] }
+openssl-sys.version = "0.9"
+
+[workspace]
+members = ["crates/*", "tools/cli"]
+
+[profile.release]
+lto = true
+opt-level = 3
+debug-ratio = 0.5
+
+[[bin]]
+name = "one"
+
+[[bin]]
+name = "two"
+path = """
+src/two.rs"""
+released = 1979-05-27T07:32:00Z
+note = 'C:\path\to'
+escaped = "tab\there \u00e9"
+`
+	got, err := Decode([]byte(doc))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path []string
+		want interface{}
+	}{
+		{[]string{"package", "name"}, "app"},
+		{[]string{"package", "rust-version"}, "1.70"},
+		{[]string{"package", "authors"}, []interface{}{"Jane <jane@example.com>", "Joe"}},
+		{[]string{"dependencies", "serde", "version"}, "1.0"},
+		{[]string{"dependencies", "serde", "features"}, []interface{}{"derive"}},
+		{[]string{"dependencies", "openssl-sys", "version"}, "0.9"},
+		{[]string{"profile", "release", "lto"}, true},
+		{[]string{"profile", "release", "opt-level"}, int64(3)},
+		{[]string{"profile", "release", "debug-ratio"}, 0.5},
+		{[]string{"bin", "name"}, "two"},
+		{[]string{"bin", "path"}, "src/two.rs"},
+		{[]string{"bin", "released"}, "1979-05-27T07:32:00Z"},
+		{[]string{"bin", "note"}, `C:\path\to`},
+		{[]string{"bin", "escaped"}, "tab\there é"},
+		{[]string{"missing", "key"}, nil},
+	}
+	for _, tt := range tests {
+		if v := Lookup(got, tt.path...); !reflect.DeepEqual(v, tt.want) {
+			t.Errorf("Lookup(%v) = %#v, want %#v", tt.path, v, tt.want)
+		}
+	}
+
+	if bins := Tables(got, "bin"); len(bins) != 2 || bins[0]["name"] != "one" {
+		t.Errorf("Expected two [[bin]] tables, got %v", bins)
+	}
+	if members := Strings(got, "workspace", "members"); !reflect.DeepEqual(members, []string{"crates/*", "tools/cli"}) {
+		t.Errorf("Unexpected workspace members %v", members)
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	docs := []string{
+		"key = ",
+		"key = \"unterminated",
+		"[table",
+		"key = [1, 2",
+		"key = { a = 1",
+		"= 1",
+		"a = 1 b = 2",
+	}
+	for _, doc := range docs {
+		if _, err := Decode([]byte(doc)); err == nil {
+			t.Errorf("Decode(%q) expected an error", doc)
+		}
+	}
+}
@@ -0,0 +1,254 @@
+// Package versionpin reads the runtime versions a project pins in version
+// manager files such as .nvmrc, .tool-versions or rust-toolchain.toml.
+package versionpin
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/Sw3bbl3/devdoctor/internal/toml"
+)
+
+// Pin is a runtime version pinned by a file in the project
+type Pin struct {
+	Tool    string // node, python, ruby, go, java, rust, dotnet, maven or gradle
+	Version string // numeric version, possibly partial (e.g. "18" or "3.11")
+	File    string // file the pin was read from
+}
+
+// Files lists the pin files that are read, in order
+var Files = []string{
+	".nvmrc",
+	".node-version",
+	".python-version",
+	".ruby-version",
+	".go-version",
+	".java-version",
+	".sdkmanrc",
+	".tool-versions",
+	"mise.toml",
+	".mise.toml",
+	"rust-toolchain.toml",
+	"rust-toolchain",
+	"global.json",
+}
+
+// singleVersionFiles maps files holding a bare version to their tool
+var singleVersionFiles = map[string]string{
+	".nvmrc":          "node",
+	".node-version":   "node",
+	".python-version": "python",
+	".ruby-version":   "ruby",
+	".go-version":     "go",
+	".java-version":   "java",
+}
+
+// toolAliases maps asdf, mise and SDKMAN tool names to canonical names
+var toolAliases = map[string]string{
+	"node":        "node",
+	"nodejs":      "node",
+	"python":      "python",
+	"ruby":        "ruby",
+	"go":          "go",
+	"golang":      "go",
+	"java":        "java",
+	"rust":        "rust",
+	"dotnet":      "dotnet",
+	"dotnet-core": "dotnet",
+	"maven":       "maven",
+	"gradle":      "gradle",
+}
+
+var versionRe = regexp.MustCompile(`^\d+(\.\d+)*`)
+
+// Find reads every pin file in path and returns the pins they declare.
+// Pins that are not numeric versions (lts/*, stable, system, ...) are skipped.
+func Find(path string) []Pin {
+	pins := []Pin{}
+	for _, file := range Files {
+		data, err := os.ReadFile(filepath.Join(path, file))
+		if err != nil {
+			continue
+		}
+		for _, pin := range parse(file, data) {
+			if pin.Version = cleanVersion(pin.Version); pin.Version != "" {
+				pin.File = file
+				pins = append(pins, pin)
+			}
+		}
+	}
+	return pins
+}
+
+func parse(file string, data []byte) []Pin {
+	if tool, ok := singleVersionFiles[file]; ok {
+		// pyenv allows several versions, one per line; the first one wins
+		line := strings.TrimSpace(firstLine(string(data)))
+		return []Pin{{Tool: tool, Version: line}}
+	}
+
+	switch file {
+	case ".sdkmanrc":
+		return parseKeyValues(data, "=")
+	case ".tool-versions":
+		return parseKeyValues(data, " ")
+	case "mise.toml", ".mise.toml":
+		return parseMise(data)
+	case "rust-toolchain", "rust-toolchain.toml":
+		return parseRustToolchain(data)
+	case "global.json":
+		var global struct {
+			SDK struct {
+				Version string `json:"version"`
+			} `json:"sdk"`
+		}
+		if json.Unmarshal(data, &global) == nil && global.SDK.Version != "" {
+			return []Pin{{Tool: "dotnet", Version: global.SDK.Version}}
+		}
+	}
+	return nil
+}
+
+// parseKeyValues parses "tool<sep>version" lines (.sdkmanrc, .tool-versions)
+func parseKeyValues(data []byte, sep string) []Pin {
+	pins := []Pin{}
+	scanner := bufio.NewScanner(strings.NewReader(string(data)))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = strings.TrimSpace(line[:i])
+		}
+		name, value, ok := strings.Cut(line, sep)
+		if !ok {
+			continue
+		}
+		tool, known := toolAliases[strings.TrimSpace(name)]
+		if !known {
+			continue
+		}
+		// .tool-versions may list fallback versions after the first one
+		if fields := strings.Fields(value); len(fields) > 0 {
+			pins = append(pins, Pin{Tool: tool, Version: fields[0]})
+		}
+	}
+	return pins
+}
+
+func parseMise(data []byte) []Pin {
+	doc, err := toml.Decode(data)
+	if err != nil {
+		return nil
+	}
+	pins := []Pin{}
+	for name, value := range toml.Table(doc, "tools") {
+		tool, known := toolAliases[name]
+		if !known {
+			continue
+		}
+		version := ""
+		switch v := value.(type) {
+		case string:
+			version = v
+		case []interface{}:
+			if len(v) > 0 {
+				version, _ = v[0].(string)
+			}
+		case map[string]interface{}:
+			version, _ = v["version"].(string)
+		}
+		pins = append(pins, Pin{Tool: tool, Version: version})
+	}
+	sort.Slice(pins, func(i, j int) bool { return pins[i].Tool < pins[j].Tool })
+	return pins
+}
+
+func parseRustToolchain(data []byte) []Pin {
+	// The legacy file holds just the channel name
+	if doc, err := toml.Decode(data); err == nil {
+		if channel := toml.String(doc, "toolchain", "channel"); channel != "" {
+			return []Pin{{Tool: "rust", Version: channel}}
+		}
+	}
+	if line := strings.TrimSpace(firstLine(string(data))); line != "" && !strings.HasPrefix(line, "[") {
+		return []Pin{{Tool: "rust", Version: line}}
+	}
+	return nil
+}
+
+// vendorPrefixes are the distribution names a pin may put before the
+// version of the tool itself, as in temurin-17 or ruby-3.2.2. Other
+// prefixes name a channel or another implementation (nightly-2024-01-01,
+// pypy3.9-7.3.11, anaconda3-2023.09) whose version is not the tool's.
+var vendorPrefixes = map[string]bool{
+	"temurin": true, "openjdk": true, "corretto": true, "zulu": true, "adoptopenjdk": true,
+	"liberica": true, "ruby": true, "python": true,
+}
+
+// cleanVersion extracts the numeric version from values such as "v18.17.1",
+// "ruby-3.2.2", "temurin-17.0.8+7", "17.0.2-tem" or "1.75.0-x86_64". It
+// returns "" for aliases like "lts/*", "stable" or "system" and for
+// channels and alternative implementations.
+func cleanVersion(v string) string {
+	v = strings.TrimSpace(v)
+	v = strings.TrimPrefix(v, "v")
+	if m := versionRe.FindString(v); m != "" {
+		return m
+	}
+	if prefix, rest, ok := strings.Cut(v, "-"); ok && vendorPrefixes[strings.ToLower(prefix)] {
+		return versionRe.FindString(rest)
+	}
+	return ""
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+	return line
+}
+
+// Matches reports whether the installed version satisfies the pin. Every
+// component the pin specifies must be equal, so "18" matches "18.17.1".
+// Java "1.8" style versions are treated as "8".
+func Matches(pin Pin, installed string) bool {
+	want := strings.Split(normalize(pin.Tool, pin.Version), ".")
+	got := strings.Split(normalize(pin.Tool, installed), ".")
+	if len(got) < len(want) {
+		return false
+	}
+	if pin.Tool == "dotnet" && len(want) == 3 {
+		// global.json rolls forward to the latest patch of the same
+		// feature band by default: 8.0.100 accepts 8.0.1xx
+		wantPatch, gotPatch := atoi(want[2]), atoi(got[2])
+		return want[0] == got[0] && want[1] == got[1] &&
+			gotPatch/100 == wantPatch/100 && gotPatch >= wantPatch
+	}
+	for i := range want {
+		if strings.TrimLeft(want[i], "0") != strings.TrimLeft(got[i], "0") {
+			return false
+		}
+	}
+	return true
+}
+
+func atoi(s string) int {
+	n := 0
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			break
+		}
+		n = n*10 + int(c-'0')
+	}
+	return n
+}
+
+func normalize(tool, version string) string {
+	version = cleanVersion(version)
+	if tool == "java" && strings.HasPrefix(version, "1.") {
+		version = strings.TrimPrefix(version, "1.")
+	}
+	return version
+}
@@ -0,0 +1,133 @@
+package versionpin
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFind(t *testing.T) {
+	tmpDir := t.TempDir()
+	files := map[string]string{
+		".nvmrc":              "v18.17.1\n",
+		".python-version":     "3.11.4\n3.10.12\n",
+		".ruby-version":       "ruby-3.2.2",
+		".java-version":       "lts/*",
+		".sdkmanrc":           "# SDKMAN\njava=17.0.8-tem\nmaven=3.9.6\n",
+		".tool-versions":      "nodejs 20.10.0\ngolang 1.21.5 # comment\nerlang 26.1\njava temurin-21.0.1+12.0.LTS\n",
+		"mise.toml":           "[tools]\npython = [\"3.12\", \"3.11\"]\nnode = { version = \"22\" }\nterraform = \"1.6\"\n",
+		"rust-toolchain.toml": "[toolchain]\nchannel = \"1.75.0\"\ncomponents = [\"clippy\"]\n",
+		"global.json":         `{"sdk": {"version": "8.0.100", "rollForward": "latestFeature"}}`,
+	}
+	for file, content := range files {
+		if err := os.WriteFile(filepath.Join(tmpDir, file), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want := []Pin{
+		{"node", "18.17.1", ".nvmrc"},
+		{"python", "3.11.4", ".python-version"},
+		{"ruby", "3.2.2", ".ruby-version"},
+		{"java", "17.0.8", ".sdkmanrc"},
+		{"maven", "3.9.6", ".sdkmanrc"},
+		{"node", "20.10.0", ".tool-versions"},
+		{"go", "1.21.5", ".tool-versions"},
+		{"java", "21.0.1", ".tool-versions"},
+		{"node", "22", "mise.toml"},
+		{"python", "3.12", "mise.toml"},
+		{"rust", "1.75.0", "rust-toolchain.toml"},
+		{"dotnet", "8.0.100", "global.json"},
+	}
+
+	got := Find(tmpDir)
+	if len(got) != len(want) {
+		t.Fatalf("Expected %d pins, got %d: %v", len(want), len(got), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Pin %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestFindLegacyRustToolchain(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmpDir, "rust-toolchain"), []byte("1.70.0\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	pins := Find(tmpDir)
+	if len(pins) != 1 || pins[0].Version != "1.70.0" {
+		t.Errorf("Unexpected pins %v", pins)
+	}
+
+	if err := os.WriteFile(filepath.Join(tmpDir, "rust-toolchain"), []byte("stable\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if pins := Find(tmpDir); len(pins) != 0 {
+		t.Errorf("Expected channel names to be skipped, got %v", pins)
+	}
+}
+
+func TestCleanVersion(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"v18.17.1", "18.17.1"},
+		{"ruby-3.2.2", "3.2.2"},
+		{"temurin-17.0.8+7", "17.0.8"},
+		{"corretto-21.0.1.12.1", "21.0.1.12.1"},
+		{"17.0.2-tem", "17.0.2"},
+		{"1.75.0-x86_64-unknown-linux-gnu", "1.75.0"},
+		{"nightly-2024-01-01", ""},
+		{"beta-2024-02-08", ""},
+		{"pypy3.9-7.3.11", ""},
+		{"anaconda3-2023.09", ""},
+		{"jruby-9.4.5.0", ""},
+		{"lts/*", ""},
+		{"stable", ""},
+	}
+	for _, tt := range tests {
+		if got := cleanVersion(tt.in); got != tt.want {
+			t.Errorf("cleanVersion(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestFindNightlyRustToolchain(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmpDir, "rust-toolchain.toml"), []byte("[toolchain]\nchannel = \"nightly-2024-01-01\"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if pins := Find(tmpDir); len(pins) != 0 {
+		t.Errorf("Expected a dated nightly to be skipped, got %v", pins)
+	}
+}
+
+func TestMatches(t *testing.T) {
+	tests := []struct {
+		pin       Pin
+		installed string
+		want      bool
+	}{
+		{Pin{Tool: "node", Version: "18"}, "18.17.1", true},
+		{Pin{Tool: "node", Version: "18.17.1"}, "18.17.1", true},
+		{Pin{Tool: "node", Version: "18.17.1"}, "18.17.0", false},
+		{Pin{Tool: "node", Version: "18"}, "20.10.0", false},
+		{Pin{Tool: "python", Version: "3.11"}, "3.11.4", true},
+		{Pin{Tool: "python", Version: "3.1"}, "3.11.4", false},
+		{Pin{Tool: "java", Version: "8"}, "1.8.0_292", true},
+		{Pin{Tool: "java", Version: "1.8"}, "1.8.0_292", true},
+		{Pin{Tool: "java", Version: "17"}, "21.0.1", false},
+		{Pin{Tool: "dotnet", Version: "8.0.100"}, "8.0.104", true},
+		{Pin{Tool: "dotnet", Version: "8.0.100"}, "8.0.204", false},
+		{Pin{Tool: "dotnet", Version: "8.0.105"}, "8.0.104", false},
+	}
+
+	for _, tt := range tests {
+		if got := Matches(tt.pin, tt.installed); got != tt.want {
+			t.Errorf("Matches(%v, %s) = %v, want %v", tt.pin, tt.installed, got, tt.want)
+		}
+	}
+}
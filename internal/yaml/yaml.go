@@ -0,0 +1,715 @@
+// Package yaml implements a small YAML decoder covering the subset of the
+// format used by lockfiles and tool configuration (pnpm-lock.yaml, compose
+// files, pubspec.yaml, stack.yaml): block and flow collections, plain and
+// quoted scalars, block scalars, anchors, aliases and merge keys.
+package yaml
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Decode parses the first document of data. Mappings decode to
+// map[string]interface{}, sequences to []interface{} and scalars to string;
+// null values decode to nil. Scalars are not converted to numbers or
+// booleans.
+func Decode(data []byte) (interface{}, error) {
+	docs, err := DecodeAll(data)
+	if err != nil || len(docs) == 0 {
+		return nil, err
+	}
+	return docs[0], nil
+}
+
+// DecodeAll parses every document of a multi-document stream
+func DecodeAll(data []byte) ([]interface{}, error) {
+	docs := []interface{}{}
+	for _, doc := range splitDocuments(string(data)) {
+		p := &parser{lines: doc, anchors: map[string]interface{}{}}
+		value, err := p.parseDocument()
+		if err != nil {
+			return nil, err
+		}
+		docs = append(docs, value)
+	}
+	return docs, nil
+}
+
+// Map returns v as a mapping, or nil
+func Map(v interface{}) map[string]interface{} {
+	m, _ := v.(map[string]interface{})
+	return m
+}
+
+// List returns v as a sequence, or nil
+func List(v interface{}) []interface{} {
+	l, _ := v.([]interface{})
+	return l
+}
+
+// String returns v as a scalar, or ""
+func String(v interface{}) string {
+	s, _ := v.(string)
+	return s
+}
+
+// Lookup follows a path of mapping keys from v and returns the value found,
+// or nil
+func Lookup(v interface{}, path ...string) interface{} {
+	for _, key := range path {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		v = m[key]
+	}
+	return v
+}
+
+// Keys returns the keys of a mapping in sorted order
+func Keys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+type line struct {
+	num    int    // 1-based line number
+	indent int    // leading spaces
+	text   string // content without indentation, comments kept
+}
+
+func splitDocuments(src string) [][]line {
+	src = strings.TrimPrefix(src, "\ufeff")
+	docs := [][]line{}
+	current := []line{}
+	hasContent := false
+	for i, raw := range strings.Split(strings.ReplaceAll(src, "\r\n", "\n"), "\n") {
+		trimmed := strings.TrimLeft(raw, " ")
+		if strings.HasPrefix(raw, "---") && (len(raw) == 3 || raw[3] == ' ') {
+			if hasContent {
+				docs = append(docs, current)
+			}
+			current, hasContent = []line{}, false
+			if rest := strings.TrimSpace(raw[3:]); rest != "" && !strings.HasPrefix(rest, "#") {
+				current = append(current, line{num: i + 1, text: rest})
+				hasContent = true
+			}
+			continue
+		}
+		if raw == "..." || strings.HasPrefix(trimmed, "%") && len(current) == 0 {
+			continue
+		}
+		current = append(current, line{num: i + 1, indent: len(raw) - len(trimmed), text: strings.TrimRight(trimmed, " \t")})
+		if !isBlank(trimmed) {
+			hasContent = true
+		}
+	}
+	if hasContent {
+		docs = append(docs, current)
+	}
+	return docs
+}
+
+func isBlank(text string) bool {
+	text = strings.TrimSpace(text)
+	return text == "" || strings.HasPrefix(text, "#")
+}
+
+type parser struct {
+	lines   []line
+	pos     int
+	anchors map[string]interface{}
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	num := 0
+	if p.pos < len(p.lines) {
+		num = p.lines[p.pos].num
+	} else if len(p.lines) > 0 {
+		num = p.lines[len(p.lines)-1].num
+	}
+	return fmt.Errorf("yaml: line %d: %s", num, fmt.Sprintf(format, args...))
+}
+
+// next skips blank and comment lines and returns the next content line
+func (p *parser) next() (line, bool) {
+	for p.pos < len(p.lines) {
+		if !isBlank(p.lines[p.pos].text) {
+			return p.lines[p.pos], true
+		}
+		p.pos++
+	}
+	return line{}, false
+}
+
+func (p *parser) parseDocument() (interface{}, error) {
+	l, ok := p.next()
+	if !ok {
+		return nil, nil
+	}
+	value, err := p.parseBlock(l.indent)
+	if err != nil {
+		return nil, err
+	}
+	if l, ok := p.next(); ok {
+		return nil, p.errorf("unexpected content %q", l.text)
+	}
+	return value, nil
+}
+
+// parseBlock parses the node starting at the current line, which is
+// indented by indent
+func (p *parser) parseBlock(indent int) (interface{}, error) {
+	l, _ := p.next()
+	text := stripComment(l.text)
+	if isSequenceItem(text) {
+		return p.parseSequence(indent)
+	}
+	if _, _, ok := splitKey(text); ok {
+		return p.parseMapping(indent)
+	}
+	// A scalar or flow collection, possibly continued on following lines
+	p.pos++
+	return p.parseValue(text, indent-1)
+}
+
+func isSequenceItem(text string) bool {
+	return text == "-" || strings.HasPrefix(text, "- ")
+}
+
+func (p *parser) parseSequence(indent int) (interface{}, error) {
+	items := []interface{}{}
+	for {
+		l, ok := p.next()
+		if !ok || l.indent != indent || !isSequenceItem(stripComment(l.text)) {
+			return items, nil
+		}
+		content := strings.TrimLeft(l.text[1:], " ")
+		if isBlank(content) {
+			p.pos++
+			next, ok := p.next()
+			if ok && next.indent > indent {
+				item, err := p.parseBlock(next.indent)
+				if err != nil {
+					return nil, err
+				}
+				items = append(items, item)
+			} else {
+				items = append(items, nil)
+			}
+			continue
+		}
+
+		// Re-read the item content as a line of its own at its column so
+		// that "- key: value" continues with keys aligned to "key"
+		column := l.indent + len(l.text) - len(content)
+		p.lines[p.pos] = line{num: l.num, indent: column, text: content}
+		anchor := ""
+		if strings.HasPrefix(content, "&") {
+			name, rest, _ := strings.Cut(content[1:], " ")
+			anchor = name
+			rest = strings.TrimLeft(rest, " ")
+			if isBlank(rest) {
+				p.pos++
+				next, ok := p.next()
+				if !ok || next.indent <= indent {
+					p.anchors[anchor] = nil
+					items = append(items, nil)
+					continue
+				}
+				column = next.indent
+			} else {
+				column += len(content) - len(rest)
+				p.lines[p.pos] = line{num: l.num, indent: column, text: rest}
+			}
+		}
+		item, err := p.parseBlock(column)
+		if err != nil {
+			return nil, err
+		}
+		if anchor != "" {
+			p.anchors[anchor] = item
+		}
+		items = append(items, item)
+	}
+}
+
+func (p *parser) parseMapping(indent int) (interface{}, error) {
+	m := map[string]interface{}{}
+	merges := []map[string]interface{}{}
+	for {
+		l, ok := p.next()
+		if !ok || l.indent != indent {
+			break
+		}
+		text := stripComment(l.text)
+		if isSequenceItem(text) {
+			break
+		}
+		key, rest, ok := splitKey(text)
+		if !ok {
+			return nil, p.errorf("expected a mapping key, got %q", text)
+		}
+		p.pos++
+
+		value, err := p.parseMappingValue(rest, indent)
+		if err != nil {
+			return nil, err
+		}
+		if key == "<<" {
+			switch v := value.(type) {
+			case map[string]interface{}:
+				merges = append(merges, v)
+			case []interface{}:
+				for _, item := range v {
+					if mv, ok := item.(map[string]interface{}); ok {
+						merges = append(merges, mv)
+					}
+				}
+			}
+			continue
+		}
+		m[key] = value
+	}
+
+	// Explicit keys win over merged ones, earlier merges over later ones
+	for _, merge := range merges {
+		for key, value := range merge {
+			if _, exists := m[key]; !exists {
+				m[key] = value
+			}
+		}
+	}
+	return m, nil
+}
+
+// parseMappingValue parses the value that follows "key:" on a line of a
+// mapping indented by indent
+func (p *parser) parseMappingValue(rest string, indent int) (interface{}, error) {
+	anchor := ""
+	rest = stripTag(rest)
+	if strings.HasPrefix(rest, "&") {
+		name, after, _ := strings.Cut(rest[1:], " ")
+		anchor, rest = name, stripTag(strings.TrimSpace(after))
+	}
+
+	var value interface{}
+	var err error
+	switch {
+	case rest == "":
+		next, ok := p.next()
+		if ok && (next.indent > indent || next.indent == indent && isSequenceItem(stripComment(next.text))) {
+			value, err = p.parseBlock(next.indent)
+		}
+	case rest[0] == '|' || rest[0] == '>':
+		value, err = p.parseBlockScalar(rest, indent)
+	default:
+		value, err = p.parseValue(rest, indent)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if anchor != "" {
+		p.anchors[anchor] = value
+	}
+	return value, nil
+}
+
+// parseValue parses an inline value whose continuation lines, if any, are
+// indented more than indent
+func (p *parser) parseValue(text string, indent int) (interface{}, error) {
+	// Gather continuation lines of flow collections, quoted and plain scalars
+	for {
+		l, ok := p.next()
+		unclosed := (strings.HasPrefix(text, "[") || strings.HasPrefix(text, "{")) && !flowBalanced(text)
+		if !ok || l.indent <= indent && !unclosed || !needsContinuation(text, l) {
+			break
+		}
+		text += " " + stripComment(l.text)
+		p.pos++
+	}
+
+	switch {
+	case strings.HasPrefix(text, "*"):
+		name := strings.TrimSpace(text[1:])
+		value, ok := p.anchors[name]
+		if !ok {
+			return nil, p.errorf("unknown alias %q", name)
+		}
+		return value, nil
+	case strings.HasPrefix(text, "[") || strings.HasPrefix(text, "{"):
+		f := &flowParser{src: text, anchors: p.anchors}
+		value, err := f.parse()
+		if err != nil {
+			return nil, p.errorf("%v", err)
+		}
+		return value, nil
+	case strings.HasPrefix(text, `"`), strings.HasPrefix(text, "'"):
+		s, rest, err := parseQuoted(text)
+		if err != nil {
+			return nil, p.errorf("%v", err)
+		}
+		if strings.TrimSpace(rest) != "" {
+			return nil, p.errorf("unexpected %q after quoted string", rest)
+		}
+		return s, nil
+	}
+	return plainScalar(text), nil
+}
+
+// needsContinuation reports whether an inline value continues on line l
+func needsContinuation(text string, l line) bool {
+	switch {
+	case strings.HasPrefix(text, "[") || strings.HasPrefix(text, "{"):
+		return !flowBalanced(text)
+	case strings.HasPrefix(text, `"`) || strings.HasPrefix(text, "'"):
+		_, _, err := parseQuoted(text)
+		return err != nil
+	}
+	// Multi-line plain scalar; a nested key or item ends it
+	content := stripComment(l.text)
+	if _, _, isKey := splitKey(content); isKey || isSequenceItem(content) {
+		return false
+	}
+	return true
+}
+
+func (p *parser) parseBlockScalar(header string, indent int) (interface{}, error) {
+	folded := header[0] == '>'
+	chomp := ""
+	if strings.Contains(header, "-") {
+		chomp = "-"
+	} else if strings.Contains(header, "+") {
+		chomp = "+"
+	}
+
+	lines := []string{}
+	blockIndent := -1
+	for p.pos < len(p.lines) {
+		l := p.lines[p.pos]
+		if strings.TrimSpace(l.text) == "" {
+			lines = append(lines, "")
+			p.pos++
+			continue
+		}
+		if l.indent <= indent {
+			break
+		}
+		if blockIndent < 0 {
+			blockIndent = l.indent
+		}
+		if l.indent < blockIndent {
+			break
+		}
+		lines = append(lines, strings.Repeat(" ", l.indent-blockIndent)+l.text)
+		p.pos++
+	}
+
+	// Trailing blank lines belong to chomping, not content
+	trailing := 0
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+		trailing++
+	}
+
+	var sb strings.Builder
+	for i, l := range lines {
+		if i > 0 {
+			prev := lines[i-1]
+			if folded && l != "" && prev != "" && !strings.HasPrefix(l, " ") && !strings.HasPrefix(prev, " ") {
+				sb.WriteByte(' ')
+			} else {
+				sb.WriteByte('\n')
+			}
+		}
+		sb.WriteString(l)
+	}
+	s := sb.String()
+	switch chomp {
+	case "":
+		if len(lines) > 0 {
+			s += "\n"
+		}
+	case "+":
+		s += strings.Repeat("\n", trailing+1)
+	}
+	return s, nil
+}
+
+// splitKey splits "key: value" and reports whether text is a mapping entry
+func splitKey(text string) (string, string, bool) {
+	if text == "" || text[0] == '[' || text[0] == '{' || text[0] == '-' && isSequenceItem(text) {
+		return "", "", false
+	}
+	if text[0] == '"' || text[0] == '\'' {
+		key, rest, err := parseQuoted(text)
+		if err != nil || !strings.HasPrefix(rest, ":") {
+			return "", "", false
+		}
+		rest = rest[1:]
+		if rest != "" && rest[0] != ' ' && rest[0] != '\t' {
+			return "", "", false
+		}
+		return key, strings.TrimSpace(rest), true
+	}
+	for i := 0; i < len(text); i++ {
+		if text[i] == ':' && (i+1 == len(text) || text[i+1] == ' ' || text[i+1] == '\t') {
+			return strings.TrimSpace(text[:i]), strings.TrimSpace(text[i+1:]), true
+		}
+	}
+	return "", "", false
+}
+
+// stripComment removes a trailing comment outside of quotes
+func stripComment(text string) string {
+	var quote byte
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case quote != 0:
+			if c == '\\' && quote == '"' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			if i == 0 || strings.ContainsRune(" \t[{,:", rune(text[i-1])) {
+				quote = c
+			}
+		case c == '#' && (i == 0 || text[i-1] == ' ' || text[i-1] == '\t'):
+			return strings.TrimRight(text[:i], " \t")
+		}
+	}
+	return text
+}
+
+// stripTag drops a leading tag such as !!str or !reset
+func stripTag(text string) string {
+	if strings.HasPrefix(text, "!") {
+		_, rest, _ := strings.Cut(text, " ")
+		return strings.TrimSpace(rest)
+	}
+	return text
+}
+
+func plainScalar(text string) interface{} {
+	text = strings.TrimSpace(text)
+	switch text {
+	case "", "~", "null", "Null", "NULL":
+		return nil
+	}
+	return text
+}
+
+// parseQuoted parses a quoted scalar at the start of text and returns the
+// string and the remaining text
+func parseQuoted(text string) (string, string, error) {
+	quote := text[0]
+	var sb strings.Builder
+	for i := 1; i < len(text); i++ {
+		c := text[i]
+		if quote == '\'' {
+			if c == '\'' {
+				if i+1 < len(text) && text[i+1] == '\'' {
+					sb.WriteByte('\'')
+					i++
+					continue
+				}
+				return sb.String(), text[i+1:], nil
+			}
+			sb.WriteByte(c)
+			continue
+		}
+		switch c {
+		case '"':
+			return sb.String(), text[i+1:], nil
+		case '\\':
+			if i+1 >= len(text) {
+				return "", "", fmt.Errorf("unterminated escape")
+			}
+			i++
+			switch e := text[i]; e {
+			case 'n':
+				sb.WriteByte('\n')
+			case 't':
+				sb.WriteByte('\t')
+			case 'r':
+				sb.WriteByte('\r')
+			case '0':
+				sb.WriteByte(0)
+			case 'x', 'u', 'U':
+				n := map[byte]int{'x': 2, 'u': 4, 'U': 8}[e]
+				if i+n >= len(text) {
+					return "", "", fmt.Errorf("invalid escape")
+				}
+				code, err := strconv.ParseUint(text[i+1:i+1+n], 16, 32)
+				if err != nil {
+					return "", "", fmt.Errorf("invalid escape")
+				}
+				sb.WriteRune(rune(code))
+				i += n
+			default:
+				sb.WriteByte(e)
+			}
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return "", "", fmt.Errorf("unterminated quoted string")
+}
+
+func flowBalanced(text string) bool {
+	depth := 0
+	var quote byte
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case quote != 0:
+			if c == '\\' && quote == '"' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '[' || c == '{':
+			depth++
+		case c == ']' || c == '}':
+			depth--
+		}
+	}
+	return depth <= 0 && quote == 0
+}
+
+// flowParser parses flow collections such as [a, "b"] and {a: 1, b: [x]}
+type flowParser struct {
+	src     string
+	pos     int
+	anchors map[string]interface{}
+}
+
+func (f *flowParser) parse() (interface{}, error) {
+	value, err := f.value()
+	if err != nil {
+		return nil, err
+	}
+	f.skipSpace()
+	if f.pos < len(f.src) {
+		return nil, fmt.Errorf("unexpected %q after flow collection", f.src[f.pos:])
+	}
+	return value, nil
+}
+
+func (f *flowParser) skipSpace() {
+	for f.pos < len(f.src) && (f.src[f.pos] == ' ' || f.src[f.pos] == '\t') {
+		f.pos++
+	}
+}
+
+func (f *flowParser) value() (interface{}, error) {
+	f.skipSpace()
+	if f.pos >= len(f.src) {
+		return nil, fmt.Errorf("unexpected end of flow collection")
+	}
+	switch c := f.src[f.pos]; c {
+	case '[':
+		f.pos++
+		items := []interface{}{}
+		for {
+			f.skipSpace()
+			if f.pos < len(f.src) && f.src[f.pos] == ']' {
+				f.pos++
+				return items, nil
+			}
+			item, err := f.value()
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+			if err := f.separator(']'); err != nil {
+				return nil, err
+			}
+		}
+	case '{':
+		f.pos++
+		m := map[string]interface{}{}
+		for {
+			f.skipSpace()
+			if f.pos < len(f.src) && f.src[f.pos] == '}' {
+				f.pos++
+				return m, nil
+			}
+			key, err := f.value()
+			if err != nil {
+				return nil, err
+			}
+			f.skipSpace()
+			var value interface{}
+			if f.pos < len(f.src) && f.src[f.pos] == ':' {
+				f.pos++
+				f.skipSpace()
+				if f.pos < len(f.src) && f.src[f.pos] != ',' && f.src[f.pos] != '}' {
+					if value, err = f.value(); err != nil {
+						return nil, err
+					}
+				}
+			}
+			m[fmt.Sprint(key)] = value
+			if err := f.separator('}'); err != nil {
+				return nil, err
+			}
+		}
+	case '"', '\'':
+		s, rest, err := parseQuoted(f.src[f.pos:])
+		if err != nil {
+			return nil, err
+		}
+		f.pos = len(f.src) - len(rest)
+		return s, nil
+	case '*':
+		start := f.pos + 1
+		for f.pos < len(f.src) && !strings.ContainsRune(",]} ", rune(f.src[f.pos])) {
+			f.pos++
+		}
+		value, ok := f.anchors[f.src[start:f.pos]]
+		if !ok {
+			return nil, fmt.Errorf("unknown alias %q", f.src[start:f.pos])
+		}
+		return value, nil
+	}
+
+	// Plain scalar: ends at a flow indicator or at ": "
+	start := f.pos
+	for f.pos < len(f.src) {
+		c := f.src[f.pos]
+		if c == ',' || c == ']' || c == '}' {
+			break
+		}
+		if c == ':' && (f.pos+1 == len(f.src) || strings.ContainsRune(" ,]}", rune(f.src[f.pos+1]))) {
+			break
+		}
+		f.pos++
+	}
+	return plainScalar(f.src[start:f.pos]), nil
+}
+
+func (f *flowParser) separator(closing byte) error {
+	f.skipSpace()
+	if f.pos >= len(f.src) {
+		return fmt.Errorf("unterminated flow collection")
+	}
+	switch f.src[f.pos] {
+	case ',':
+		f.pos++
+		return nil
+	case closing:
+		return nil
+	}
+	return fmt.Errorf("expected ',' or %q, got %q", closing, f.src[f.pos])
+}
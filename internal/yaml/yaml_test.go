@@ -0,0 +1,140 @@
+package yaml
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDecode(t *testing.T) {
+	doc := `# compose file
+x-common: &common
+  restart: unless-stopped
+  environment:
+    - TZ=UTC
+
+services:
+  web:
+    <<: *common
+    image: "nginx:1.25"   # pinned
+    ports:
+    - "8080:80"
+    - 127.0.0.1:8443:443
+    command: >
+      nginx -g
+      'daemon off;'
+    healthcheck: {test: [CMD, curl, -f, "http://localhost"], interval: 30s}
+  db:
+    <<: [*common]
+    restart: always
+    image: postgres
+    environment:
+      POSTGRES_PASSWORD: 'it''s secret'
+      EMPTY:
+    script: |
+      echo one
+        echo two
+    volumes: [
+      data:/var/lib/postgresql/data,
+      ./init:/docker-entrypoint-initdb.d
+    ]
+    tags:
+      - name: first
+        weight: 1
+      - &second
+        name: second
+    long: this value
+      spans lines
+`
+	got, err := Decode([]byte(doc))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path []string
+		want interface{}
+	}{
+		{[]string{"services", "web", "restart"}, "unless-stopped"},
+		{[]string{"services", "web", "environment"}, []interface{}{"TZ=UTC"}},
+		{[]string{"services", "web", "image"}, "nginx:1.25"},
+		{[]string{"services", "web", "ports"}, []interface{}{"8080:80", "127.0.0.1:8443:443"}},
+		{[]string{"services", "web", "command"}, "nginx -g 'daemon off;'\n"},
+		{[]string{"services", "web", "healthcheck", "test"}, []interface{}{"CMD", "curl", "-f", "http://localhost"}},
+		{[]string{"services", "web", "healthcheck", "interval"}, "30s"},
+		{[]string{"services", "db", "restart"}, "always"},
+		{[]string{"services", "db", "environment", "POSTGRES_PASSWORD"}, "it's secret"},
+		{[]string{"services", "db", "environment", "EMPTY"}, nil},
+		{[]string{"services", "db", "script"}, "echo one\n  echo two\n"},
+		{[]string{"services", "db", "volumes"}, []interface{}{"data:/var/lib/postgresql/data", "./init:/docker-entrypoint-initdb.d"}},
+		{[]string{"services", "db", "long"}, "this value spans lines"},
+	}
+	for _, tt := range tests {
+		if v := Lookup(got, tt.path...); !reflect.DeepEqual(v, tt.want) {
+			t.Errorf("Lookup(%v) = %#v, want %#v", tt.path, v, tt.want)
+		}
+	}
+
+	tags := List(Lookup(got, "services", "db", "tags"))
+	if len(tags) != 2 || String(Lookup(tags[0], "weight")) != "1" || String(Lookup(tags[1], "name")) != "second" {
+		t.Errorf("tags = %#v", tags)
+	}
+}
+
+func TestDecodeLockfileKeys(t *testing.T) {
+	doc := `lockfileVersion: '9.0'
+
+importers:
+
+  .:
+    dependencies:
+      '@babel/core':
+        specifier: ^7.22.0
+        version: 7.22.5(supports-color@8.1.1)
+
+packages:
+
+  /@babel/core@7.22.5:
+    resolution: {integrity: sha512-abc==}
+`
+	got, err := Decode([]byte(doc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := String(Lookup(got, "lockfileVersion")); v != "9.0" {
+		t.Errorf("lockfileVersion = %q", v)
+	}
+	if v := String(Lookup(got, "importers", ".", "dependencies", "@babel/core", "version")); v != "7.22.5(supports-color@8.1.1)" {
+		t.Errorf("version = %q", v)
+	}
+	if v := String(Lookup(got, "packages", "/@babel/core@7.22.5", "resolution", "integrity")); v != "sha512-abc==" {
+		t.Errorf("integrity = %q", v)
+	}
+}
+
+func TestDecodeAll(t *testing.T) {
+	docs, err := DecodeAll([]byte("---\na: 1\n---\n- x\n- y\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []interface{}{
+		map[string]interface{}{"a": "1"},
+		[]interface{}{"x", "y"},
+	}
+	if !reflect.DeepEqual(docs, want) {
+		t.Errorf("DecodeAll = %#v, want %#v", docs, want)
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	tests := []string{
+		"a: [1, 2\n",
+		"a: \"open\n",
+		"a: *missing\n",
+		"a: 1\n  - b\n",
+	}
+	for _, doc := range tests {
+		if _, err := Decode([]byte(doc)); err == nil {
+			t.Errorf("Decode(%q) succeeded, want error", doc)
+		}
+	}
+}